@@ -28,6 +28,8 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var calculateURL string
+
 // calculateCmd represents the calculate command
 var calculateCmd = &cobra.Command{
 	Use:   "calculate",
@@ -38,11 +40,12 @@ var calculateCmd = &cobra.Command{
 				- wsdl-example calculate subtract 5 2
 				- wsdl-example calculate devine 4 2
 				- wsdl-example calculate multiply 2 7
+				- wsdl-example calculate --url http://localhost:8080 add 1 5
 		`,
 	Run: func(cmd *cobra.Command, args []string) {
 		if len(args) == 3 {
 			var err error
-			service := calculator.NewCalculatorSoap("", false, nil)
+			service := calculator.NewCalculatorSoap(calculateURL, false, nil)
 			intA, err := strconv.ParseInt(args[1], 10, 32)
 			intB, err := strconv.ParseInt(args[2], 10, 32)
 			var result int32
@@ -50,22 +53,30 @@ var calculateCmd = &cobra.Command{
 			case "add":
 				resp, rErr := service.Add(&calculator.Add{IntA: int32(intA), IntB: int32(intB)})
 				err = rErr
-				result = resp.AddResult
+				if err == nil {
+					result = resp.AddResult
+				}
 				break
 			case "subtract":
 				resp, rErr := service.Subtract(&calculator.Subtract{IntA: int32(intA), IntB: int32(intB)})
 				err = rErr
-				result = resp.SubtractResult
+				if err == nil {
+					result = resp.SubtractResult
+				}
 				break
 			case "devine":
 				resp, rErr := service.Divide(&calculator.Divide{IntA: int32(intA), IntB: int32(intB)})
 				err = rErr
-				result = resp.DivideResult
+				if err == nil {
+					result = resp.DivideResult
+				}
 				break
 			case "multiply":
 				resp, rErr := service.Multiply(&calculator.Multiply{IntA: int32(intA), IntB: int32(intB)})
 				err = rErr
-				result = resp.MultiplyResult
+				if err == nil {
+					result = resp.MultiplyResult
+				}
 				break
 			default:
 				fmt.Println("Invalid method " + args[0])
@@ -85,6 +96,8 @@ var calculateCmd = &cobra.Command{
 func init() {
 	rootCmd.AddCommand(calculateCmd)
 
+	calculateCmd.Flags().StringVar(&calculateURL, "url", "", "endpoint of the service (default is http://www.dneonline.com/calculator.asmx)")
+
 	// Here you will define your flags and configuration settings.
 
 	// Cobra supports Persistent Flags which will work for this command
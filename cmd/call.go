@@ -0,0 +1,117 @@
+// Copyright © 2018 Jason Lu <luhonghai@gmail.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cmd
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/luhonghai/wsdl-example/pkg/dynamic"
+	"github.com/luhonghai/wsdl-example/pkg/validate"
+	"github.com/spf13/cobra"
+)
+
+var (
+	callWSDL      string
+	callOperation string
+	callURL       string
+	callCatalog   string
+	callInsecure  bool
+	callXML       bool
+	callValidate  bool
+)
+
+// callCmd represents the call command
+var callCmd = &cobra.Command{
+	Use:   "call [name=value...]",
+	Short: "Call a SOAP operation described by a WSDL document",
+	Long: `Call an operation of any WSDL document without generating code. The request is
+		built from the schema: every argument sets a parameter, dotted paths reach nested
+		elements and repeating a name gives several values. Positions from 1 tell apart the
+		occurrences of repeated nested elements, as in AccessControlList.Grant[2].Permission,
+		and @type names the derived type an element holds. For example:
+				- wsdl-example call --wsdl pkg/calculator.xml --operation Add intA=1 intB=2
+				- wsdl-example call --wsdl pkg/AmazonS3.wsdl --catalog pkg/schemas/catalog.txt --operation CreateBucket Bucket=photos CreateBucketConfiguration.LocationConstraint=EU
+		The response is printed as JSON, or as XML with --xml. With --validate the request
+		and the response are checked against the schema, and violations are reported.
+		`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := call(args); err != nil {
+			fmt.Println("Error", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func call(args []string) error {
+	if callWSDL == "" || callOperation == "" {
+		return fmt.Errorf("--wsdl and --operation are required")
+	}
+	params := make(map[string][]string)
+	for _, arg := range args {
+		i := strings.Index(arg, "=")
+		if i <= 0 {
+			return fmt.Errorf("argument %q is not of the form name=value", arg)
+		}
+		params[arg[:i]] = append(params[arg[:i]], arg[i+1:])
+	}
+
+	defs, err := loadDefinitions(callWSDL, callCatalog)
+	if err != nil {
+		return err
+	}
+	client := dynamic.NewClient(defs, callURL, callInsecure, nil)
+	if callValidate {
+		client.SetValidator(validate.New(defs))
+	}
+	response, err := client.Call(callOperation, params)
+	if err != nil {
+		return err
+	}
+
+	var output []byte
+	if callXML {
+		output, err = xml.MarshalIndent(response, "", "  ")
+	} else {
+		output, err = json.MarshalIndent(response, "", "  ")
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(output))
+
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(callCmd)
+
+	callCmd.Flags().StringVar(&callWSDL, "wsdl", "", "WSDL document describing the service")
+	callCmd.Flags().StringVar(&callOperation, "operation", "", "operation to call, optionally qualified by its port type")
+	callCmd.Flags().StringVar(&callURL, "url", "", "endpoint to call (default is the address in the WSDL document)")
+	callCmd.Flags().StringVar(&callCatalog, "catalog", "", "catalog mapping remote schema locations to local files")
+	callCmd.Flags().BoolVar(&callInsecure, "insecure", false, "skip TLS certificate verification")
+	callCmd.Flags().BoolVar(&callXML, "xml", false, "print the response as XML")
+	callCmd.Flags().BoolVar(&callValidate, "validate", false, "check the request and the response against the schema")
+}
@@ -0,0 +1,103 @@
+// Copyright © 2018 Jason Lu <luhonghai@gmail.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/luhonghai/wsdl-example/pkg/contract"
+	"github.com/spf13/cobra"
+)
+
+var (
+	diffCatalog string
+	diffJSON    bool
+)
+
+// diffCmd represents the diff command
+var diffCmd = &cobra.Command{
+	Use:   "diff <old wsdl> <new wsdl>",
+	Short: "Compare two versions of a WSDL contract",
+	Long: `Compare operations, messages, element types, cardinalities and endpoints of two
+		versions of a WSDL document. Every change is marked BREAKING when clients generated
+		from the old version may fail against the new one, compatible otherwise. For example:
+				- wsdl-example diff pkg/calculator.xml calculator-v2.xml
+				- wsdl-example diff pkg/AmazonS3.wsdl AmazonS3-new.wsdl --catalog pkg/schemas/catalog.txt --json
+		The command exits with status 1 when a change is breaking, so it can gate builds, and
+		with status 2 when it cannot compare the documents, such as for a WSDL document that
+		does not load or parse.
+		`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 2 {
+			diffFailed(fmt.Errorf("accepts 2 arg(s), received %d", len(args)))
+		}
+		var descriptions []*contract.Description
+		for _, path := range args {
+			defs, err := loadDefinitions(path, diffCatalog)
+			if err != nil {
+				diffFailed(err)
+			}
+			descriptions = append(descriptions, contract.Describe(defs))
+		}
+
+		changes := contract.Diff(descriptions[0], descriptions[1])
+		if diffJSON {
+			if changes == nil {
+				changes = []*contract.Change{}
+			}
+			output, err := json.MarshalIndent(changes, "", "  ")
+			if err != nil {
+				diffFailed(err)
+			}
+			fmt.Println(string(output))
+		} else if err := contract.WriteChanges(os.Stdout, changes); err != nil {
+			diffFailed(err)
+		}
+
+		if contract.Breaking(changes) {
+			os.Exit(diffBreaking)
+		}
+	},
+}
+
+// Exit statuses of diff, telling breaking contracts from broken inputs.
+const (
+	diffBreaking = 1
+	diffError    = 2
+)
+
+func diffFailed(err error) {
+	fmt.Println("Error", err)
+	os.Exit(diffError)
+}
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+
+	diffCmd.Flags().StringVar(&diffCatalog, "catalog", "", "catalog mapping remote schema locations to local files")
+	diffCmd.Flags().BoolVar(&diffJSON, "json", false, "print the changes as JSON")
+	diffCmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		diffFailed(err)
+		return err
+	})
+}
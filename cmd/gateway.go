@@ -0,0 +1,117 @@
+// Copyright © 2018 Jason Lu <luhonghai@gmail.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/luhonghai/wsdl-example/pkg/dynamic"
+	"github.com/luhonghai/wsdl-example/pkg/gateway"
+	"github.com/spf13/cobra"
+)
+
+var (
+	gatewayServices  []string
+	gatewayEndpoints []string
+	gatewayCatalog   string
+	gatewayAddr      string
+	gatewayInsecure  bool
+)
+
+// defaultGatewayServices are the services of this repository the gateway
+// exposes unless told otherwise, as name=wsdl.
+var defaultGatewayServices = []string{"calculator=pkg/calculator.xml", "dilbert=pkg/dilbert.xml", "s3=pkg/AmazonS3.wsdl"}
+
+// gatewayCmd represents the gateway command
+var gatewayCmd = &cobra.Command{
+	Use:   "gateway",
+	Short: "Expose SOAP operations as JSON over HTTP",
+	Long: `Serve the operations of SOAP services as JSON endpoints, one per operation at
+		/<service>/<operation>. The JSON object posted holds the parameters: nested objects
+		fill nested elements and arrays repeated ones, and "@type" names the derived type an
+		element holds, as the OpenAPI document of the openapi command describes. For example:
+				- wsdl-example gateway --addr :8081
+				- curl -d '{"intA": 1, "intB": 2}' http://localhost:8081/calculator/Add
+				- wsdl-example gateway --service people=people.wsdl --endpoint people=http://localhost:8080
+		By default the calculator, dilbert and s3 services of this repository are exposed.
+		Client faults are answered with status 400, other faults and failures of the
+		service with 502 and timeouts with 504, with a JSON error body.
+		`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := serveGateway(); err != nil {
+			fmt.Println("Error", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func serveGateway() error {
+	endpoints, err := namedValues("--endpoint", gatewayEndpoints)
+	if err != nil {
+		return err
+	}
+	services, err := namedValues("--service", gatewayServices)
+	if err != nil {
+		return err
+	}
+
+	g := gateway.New()
+	for _, service := range gatewayServices {
+		name := service[:strings.Index(service, "=")]
+		defs, err := loadDefinitions(services[name], gatewayCatalog)
+		if err != nil {
+			return err
+		}
+		g.Add(name, dynamic.NewClient(defs, endpoints[name], gatewayInsecure, nil))
+		log.Printf("serving %s at /%s/", services[name], name)
+	}
+
+	log.Println("listening on", gatewayAddr)
+	return http.ListenAndServe(gatewayAddr, g)
+}
+
+// namedValues parses arguments of the form name=value.
+func namedValues(flag string, args []string) (map[string]string, error) {
+	values := make(map[string]string)
+	for _, arg := range args {
+		i := strings.Index(arg, "=")
+		if i <= 0 {
+			return nil, fmt.Errorf("%s %q is not of the form name=value", flag, arg)
+		}
+		values[arg[:i]] = arg[i+1:]
+	}
+
+	return values, nil
+}
+
+func init() {
+	rootCmd.AddCommand(gatewayCmd)
+
+	gatewayCmd.Flags().StringArrayVar(&gatewayServices, "service", defaultGatewayServices, "service to expose, as name=wsdl; repeat for several")
+	gatewayCmd.Flags().StringArrayVar(&gatewayEndpoints, "endpoint", nil, "endpoint of a service, as name=url (default is the address in its WSDL)")
+	gatewayCmd.Flags().StringVar(&gatewayCatalog, "catalog", "pkg/schemas/catalog.txt", "catalog mapping remote schema locations to local files")
+	gatewayCmd.Flags().StringVar(&gatewayAddr, "addr", ":8080", "address to listen on")
+	gatewayCmd.Flags().BoolVar(&gatewayInsecure, "insecure", false, "skip TLS certificate verification")
+}
@@ -0,0 +1,194 @@
+// Copyright © 2018 Jason Lu <luhonghai@gmail.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/luhonghai/wsdl-example/pkg/generator"
+	"github.com/spf13/cobra"
+)
+
+var (
+	generateWSDL    string
+	generatePackage string
+	generateOut     string
+	generateFile    string
+	generateCatalog string
+	generateImport  string
+)
+
+// generateCmd represents the generate command
+var generateCmd = &cobra.Command{
+	Use:   "generate",
+	Short: "Generate a Go client package from a WSDL document",
+	Long: `Generate the Go types and client of a SOAP service from its WSDL document. For example:
+				- wsdl-example generate --wsdl pkg/calculator.xml --package calculator --out pkg/calculator
+				- wsdl-example generate --wsdl pkg/AmazonS3.wsdl --package aws --out pkg/aws --file aws_s3.go --catalog pkg/schemas/catalog.txt
+		The generated file carries a go:generate directive, so "go generate ./..." refreshes it.
+		In-memory fakes of the ports, for tests, go to package <package>fake in a directory of
+		that name. They import the generated package, whose import path is derived from go.mod
+		unless --import gives it.
+		`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := generate(); err != nil {
+			fmt.Println("Error", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func generate() error {
+	if generateWSDL == "" {
+		return fmt.Errorf("--wsdl is required")
+	}
+	pkg := generatePackage
+	if pkg == "" {
+		pkg = filepath.Base(strings.TrimSuffix(generateWSDL, filepath.Ext(generateWSDL)))
+	}
+	file := generateFile
+	if file == "" {
+		file = pkg + ".go"
+	}
+
+	importPath := generateImport
+	if importPath == "" {
+		importPath = modulePath(generateOut)
+	}
+
+	defs, err := loadDefinitions(generateWSDL, generateCatalog)
+	if err != nil {
+		return err
+	}
+	options := &generator.Options{
+		Package:    pkg,
+		GoGenerate: goGenerateDirective(pkg, file, importPath),
+		ImportPath: importPath,
+	}
+	source, err := generator.Generate(defs, options)
+	if err != nil {
+		return err
+	}
+	if err := writeGenerated(generateOut, file, source); err != nil {
+		return err
+	}
+
+	if importPath == "" {
+		fmt.Println("Skipped the fakes: cannot tell the import path of", generateOut, "without --import")
+		return nil
+	}
+	fake, err := generator.GenerateFake(defs, options)
+	if err != nil || fake == nil {
+		return err
+	}
+	fakePkg := generator.FakePackage(pkg)
+
+	return writeGenerated(filepath.Join(generateOut, fakePkg), fakePkg+".go", fake)
+}
+
+func writeGenerated(dir, file string, source []byte) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	path := filepath.Join(dir, file)
+	if err := ioutil.WriteFile(path, source, 0644); err != nil {
+		return err
+	}
+	fmt.Println("Generated", path)
+
+	return nil
+}
+
+// modulePath returns the import path of the package in dir, going by the
+// go.mod of the module holding it, or an empty string when there is none.
+func modulePath(dir string) string {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+	for root := abs; ; root = filepath.Dir(root) {
+		data, err := ioutil.ReadFile(filepath.Join(root, "go.mod"))
+		if err == nil {
+			for _, line := range strings.Split(string(data), "\n") {
+				fields := strings.Fields(line)
+				if len(fields) == 2 && fields[0] == "module" {
+					rel, err := filepath.Rel(root, abs)
+					if err != nil {
+						return ""
+					}
+					return path.Join(strings.Trim(fields[1], `"`), filepath.ToSlash(rel))
+				}
+			}
+			return ""
+		}
+		if filepath.Dir(root) == root {
+			return ""
+		}
+	}
+}
+
+// goGenerateDirective returns the command regenerating the package from the
+// output directory, which is where go generate runs it.
+func goGenerateDirective(pkg, file, importPath string) string {
+	directive := fmt.Sprintf("go run github.com/luhonghai/wsdl-example generate --wsdl %s --package %s --out .",
+		relativeToOut(generateWSDL), pkg)
+	if file != pkg+".go" {
+		directive += " --file " + file
+	}
+	if generateCatalog != "" {
+		directive += " --catalog " + relativeToOut(generateCatalog)
+	}
+	if importPath != "" {
+		directive += " --import " + importPath
+	}
+
+	return directive
+}
+
+// relativeToOut returns path relative to the output directory.
+func relativeToOut(path string) string {
+	if out, err := filepath.Abs(generateOut); err == nil {
+		if abs, err := filepath.Abs(path); err == nil {
+			if rel, err := filepath.Rel(out, abs); err == nil {
+				path = rel
+			}
+		}
+	}
+
+	return filepath.ToSlash(path)
+}
+
+func init() {
+	rootCmd.AddCommand(generateCmd)
+
+	generateCmd.Flags().StringVar(&generateWSDL, "wsdl", "", "WSDL document to generate from")
+	generateCmd.Flags().StringVar(&generatePackage, "package", "", "name of the generated package (default is the WSDL file name)")
+	generateCmd.Flags().StringVar(&generateOut, "out", ".", "directory to write the package to")
+	generateCmd.Flags().StringVar(&generateFile, "file", "", "name of the generated file (default is <package>.go)")
+	generateCmd.Flags().StringVar(&generateCatalog, "catalog", "", "catalog mapping remote schema locations to local files")
+	generateCmd.Flags().StringVar(&generateImport, "import", "", "import path of the generated package (default is derived from go.mod)")
+}
@@ -0,0 +1,77 @@
+// Copyright © 2018 Jason Lu <luhonghai@gmail.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/luhonghai/wsdl-example/pkg/contract"
+	"github.com/spf13/cobra"
+)
+
+var (
+	inspectCatalog string
+	inspectJSON    bool
+)
+
+// inspectCmd represents the inspect command
+var inspectCmd = &cobra.Command{
+	Use:   "inspect <wsdl>",
+	Short: "Describe the services and operations of a WSDL document",
+	Long: `List the services, ports, endpoints and SOAP versions of a WSDL document, with
+		every operation, its soapAction and the structure of its input and output. For example:
+				- wsdl-example inspect pkg/calculator.xml
+				- wsdl-example inspect pkg/AmazonS3.wsdl --catalog pkg/schemas/catalog.txt --json
+		Cardinalities other than exactly one are shown as [min..max].
+		`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		defs, err := loadDefinitions(args[0], inspectCatalog)
+		if err != nil {
+			fmt.Println("Error", err)
+			os.Exit(1)
+		}
+
+		description := contract.Describe(defs)
+		if inspectJSON {
+			output, err := json.MarshalIndent(description, "", "  ")
+			if err != nil {
+				fmt.Println("Error", err)
+				os.Exit(1)
+			}
+			fmt.Println(string(output))
+			return
+		}
+		if err := description.WriteTree(os.Stdout); err != nil {
+			fmt.Println("Error", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(inspectCmd)
+
+	inspectCmd.Flags().StringVar(&inspectCatalog, "catalog", "", "catalog mapping remote schema locations to local files")
+	inspectCmd.Flags().BoolVar(&inspectJSON, "json", false, "print the description as JSON")
+}
@@ -0,0 +1,98 @@
+// Copyright © 2018 Jason Lu <luhonghai@gmail.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/luhonghai/wsdl-example/pkg/mock"
+	"github.com/spf13/cobra"
+)
+
+var (
+	mockWSDL    string
+	mockCatalog string
+	mockRules   string
+	mockAddr    string
+)
+
+// mockCmd represents the mock command
+var mockCmd = &cobra.Command{
+	Use:   "mock",
+	Short: "Serve any WSDL document from scripted rules",
+	Long: `Serve the operations of a WSDL document, answering from a rules file. Requests are
+		checked against the schema and answered by the first rule matching them. For example:
+				- wsdl-example mock --wsdl pkg/calculator.xml --rules pkg/mock/testdata/calculator.json
+		The rules file is a JSON array of rules such as
+				{"operation": "Divide", "match": [{"path": "intB", "equals": "0"}],
+				 "fault": {"code": "soap:Server", "string": "Attempted to divide by zero."}}
+		A rule answers with a fault, with an HTTP error when it has a status, or with its
+		response, a template of the element sent back such as
+				<AddResponse xmlns="http://tempuri.org/"><AddResult>{{.Value "intA"}}</AddResult></AddResponse>
+		Values are escaped for XML, and responses are checked against the schema too.
+		Predicates test paths below the request element, such as Contents[2]/Key or
+		Grantee/@type, with equals, regexp or exists. A delay such as "2s" holds the answer back.
+		`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := serveMock(); err != nil {
+			fmt.Println("Error", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func serveMock() error {
+	if mockWSDL == "" || mockRules == "" {
+		return fmt.Errorf("--wsdl and --rules are required")
+	}
+	defs, err := loadDefinitions(mockWSDL, mockCatalog)
+	if err != nil {
+		return err
+	}
+	document, err := ioutil.ReadFile(mockWSDL)
+	if err != nil {
+		return err
+	}
+	rules, err := mock.ReadRules(mockRules)
+	if err != nil {
+		return err
+	}
+	handler, err := mock.NewServer(defs, document, rules)
+	if err != nil {
+		return err
+	}
+
+	log.Println("listening on", mockAddr)
+	return http.ListenAndServe(mockAddr, handler)
+}
+
+func init() {
+	rootCmd.AddCommand(mockCmd)
+
+	mockCmd.Flags().StringVar(&mockWSDL, "wsdl", "", "WSDL document to serve")
+	mockCmd.Flags().StringVar(&mockCatalog, "catalog", "", "catalog mapping remote schema locations to local files")
+	mockCmd.Flags().StringVar(&mockRules, "rules", "", "JSON file of the rules answering requests")
+	mockCmd.Flags().StringVar(&mockAddr, "addr", ":8080", "address to listen on")
+}
@@ -0,0 +1,119 @@
+// Copyright © 2018 Jason Lu <luhonghai@gmail.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/luhonghai/wsdl-example/pkg/openapi"
+	"github.com/spf13/cobra"
+)
+
+var (
+	openapiCatalog string
+	openapiOut     string
+	openapiTitle   string
+	openapiVersion string
+	openapiServer  string
+)
+
+// openapiCmd represents the openapi command
+var openapiCmd = &cobra.Command{
+	Use:   "openapi <wsdl>",
+	Short: "Describe the JSON endpoints of a WSDL document as an OpenAPI 3 document",
+	Long: `Write an OpenAPI 3 document describing the operations of a WSDL document as the
+		gateway command serves them, with a schema for every type. For example:
+				- wsdl-example openapi pkg/calculator.xml
+				- wsdl-example openapi pkg/AmazonS3.wsdl --out s3.json
+				- wsdl-example openapi people.wsdl --server /people
+		The server URL defaults to the path the gateway serves the document at by default,
+		as in /s3 for pkg/AmazonS3.wsdl, and to the name of other WSDL files.
+		`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := writeOpenAPI(args[0]); err != nil {
+			fmt.Println("Error", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func writeOpenAPI(path string) error {
+	defs, err := loadDefinitions(path, openapiCatalog)
+	if err != nil {
+		return err
+	}
+	server := openapiServer
+	if server == "" {
+		server = "/" + gatewayServiceName(path)
+	}
+	doc, err := openapi.Generate(defs, &openapi.Options{
+		Title:     openapiTitle,
+		Version:   openapiVersion,
+		ServerURL: server,
+	})
+	if err != nil {
+		return err
+	}
+
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return err
+	}
+	if openapiOut == "" {
+		_, err = os.Stdout.Write(buffer.Bytes())
+		return err
+	}
+
+	return ioutil.WriteFile(openapiOut, buffer.Bytes(), 0644)
+}
+
+// gatewayServiceName returns the name the gateway serves the WSDL document
+// at path under by default, or the name of the file for other documents.
+func gatewayServiceName(path string) string {
+	for _, service := range defaultGatewayServices {
+		parts := strings.SplitN(service, "=", 2)
+		if filepath.Clean(parts[1]) == filepath.Clean(path) {
+			return parts[0]
+		}
+	}
+
+	return strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+}
+
+func init() {
+	rootCmd.AddCommand(openapiCmd)
+
+	openapiCmd.Flags().StringVar(&openapiCatalog, "catalog", "pkg/schemas/catalog.txt", "catalog mapping remote schema locations to local files")
+	openapiCmd.Flags().StringVar(&openapiOut, "out", "", "file to write the document to (default is standard output)")
+	openapiCmd.Flags().StringVar(&openapiTitle, "title", "", "title of the API (default is the name of the WSDL document or of its service)")
+	openapiCmd.Flags().StringVar(&openapiVersion, "version", "1.0.0", "version of the API")
+	openapiCmd.Flags().StringVar(&openapiServer, "server", "", "URL the gateway serves the operations at (default is the path of the service in the gateway)")
+}
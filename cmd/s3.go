@@ -0,0 +1,73 @@
+// Copyright © 2018 Jason Lu <luhonghai@gmail.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cmd
+
+import (
+	"os"
+
+	"github.com/luhonghai/wsdl-example/pkg/aws"
+	"github.com/spf13/cobra"
+)
+
+var (
+	s3Endpoint  string
+	s3AccessKey string
+	s3SecretKey string
+	s3Insecure  bool
+)
+
+// s3Cmd represents the s3 command
+var s3Cmd = &cobra.Command{
+	Use:   "s3",
+	Short: "Amazon S3 SOAP commands",
+	Long: `Commands talking to the Amazon S3 SOAP API. Credentials default to the
+AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY environment variables.`,
+}
+
+func init() {
+	rootCmd.AddCommand(s3Cmd)
+
+	s3Cmd.PersistentFlags().StringVar(&s3Endpoint, "endpoint", "", "S3 SOAP endpoint (default is https://s3.amazonaws.com/soap)")
+	s3Cmd.PersistentFlags().StringVar(&s3AccessKey, "access-key", "", "AWS access key id")
+	s3Cmd.PersistentFlags().StringVar(&s3SecretKey, "secret-key", "", "AWS secret access key used to sign requests")
+	s3Cmd.PersistentFlags().BoolVar(&s3Insecure, "insecure", false, "skip TLS certificate verification")
+}
+
+func newS3Service() *aws.AmazonS3 {
+	return aws.NewAmazonS3(s3Endpoint, s3Insecure, nil)
+}
+
+// s3Credentials returns the credentials given on the command line, falling
+// back to the standard AWS environment variables.
+func s3Credentials() *aws.Credentials {
+	credentials := &aws.Credentials{
+		AccessKeyID:     s3AccessKey,
+		SecretAccessKey: s3SecretKey,
+	}
+	if credentials.AccessKeyID == "" {
+		credentials.AccessKeyID = os.Getenv("AWS_ACCESS_KEY_ID")
+	}
+	if credentials.SecretAccessKey == "" {
+		credentials.SecretAccessKey = os.Getenv("AWS_SECRET_ACCESS_KEY")
+	}
+
+	return credentials
+}
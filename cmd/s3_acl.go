@@ -0,0 +1,172 @@
+// Copyright © 2018 Jason Lu <luhonghai@gmail.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/luhonghai/wsdl-example/pkg/aws"
+	"github.com/luhonghai/wsdl-example/pkg/xsd"
+	"github.com/spf13/cobra"
+)
+
+var (
+	s3ACLCanned string
+	s3ACLDryRun bool
+)
+
+// s3ACLCmd represents the s3 acl command
+var s3ACLCmd = &cobra.Command{
+	Use:   "acl",
+	Short: "Show or change bucket and object access control lists",
+	Long: `Show or change access control lists. Give a key to act on an object
+instead of the bucket. For example:
+				- wsdl-example s3 acl get my-bucket
+				- wsdl-example s3 acl get my-bucket photos/cat.jpg
+				- wsdl-example s3 acl set my-bucket --canned public-read
+				- wsdl-example s3 acl set my-bucket photos/cat.jpg --canned private --dry-run
+		`,
+}
+
+var s3ACLGetCmd = &cobra.Command{
+	Use:   "get <bucket> [key]",
+	Short: "Print an access control list as a table",
+	Args:  cobra.RangeArgs(1, 2),
+	Run: func(cmd *cobra.Command, args []string) {
+		policy, err := getS3Policy(args)
+		if err != nil {
+			fmt.Println("Error", err)
+			os.Exit(1)
+		}
+		if policy.Owner != nil {
+			fmt.Printf("Owner: %s (%s)\n\n", xsd.StringValue(policy.Owner.DisplayName), policy.Owner.ID)
+		}
+		var grants []*aws.Grant
+		if policy.AccessControlList != nil {
+			grants = policy.AccessControlList.Grant
+		}
+		printGrants(grants, "")
+	},
+}
+
+var s3ACLSetCmd = &cobra.Command{
+	Use:   "set <bucket> [key]",
+	Short: "Apply a canned access control list",
+	Long: `Apply a canned access control list, only updating S3 when the grants differ.
+Supported canned ACLs are private, public-read, public-read-write,
+authenticated-read and log-delivery-write.`,
+	Args: cobra.RangeArgs(1, 2),
+	Run: func(cmd *cobra.Command, args []string) {
+		policy, err := getS3Policy(args)
+		if err != nil {
+			fmt.Println("Error", err)
+			os.Exit(1)
+		}
+		desired, err := aws.NewCannedACL(aws.CannedACL(s3ACLCanned), policy.Owner)
+		if err != nil {
+			fmt.Println("Error", err)
+			os.Exit(1)
+		}
+
+		var change *aws.ACLChange
+		switch {
+		case s3ACLDryRun:
+			change = aws.DiffACL(policy.AccessControlList, desired)
+		case len(args) == 2:
+			change, err = newS3Service().ApplyObjectACL(s3Credentials(), args[0], args[1], desired)
+		default:
+			change, err = newS3Service().ApplyBucketACL(s3Credentials(), args[0], desired)
+		}
+		if err != nil {
+			fmt.Println("Error", err)
+			os.Exit(1)
+		}
+		if change.Empty() {
+			fmt.Println("ACL already up to date")
+			return
+		}
+		printGrants(change.Added, "+")
+		printGrants(change.Removed, "-")
+	},
+}
+
+func init() {
+	s3Cmd.AddCommand(s3ACLCmd)
+	s3ACLCmd.AddCommand(s3ACLGetCmd)
+	s3ACLCmd.AddCommand(s3ACLSetCmd)
+
+	s3ACLSetCmd.Flags().StringVar(&s3ACLCanned, "canned", string(aws.CannedPrivate), "canned ACL to apply")
+	s3ACLSetCmd.Flags().BoolVar(&s3ACLDryRun, "dry-run", false, "print the change without applying it")
+}
+
+// getS3Policy fetches the policy of the bucket, or of the object when args
+// also holds a key.
+func getS3Policy(args []string) (*aws.AccessControlPolicy, error) {
+	service := newS3Service()
+	if len(args) == 2 {
+		key, timestamp, signature := s3Credentials().Sign("GetObjectAccessControlPolicy")
+		resp, err := service.GetObjectAccessControlPolicy(&aws.GetObjectAccessControlPolicy{
+			Bucket:         args[0],
+			Key:            args[1],
+			AWSAccessKeyId: key,
+			Timestamp:      timestamp,
+			Signature:      signature,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return resp.GetObjectAccessControlPolicyResponse, nil
+	}
+
+	key, timestamp, signature := s3Credentials().Sign("GetBucketAccessControlPolicy")
+	resp, err := service.GetBucketAccessControlPolicy(&aws.GetBucketAccessControlPolicy{
+		Bucket:         args[0],
+		AWSAccessKeyId: key,
+		Timestamp:      timestamp,
+		Signature:      signature,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetBucketAccessControlPolicyResponse, nil
+}
+
+// printGrants writes grants as a table, prefixing each row with marker.
+func printGrants(grants []*aws.Grant, marker string) {
+	if len(grants) == 0 {
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	if marker == "" {
+		fmt.Fprintln(w, "TYPE\tGRANTEE\tPERMISSION")
+	}
+	for _, grant := range grants {
+		var kind string
+		if grant.Grantee != nil {
+			kind = grant.Grantee.Type
+		}
+		fmt.Fprintf(w, "%s%s\t%s\t%s\n", marker, kind, grant.Grantee, grant.Permission)
+	}
+	w.Flush()
+}
@@ -0,0 +1,145 @@
+// Copyright © 2018 Jason Lu <luhonghai@gmail.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/luhonghai/wsdl-example/pkg/aws"
+	"github.com/spf13/cobra"
+)
+
+var (
+	s3BucketLocation string
+	s3BucketForce    bool
+)
+
+// s3MakeBucketCmd represents the s3 mb command
+var s3MakeBucketCmd = &cobra.Command{
+	Use:   "mb <bucket>",
+	Short: "Create a bucket",
+	Long: `Create a bucket, optionally in a given region. For example:
+				- wsdl-example s3 mb my-bucket
+				- wsdl-example s3 mb my-bucket --location EU
+		`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		key, timestamp, signature := s3Credentials().Sign("CreateBucket")
+		request := &aws.CreateBucket{
+			Bucket:         args[0],
+			AWSAccessKeyId: key,
+			Timestamp:      timestamp,
+			Signature:      signature,
+		}
+		if s3BucketLocation != "" {
+			request.CreateBucketConfiguration = &aws.CreateBucketConfiguration{
+				LocationConstraint: &aws.LocationConstraint{Value: s3BucketLocation},
+			}
+		}
+
+		resp, err := newS3Service().CreateBucket(request)
+		if err != nil {
+			fmt.Println("Error", err)
+			os.Exit(1)
+		}
+		fmt.Println("Created bucket", resp.CreateBucketReturn.BucketName)
+	},
+}
+
+// s3LocationCmd represents the s3 location command
+var s3LocationCmd = &cobra.Command{
+	Use:   "location <bucket>",
+	Short: "Print the region a bucket lives in",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		key, timestamp, signature := s3Credentials().Sign("GetBucketLocation")
+		resp, err := newS3Service().GetBucketLocation(&aws.GetBucketLocation{
+			Bucket:         args[0],
+			AWSAccessKeyId: key,
+			Timestamp:      timestamp,
+			Signature:      signature,
+		})
+		if err != nil {
+			fmt.Println("Error", err)
+			os.Exit(1)
+		}
+		location := ""
+		if resp.GetBucketLocationResponse != nil {
+			location = resp.GetBucketLocationResponse.Value
+		}
+		if location == "" {
+			// An empty constraint means the bucket lives in the default region.
+			location = "US"
+		}
+		fmt.Println(location)
+	},
+}
+
+// s3RemoveBucketCmd represents the s3 rb command
+var s3RemoveBucketCmd = &cobra.Command{
+	Use:   "rb <bucket>",
+	Short: "Delete a bucket",
+	Long: `Delete a bucket. S3 refuses to delete a bucket that still holds objects,
+use --force to delete them first. For example:
+				- wsdl-example s3 rb my-bucket
+				- wsdl-example s3 rb my-bucket --force
+		`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		service := newS3Service()
+		if s3BucketForce {
+			report, err := service.DeletePrefix(args[0], "", s3DeleteOptions())
+			if err != nil {
+				fmt.Println("Error", err)
+				os.Exit(1)
+			}
+			printDeleteReport(report)
+			if err := report.Err(); err != nil {
+				fmt.Println("Error", err)
+				os.Exit(1)
+			}
+		}
+
+		key, timestamp, signature := s3Credentials().Sign("DeleteBucket")
+		_, err := service.DeleteBucket(&aws.DeleteBucket{
+			Bucket:         args[0],
+			AWSAccessKeyId: key,
+			Timestamp:      timestamp,
+			Signature:      signature,
+		})
+		if err != nil {
+			fmt.Println("Error", err)
+			os.Exit(1)
+		}
+		fmt.Println("Deleted bucket", args[0])
+	},
+}
+
+func init() {
+	s3Cmd.AddCommand(s3MakeBucketCmd)
+	s3Cmd.AddCommand(s3LocationCmd)
+	s3Cmd.AddCommand(s3RemoveBucketCmd)
+
+	s3MakeBucketCmd.Flags().StringVar(&s3BucketLocation, "location", "", "location constraint of the bucket, e.g. EU")
+	s3RemoveBucketCmd.Flags().BoolVar(&s3BucketForce, "force", false, "delete every object in the bucket first")
+	addS3DeleteFlags(s3RemoveBucketCmd)
+}
@@ -0,0 +1,112 @@
+// Copyright © 2018 Jason Lu <luhonghai@gmail.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/luhonghai/wsdl-example/pkg/aws"
+	"github.com/spf13/cobra"
+)
+
+var (
+	s3NotificationTopic  string
+	s3NotificationEvents []string
+)
+
+// s3NotificationCmd represents the s3 notification command
+var s3NotificationCmd = &cobra.Command{
+	Use:   "notification",
+	Short: "Get or set bucket notification configuration",
+	Long: `Manage the topics a bucket publishes events to. For example:
+				- wsdl-example s3 notification get my-bucket
+				- wsdl-example s3 notification set my-bucket --topic arn:aws:sns:us-east-1:123456789012:uploads --event s3:ReducedRedundancyLostObject
+				- wsdl-example s3 notification set my-bucket
+		`,
+}
+
+var s3NotificationGetCmd = &cobra.Command{
+	Use:   "get <bucket>",
+	Short: "Print the topics configured for a bucket",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		key, timestamp, signature := s3Credentials().Sign("GetBucketNotification")
+		resp, err := newS3Service().GetBucketNotification(&aws.GetBucketNotification{
+			Bucket:         args[0],
+			AWSAccessKeyId: key,
+			Timestamp:      timestamp,
+			Signature:      signature,
+		})
+		if err != nil {
+			fmt.Println("Error", err)
+			os.Exit(1)
+		}
+		config := resp.GetBucketNotificationResponse
+		if config == nil || len(config.TopicConfiguration) == 0 {
+			fmt.Println("No notifications configured")
+			return
+		}
+		for _, topic := range config.TopicConfiguration {
+			fmt.Printf("%s\t%s\n", topic.Topic, strings.Join(topic.Event, ","))
+		}
+	},
+}
+
+var s3NotificationSetCmd = &cobra.Command{
+	Use:   "set <bucket>",
+	Short: "Replace the notification configuration of a bucket",
+	Long:  `Publish the given events to a topic. Without --topic the configuration is cleared.`,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		config := &aws.NotificationConfiguration{}
+		if s3NotificationTopic != "" {
+			config.TopicConfiguration = []*aws.TopicConfiguration{{
+				Topic: s3NotificationTopic,
+				Event: s3NotificationEvents,
+			}}
+		}
+
+		key, timestamp, signature := s3Credentials().Sign("SetBucketNotification")
+		_, err := newS3Service().SetBucketNotification(&aws.SetBucketNotification{
+			Bucket:                    args[0],
+			AWSAccessKeyId:            key,
+			Timestamp:                 timestamp,
+			Signature:                 signature,
+			NotificationConfiguration: config,
+		})
+		if err != nil {
+			fmt.Println("Error", err)
+			os.Exit(1)
+		}
+		fmt.Println("Notification configuration updated")
+	},
+}
+
+func init() {
+	s3Cmd.AddCommand(s3NotificationCmd)
+	s3NotificationCmd.AddCommand(s3NotificationGetCmd)
+	s3NotificationCmd.AddCommand(s3NotificationSetCmd)
+
+	s3NotificationSetCmd.Flags().StringVar(&s3NotificationTopic, "topic", "", "topic to publish events to")
+	s3NotificationSetCmd.Flags().StringSliceVar(&s3NotificationEvents, "event", []string{"s3:ReducedRedundancyLostObject"}, "event to publish, may be repeated")
+}
@@ -0,0 +1,106 @@
+// Copyright © 2018 Jason Lu <luhonghai@gmail.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/luhonghai/wsdl-example/pkg/aws"
+	"github.com/spf13/cobra"
+)
+
+var (
+	s3RemovePrefix      string
+	s3RemoveConcurrency int
+	s3RemoveRate        float64
+)
+
+// s3RemoveCmd represents the s3 rm command
+var s3RemoveCmd = &cobra.Command{
+	Use:   "rm <bucket> [key...]",
+	Short: "Delete objects",
+	Long: `Delete the given keys, or every key under a prefix, concurrently. Keys and --prefix exclude each other. For example:
+				- wsdl-example s3 rm my-bucket photos/cat.jpg photos/dog.jpg
+				- wsdl-example s3 rm my-bucket --prefix tmp/ --concurrency 16 --rate 100
+		`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		bucket, keys := args[0], args[1:]
+		if len(keys) == 0 && s3RemovePrefix == "" {
+			fmt.Println("Require keys or --prefix")
+			os.Exit(1)
+		}
+		if len(keys) > 0 && s3RemovePrefix != "" {
+			fmt.Println("Give either keys or --prefix, not both")
+			os.Exit(1)
+		}
+
+		service := newS3Service()
+		var report *aws.DeleteReport
+		if len(keys) > 0 {
+			report = service.DeleteObjects(bucket, keys, s3DeleteOptions())
+		} else {
+			var err error
+			report, err = service.DeletePrefix(bucket, s3RemovePrefix, s3DeleteOptions())
+			if err != nil {
+				fmt.Println("Error", err)
+				os.Exit(1)
+			}
+		}
+		printDeleteReport(report)
+		if err := report.Err(); err != nil {
+			fmt.Println("Error", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	s3Cmd.AddCommand(s3RemoveCmd)
+
+	s3RemoveCmd.Flags().StringVar(&s3RemovePrefix, "prefix", "", "delete every key starting with prefix")
+	addS3DeleteFlags(s3RemoveCmd)
+}
+
+// addS3DeleteFlags adds the flags tuning bulk deletes to cmd.
+func addS3DeleteFlags(cmd *cobra.Command) {
+	cmd.Flags().IntVar(&s3RemoveConcurrency, "concurrency", aws.DefaultDeleteConcurrency, "number of deletes in flight")
+	cmd.Flags().Float64Var(&s3RemoveRate, "rate", 0, "maximum deletes per second, 0 for no limit")
+}
+
+func s3DeleteOptions() *aws.BulkDeleteOptions {
+	return &aws.BulkDeleteOptions{
+		Credentials:       s3Credentials(),
+		Concurrency:       s3RemoveConcurrency,
+		RequestsPerSecond: s3RemoveRate,
+	}
+}
+
+func printDeleteReport(report *aws.DeleteReport) {
+	for _, result := range report.Results {
+		if result.Err != nil {
+			fmt.Printf("failed\t%s\t%v\n", result.Key, result.Err)
+		} else {
+			fmt.Printf("deleted\t%s\n", result.Key)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright © 2018 Jason Lu <luhonghai@gmail.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/luhonghai/wsdl-example/pkg/calculator"
+	"github.com/luhonghai/wsdl-example/pkg/dilbert"
+	"github.com/spf13/cobra"
+)
+
+var (
+	serveAddr string
+	serveWSDL string
+	serveDir  string
+)
+
+// serveCmd represents the serve command
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve reference implementations of the example SOAP services",
+	Long: `Serve one of the example services over HTTP, so that the clients can be tried
+		without the public endpoints. For example:
+				- wsdl-example serve calculator --addr :8080 --wsdl pkg/calculator.xml
+				- wsdl-example calculate --url http://localhost:8080 add 1 5
+				- wsdl-example serve dilbert --dir strips
+		With --wsdl the document is served at ?wsdl.
+		`,
+}
+
+// serveCalculatorCmd represents the serve calculator command
+var serveCalculatorCmd = &cobra.Command{
+	Use:   "calculator",
+	Short: "Serve the CalculatorSoap port",
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := serve(func(wsdl []byte) http.Handler {
+			return calculator.NewCalculatorSoapHandler(calculator.Server{}, wsdl)
+		}); err != nil {
+			fmt.Println("Error", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// serveDilbertCmd represents the serve dilbert command
+var serveDilbertCmd = &cobra.Command{
+	Use:   "dilbert",
+	Short: "Serve the DilbertSoap port",
+	Long: `Serve the DilbertSoap port with the strips of a directory, one HTML snippet
+		per day in files named like 2009-10-12.html.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := serve(func(wsdl []byte) http.Handler {
+			return dilbert.NewDilbertSoapHandler(&dilbert.Server{Provider: dilbert.Dir(serveDir)}, wsdl)
+		}); err != nil {
+			fmt.Println("Error", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// serve listens on --addr with the handler newHandler returns for the
+// document given by --wsdl, if any.
+func serve(newHandler func(wsdl []byte) http.Handler) error {
+	var wsdl []byte
+	if serveWSDL != "" {
+		var err error
+		if wsdl, err = ioutil.ReadFile(serveWSDL); err != nil {
+			return err
+		}
+	}
+
+	log.Println("listening on", serveAddr)
+	return http.ListenAndServe(serveAddr, newHandler(wsdl))
+}
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+	serveCmd.AddCommand(serveCalculatorCmd)
+	serveCmd.AddCommand(serveDilbertCmd)
+
+	serveCmd.PersistentFlags().StringVar(&serveAddr, "addr", ":8080", "address to listen on")
+	serveCmd.PersistentFlags().StringVar(&serveWSDL, "wsdl", "", "WSDL document to serve at ?wsdl")
+	serveDilbertCmd.Flags().StringVar(&serveDir, "dir", ".", "directory holding the strips")
+}
@@ -0,0 +1,89 @@
+// Copyright © 2018 Jason Lu <luhonghai@gmail.com>
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/luhonghai/wsdl-example/pkg/wsdl"
+	"github.com/spf13/cobra"
+)
+
+var (
+	vendorWSDL string
+	vendorDir  string
+)
+
+// vendorSchemasCmd represents the vendor-schemas command
+var vendorSchemasCmd = &cobra.Command{
+	Use:   "vendor-schemas",
+	Short: "Snapshot the remote schemas of a WSDL document into the repository",
+	Long: `Download the remote schemas a WSDL document includes or imports, save them below a directory
+		and list them in its catalog.txt, so later runs of generate work offline. For example:
+				- wsdl-example vendor-schemas --wsdl pkg/AmazonS3.wsdl --dir pkg/schemas
+		Schemas already in the catalog are not downloaded again.
+		`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := vendorSchemas(); err != nil {
+			fmt.Println("Error", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func vendorSchemas() error {
+	if vendorWSDL == "" {
+		return fmt.Errorf("--wsdl is required")
+	}
+
+	catalogPath := filepath.Join(vendorDir, "catalog.txt")
+	catalog, err := wsdl.ReadCatalog(catalogPath)
+	if os.IsNotExist(err) {
+		catalog, err = make(wsdl.Catalog), nil
+	}
+	if err != nil {
+		return err
+	}
+
+	locations, err := wsdl.Vendor(vendorWSDL, catalog, vendorDir, wsdl.FetchHTTP)
+	if err != nil {
+		return err
+	}
+	if len(locations) == 0 {
+		fmt.Println("All schemas are vendored already")
+		return nil
+	}
+	for _, location := range locations {
+		fmt.Println("Vendored", location)
+	}
+
+	return catalog.WriteFile(catalogPath)
+}
+
+func init() {
+	rootCmd.AddCommand(vendorSchemasCmd)
+
+	vendorSchemasCmd.Flags().StringVar(&vendorWSDL, "wsdl", "", "WSDL document whose schemas to vendor")
+	vendorSchemasCmd.Flags().StringVar(&vendorDir, "dir", "schemas", "directory to save the schemas and catalog.txt in")
+}
@@ -0,0 +1,266 @@
+package aws
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/luhonghai/wsdl-example/pkg/xsd"
+)
+
+// Well known group URIs S3 accepts as grantees.
+const (
+	AllUsersGroup           = "http://acs.amazonaws.com/groups/global/AllUsers"
+	AuthenticatedUsersGroup = "http://acs.amazonaws.com/groups/global/AuthenticatedUsers"
+	LogDeliveryGroup        = "http://acs.amazonaws.com/groups/s3/LogDelivery"
+)
+
+// Values of the xsi:type attribute distinguishing the kinds of Grantee.
+const (
+	GranteeCanonicalUser         = "CanonicalUser"
+	GranteeAmazonCustomerByEmail = "AmazonCustomerByEmail"
+	GranteeGroup                 = "Group"
+)
+
+// CannedACL names one of the access policies S3 predefines.
+type CannedACL string
+
+const (
+	CannedPrivate           CannedACL = "private"
+	CannedPublicRead        CannedACL = "public-read"
+	CannedPublicReadWrite   CannedACL = "public-read-write"
+	CannedAuthenticatedRead CannedACL = "authenticated-read"
+	CannedLogDeliveryWrite  CannedACL = "log-delivery-write"
+)
+
+// CannedACLs lists the supported canned access policies.
+var CannedACLs = []CannedACL{
+	CannedPrivate,
+	CannedPublicRead,
+	CannedPublicReadWrite,
+	CannedAuthenticatedRead,
+	CannedLogDeliveryWrite,
+}
+
+// NewCanonicalUserGrantee identifies an AWS account by its canonical user ID.
+func NewCanonicalUserGrantee(id, displayName string) *Grantee {
+	grantee := &Grantee{Type: GranteeCanonicalUser, ID: &id}
+	if displayName != "" {
+		grantee.DisplayName = &displayName
+	}
+
+	return grantee
+}
+
+// NewEmailGrantee identifies an AWS account by its email address.
+func NewEmailGrantee(email string) *Grantee {
+	return &Grantee{Type: GranteeAmazonCustomerByEmail, EmailAddress: &email}
+}
+
+// NewGroupGrantee identifies a predefined group such as AllUsersGroup.
+func NewGroupGrantee(uri string) *Grantee {
+	return &Grantee{Type: GranteeGroup, URI: &uri}
+}
+
+// NewGrant gives grantee permission.
+func NewGrant(grantee *Grantee, permission Permission) *Grant {
+	return &Grant{Grantee: grantee, Permission: permission}
+}
+
+// NewCannedACL builds the access control list S3 would apply for canned,
+// giving owner full control.
+func NewCannedACL(canned CannedACL, owner *CanonicalUser) (*AccessControlList, error) {
+	if owner == nil {
+		return nil, fmt.Errorf("canned ACL %q requires the owner", canned)
+	}
+
+	acl := &AccessControlList{
+		Grant: []*Grant{
+			NewGrant(NewCanonicalUserGrantee(owner.ID, xsd.StringValue(owner.DisplayName)), PermissionFULLCONTROL),
+		},
+	}
+	switch canned {
+	case CannedPrivate:
+	case CannedPublicRead:
+		acl.Grant = append(acl.Grant, NewGrant(NewGroupGrantee(AllUsersGroup), PermissionREAD))
+	case CannedPublicReadWrite:
+		acl.Grant = append(acl.Grant,
+			NewGrant(NewGroupGrantee(AllUsersGroup), PermissionREAD),
+			NewGrant(NewGroupGrantee(AllUsersGroup), PermissionWRITE))
+	case CannedAuthenticatedRead:
+		acl.Grant = append(acl.Grant, NewGrant(NewGroupGrantee(AuthenticatedUsersGroup), PermissionREAD))
+	case CannedLogDeliveryWrite:
+		acl.Grant = append(acl.Grant,
+			NewGrant(NewGroupGrantee(LogDeliveryGroup), PermissionWRITE),
+			NewGrant(NewGroupGrantee(LogDeliveryGroup), PermissionREADACP))
+	default:
+		return nil, fmt.Errorf("unknown canned ACL %q", canned)
+	}
+
+	return acl, nil
+}
+
+// String describes the grantee the way S3 identifies it: by canonical ID,
+// email address or group URI.
+func (g *Grantee) String() string {
+	if g == nil {
+		return ""
+	}
+	switch {
+	case g.URI != nil:
+		return *g.URI
+	case g.EmailAddress != nil:
+		return *g.EmailAddress
+	case g.DisplayName != nil:
+		return *g.DisplayName + " (" + xsd.StringValue(g.ID) + ")"
+	}
+
+	return xsd.StringValue(g.ID)
+}
+
+// ACLChange is the difference between two access control lists.
+type ACLChange struct {
+	Added   []*Grant
+	Removed []*Grant
+}
+
+// Empty reports whether the lists grant the same permissions.
+func (c *ACLChange) Empty() bool {
+	return len(c.Added) == 0 && len(c.Removed) == 0
+}
+
+// DiffACL computes the grants to add to and remove from current to arrive at
+// desired. Grants are compared by grantee identity and permission; display
+// names are ignored as S3 fills them in itself.
+func DiffACL(current, desired *AccessControlList) *ACLChange {
+	have := grantSet(current)
+	want := grantSet(desired)
+
+	change := &ACLChange{}
+	for _, grant := range grants(desired) {
+		if _, ok := have[grantKey(grant)]; !ok {
+			change.Added = append(change.Added, grant)
+		}
+	}
+	for _, grant := range grants(current) {
+		if _, ok := want[grantKey(grant)]; !ok {
+			change.Removed = append(change.Removed, grant)
+		}
+	}
+
+	return change
+}
+
+// ApplyBucketACL replaces the access control list of bucket with desired,
+// skipping the update when nothing would change.
+func (service *AmazonS3) ApplyBucketACL(credentials *Credentials, bucket string, desired *AccessControlList) (*ACLChange, error) {
+	key, timestamp, signature := credentials.Sign("GetBucketAccessControlPolicy")
+	resp, err := service.GetBucketAccessControlPolicy(&GetBucketAccessControlPolicy{
+		Bucket:         bucket,
+		AWSAccessKeyId: key,
+		Timestamp:      timestamp,
+		Signature:      signature,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	change := DiffACL(policyACL(resp.GetBucketAccessControlPolicyResponse), desired)
+	if change.Empty() {
+		return change, nil
+	}
+
+	key, timestamp, signature = credentials.Sign("SetBucketAccessControlPolicy")
+	_, err = service.SetBucketAccessControlPolicy(&SetBucketAccessControlPolicy{
+		Bucket:            bucket,
+		AccessControlList: desired,
+		AWSAccessKeyId:    key,
+		Timestamp:         timestamp,
+		Signature:         signature,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return change, nil
+}
+
+// ApplyObjectACL replaces the access control list of an object with desired,
+// skipping the update when nothing would change.
+func (service *AmazonS3) ApplyObjectACL(credentials *Credentials, bucket, objectKey string, desired *AccessControlList) (*ACLChange, error) {
+	key, timestamp, signature := credentials.Sign("GetObjectAccessControlPolicy")
+	resp, err := service.GetObjectAccessControlPolicy(&GetObjectAccessControlPolicy{
+		Bucket:         bucket,
+		Key:            objectKey,
+		AWSAccessKeyId: key,
+		Timestamp:      timestamp,
+		Signature:      signature,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	change := DiffACL(policyACL(resp.GetObjectAccessControlPolicyResponse), desired)
+	if change.Empty() {
+		return change, nil
+	}
+
+	key, timestamp, signature = credentials.Sign("SetObjectAccessControlPolicy")
+	_, err = service.SetObjectAccessControlPolicy(&SetObjectAccessControlPolicy{
+		Bucket:            bucket,
+		Key:               objectKey,
+		AccessControlList: desired,
+		AWSAccessKeyId:    key,
+		Timestamp:         timestamp,
+		Signature:         signature,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return change, nil
+}
+
+func policyACL(policy *AccessControlPolicy) *AccessControlList {
+	if policy == nil {
+		return nil
+	}
+
+	return policy.AccessControlList
+}
+
+func grants(acl *AccessControlList) []*Grant {
+	if acl == nil {
+		return nil
+	}
+
+	return acl.Grant
+}
+
+func grantSet(acl *AccessControlList) map[string]struct{} {
+	set := make(map[string]struct{})
+	for _, grant := range grants(acl) {
+		set[grantKey(grant)] = struct{}{}
+	}
+
+	return set
+}
+
+func grantKey(grant *Grant) string {
+	permission := grant.Permission
+	grantee := grant.Grantee
+	if grantee == nil {
+		return "|" + string(permission)
+	}
+
+	var id string
+	switch {
+	case grantee.URI != nil:
+		id = *grantee.URI
+	case grantee.EmailAddress != nil:
+		id = strings.ToLower(*grantee.EmailAddress)
+	default:
+		id = xsd.StringValue(grantee.ID)
+	}
+
+	return id + "|" + string(permission)
+}
@@ -0,0 +1,83 @@
+package aws
+
+import (
+	"testing"
+
+	"github.com/luhonghai/wsdl-example/pkg/xsd"
+	"github.com/magiconair/properties/assert"
+)
+
+func TestNewCannedACL(t *testing.T) {
+	owner := &CanonicalUser{ID: "owner-id", DisplayName: xsd.String("owner")}
+
+	acl, err := NewCannedACL(CannedPublicRead, owner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, len(acl.Grant), 2)
+	assert.Equal(t, *acl.Grant[0].Grantee.ID, "owner-id")
+	assert.Equal(t, acl.Grant[0].Permission, PermissionFULLCONTROL)
+	assert.Equal(t, *acl.Grant[1].Grantee.URI, AllUsersGroup)
+	assert.Equal(t, acl.Grant[1].Permission, PermissionREAD)
+
+	acl, err = NewCannedACL(CannedLogDeliveryWrite, owner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, len(acl.Grant), 3)
+	assert.Equal(t, acl.Grant[2].Permission, PermissionREADACP)
+
+	if _, err := NewCannedACL("bucket-owner-read", owner); err == nil {
+		t.Error("Expected an error for an unknown canned ACL")
+	}
+}
+
+func TestDiffACL(t *testing.T) {
+	owner := &CanonicalUser{ID: "owner-id"}
+	private, _ := NewCannedACL(CannedPrivate, owner)
+	public, _ := NewCannedACL(CannedPublicReadWrite, owner)
+
+	change := DiffACL(private, public)
+	assert.Equal(t, len(change.Added), 2)
+	assert.Equal(t, len(change.Removed), 0)
+
+	change = DiffACL(public, private)
+	assert.Equal(t, len(change.Added), 0)
+	assert.Equal(t, len(change.Removed), 2)
+
+	// S3 reports display names the caller never sent; they must not count.
+	named, _ := NewCannedACL(CannedPrivate, &CanonicalUser{ID: "owner-id", DisplayName: xsd.String("owner")})
+	assert.Equal(t, DiffACL(named, private).Empty(), true)
+}
+
+func TestApplyBucketACL(t *testing.T) {
+	fake := newFakeS3(t)
+	s3 := NewAmazonS3(fake.URL, false, nil)
+	if _, err := s3.CreateBucket(&CreateBucket{Bucket: "site"}); err != nil {
+		t.Fatal("Could not create bucket", err)
+	}
+
+	public, _ := NewCannedACL(CannedPublicRead, fakeOwner)
+	change, err := s3.ApplyBucketACL(nil, "site", public)
+	if err != nil {
+		t.Fatal("Could not apply ACL", err)
+	}
+	assert.Equal(t, len(change.Added), 1)
+	assert.Equal(t, *change.Added[0].Grantee.URI, AllUsersGroup)
+	assert.Equal(t, fake.aclUpdates, 1)
+
+	resp, err := s3.GetBucketAccessControlPolicy(&GetBucketAccessControlPolicy{Bucket: "site"})
+	if err != nil {
+		t.Fatal("Could not get ACL", err)
+	}
+	grants := resp.GetBucketAccessControlPolicyResponse.AccessControlList.Grant
+	assert.Equal(t, len(grants), 2)
+	assert.Equal(t, grants[1].Grantee.Type, GranteeGroup)
+
+	change, err = s3.ApplyBucketACL(nil, "site", public)
+	if err != nil {
+		t.Fatal("Could not apply ACL", err)
+	}
+	assert.Equal(t, change.Empty(), true)
+	assert.Equal(t, fake.aclUpdates, 1)
+}
@@ -1,14 +1,16 @@
+// Code generated by wsdl-example generate; DO NOT EDIT.
+
+//go:generate go run github.com/luhonghai/wsdl-example generate --wsdl ../AmazonS3.wsdl --package aws --out . --file aws_s3.go --catalog ../schemas/catalog.txt --import github.com/luhonghai/wsdl-example/pkg/aws
+
 package aws
 
 import (
-	"bytes"
-	"crypto/tls"
+	"context"
 	"encoding/xml"
-	"io/ioutil"
-	"log"
-	"net"
-	"net/http"
 	"time"
+
+	"github.com/luhonghai/wsdl-example/pkg/soap"
+	"github.com/luhonghai/wsdl-example/pkg/xsd"
 )
 
 // against "unused imports"
@@ -22,25 +24,100 @@ const (
 
 	PermissionWRITE Permission = "WRITE"
 
-	PermissionREADACP Permission = "READACP"
+	PermissionREADACP Permission = "READ_ACP"
 
-	PermissionWRITEACP Permission = "WRITEACP"
+	PermissionWRITEACP Permission = "WRITE_ACP"
 
-	PermissionFULLCONTROL Permission = "FULLCONTROL"
+	PermissionFULLCONTROL Permission = "FULL_CONTROL"
 )
 
+// Values returns the values Permission is restricted to.
+func (Permission) Values() []Permission {
+	return []Permission{
+		PermissionREAD,
+		PermissionWRITE,
+		PermissionREADACP,
+		PermissionWRITEACP,
+		PermissionFULLCONTROL,
+	}
+}
+
+// Validate returns an *xsd.EnumError unless v is one of Values.
+func (v Permission) Validate() error {
+	return xsd.ValidateEnum("Permission", string(v), "READ", "WRITE", "READ_ACP", "WRITE_ACP", "FULL_CONTROL")
+}
+
+// MarshalText refuses values outside the enumeration unless
+// xsd.LenientEnums reports true.
+func (v Permission) MarshalText() ([]byte, error) {
+	if err := v.Validate(); err != nil && !xsd.LenientEnums() {
+		return nil, err
+	}
+
+	return []byte(v), nil
+}
+
+// UnmarshalText refuses values outside the enumeration unless
+// xsd.LenientEnums reports true.
+func (v *Permission) UnmarshalText(text []byte) error {
+	value := Permission(text)
+	if err := value.Validate(); err != nil && !xsd.LenientEnums() {
+		return err
+	}
+	*v = value
+
+	return nil
+}
+
 type StorageClass string
 
 const (
 	StorageClassSTANDARD StorageClass = "STANDARD"
 
-	StorageClassREDUCEDREDUNDANCY StorageClass = "REDUCEDREDUNDANCY"
+	StorageClassREDUCEDREDUNDANCY StorageClass = "REDUCED_REDUNDANCY"
 
 	StorageClassGLACIER StorageClass = "GLACIER"
 
 	StorageClassUNKNOWN StorageClass = "UNKNOWN"
 )
 
+// Values returns the values StorageClass is restricted to.
+func (StorageClass) Values() []StorageClass {
+	return []StorageClass{
+		StorageClassSTANDARD,
+		StorageClassREDUCEDREDUNDANCY,
+		StorageClassGLACIER,
+		StorageClassUNKNOWN,
+	}
+}
+
+// Validate returns an *xsd.EnumError unless v is one of Values.
+func (v StorageClass) Validate() error {
+	return xsd.ValidateEnum("StorageClass", string(v), "STANDARD", "REDUCED_REDUNDANCY", "GLACIER", "UNKNOWN")
+}
+
+// MarshalText refuses values outside the enumeration unless
+// xsd.LenientEnums reports true.
+func (v StorageClass) MarshalText() ([]byte, error) {
+	if err := v.Validate(); err != nil && !xsd.LenientEnums() {
+		return nil, err
+	}
+
+	return []byte(v), nil
+}
+
+// UnmarshalText refuses values outside the enumeration unless
+// xsd.LenientEnums reports true.
+func (v *StorageClass) UnmarshalText(text []byte) error {
+	value := StorageClass(text)
+	if err := value.Validate(); err != nil && !xsd.LenientEnums() {
+		return err
+	}
+	*v = value
+
+	return nil
+}
+
 type MetadataDirective string
 
 const (
@@ -49,6 +126,41 @@ const (
 	MetadataDirectiveREPLACE MetadataDirective = "REPLACE"
 )
 
+// Values returns the values MetadataDirective is restricted to.
+func (MetadataDirective) Values() []MetadataDirective {
+	return []MetadataDirective{
+		MetadataDirectiveCOPY,
+		MetadataDirectiveREPLACE,
+	}
+}
+
+// Validate returns an *xsd.EnumError unless v is one of Values.
+func (v MetadataDirective) Validate() error {
+	return xsd.ValidateEnum("MetadataDirective", string(v), "COPY", "REPLACE")
+}
+
+// MarshalText refuses values outside the enumeration unless
+// xsd.LenientEnums reports true.
+func (v MetadataDirective) MarshalText() ([]byte, error) {
+	if err := v.Validate(); err != nil && !xsd.LenientEnums() {
+		return nil, err
+	}
+
+	return []byte(v), nil
+}
+
+// UnmarshalText refuses values outside the enumeration unless
+// xsd.LenientEnums reports true.
+func (v *MetadataDirective) UnmarshalText(text []byte) error {
+	value := MetadataDirective(text)
+	if err := value.Validate(); err != nil && !xsd.LenientEnums() {
+		return err
+	}
+	*v = value
+
+	return nil
+}
+
 type Payer string
 
 const (
@@ -57,6 +169,41 @@ const (
 	PayerRequester Payer = "Requester"
 )
 
+// Values returns the values Payer is restricted to.
+func (Payer) Values() []Payer {
+	return []Payer{
+		PayerBucketOwner,
+		PayerRequester,
+	}
+}
+
+// Validate returns an *xsd.EnumError unless v is one of Values.
+func (v Payer) Validate() error {
+	return xsd.ValidateEnum("Payer", string(v), "BucketOwner", "Requester")
+}
+
+// MarshalText refuses values outside the enumeration unless
+// xsd.LenientEnums reports true.
+func (v Payer) MarshalText() ([]byte, error) {
+	if err := v.Validate(); err != nil && !xsd.LenientEnums() {
+		return nil, err
+	}
+
+	return []byte(v), nil
+}
+
+// UnmarshalText refuses values outside the enumeration unless
+// xsd.LenientEnums reports true.
+func (v *Payer) UnmarshalText(text []byte) error {
+	value := Payer(text)
+	if err := value.Validate(); err != nil && !xsd.LenientEnums() {
+		return err
+	}
+	*v = value
+
+	return nil
+}
+
 type MfaDeleteStatus string
 
 const (
@@ -65,6 +212,41 @@ const (
 	MfaDeleteStatusDisabled MfaDeleteStatus = "Disabled"
 )
 
+// Values returns the values MfaDeleteStatus is restricted to.
+func (MfaDeleteStatus) Values() []MfaDeleteStatus {
+	return []MfaDeleteStatus{
+		MfaDeleteStatusEnabled,
+		MfaDeleteStatusDisabled,
+	}
+}
+
+// Validate returns an *xsd.EnumError unless v is one of Values.
+func (v MfaDeleteStatus) Validate() error {
+	return xsd.ValidateEnum("MfaDeleteStatus", string(v), "Enabled", "Disabled")
+}
+
+// MarshalText refuses values outside the enumeration unless
+// xsd.LenientEnums reports true.
+func (v MfaDeleteStatus) MarshalText() ([]byte, error) {
+	if err := v.Validate(); err != nil && !xsd.LenientEnums() {
+		return nil, err
+	}
+
+	return []byte(v), nil
+}
+
+// UnmarshalText refuses values outside the enumeration unless
+// xsd.LenientEnums reports true.
+func (v *MfaDeleteStatus) UnmarshalText(text []byte) error {
+	value := MfaDeleteStatus(text)
+	if err := value.Validate(); err != nil && !xsd.LenientEnums() {
+		return err
+	}
+	*v = value
+
+	return nil
+}
+
 type VersioningStatus string
 
 const (
@@ -73,63 +255,99 @@ const (
 	VersioningStatusSuspended VersioningStatus = "Suspended"
 )
 
+// Values returns the values VersioningStatus is restricted to.
+func (VersioningStatus) Values() []VersioningStatus {
+	return []VersioningStatus{
+		VersioningStatusEnabled,
+		VersioningStatusSuspended,
+	}
+}
+
+// Validate returns an *xsd.EnumError unless v is one of Values.
+func (v VersioningStatus) Validate() error {
+	return xsd.ValidateEnum("VersioningStatus", string(v), "Enabled", "Suspended")
+}
+
+// MarshalText refuses values outside the enumeration unless
+// xsd.LenientEnums reports true.
+func (v VersioningStatus) MarshalText() ([]byte, error) {
+	if err := v.Validate(); err != nil && !xsd.LenientEnums() {
+		return nil, err
+	}
+
+	return []byte(v), nil
+}
+
+// UnmarshalText refuses values outside the enumeration unless
+// xsd.LenientEnums reports true.
+func (v *VersioningStatus) UnmarshalText(text []byte) error {
+	value := VersioningStatus(text)
+	if err := value.Validate(); err != nil && !xsd.LenientEnums() {
+		return err
+	}
+	*v = value
+
+	return nil
+}
+
 type CreateBucket struct {
 	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ CreateBucket"`
 
-	Bucket            string             `xml:"Bucket,omitempty"`
-	AccessControlList *AccessControlList `xml:"AccessControlList,omitempty"`
-	AWSAccessKeyId    string             `xml:"AWSAccessKeyId,omitempty"`
-	Timestamp         time.Time          `xml:"Timestamp,omitempty"`
-	Signature         string             `xml:"Signature,omitempty"`
+	Bucket                    string                     `xml:"Bucket"`
+	AccessControlList         *AccessControlList         `xml:"AccessControlList,omitempty"`
+	CreateBucketConfiguration *CreateBucketConfiguration `xml:"CreateBucketConfiguration,omitempty"`
+	AWSAccessKeyId            *string                    `xml:"AWSAccessKeyId,omitempty"`
+	Timestamp                 *xsd.DateTime              `xml:"Timestamp,omitempty"`
+	Signature                 *string                    `xml:"Signature,omitempty"`
 }
 
 type CreateBucketResponse struct {
 	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ CreateBucketResponse"`
 
-	CreateBucketReturn *CreateBucketResult `xml:"CreateBucketReturn,omitempty"`
+	CreateBucketReturn *CreateBucketResult `xml:"CreateBucketReturn"`
 }
 
 type DeleteBucket struct {
 	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ DeleteBucket"`
 
-	Bucket         string    `xml:"Bucket,omitempty"`
-	AWSAccessKeyId string    `xml:"AWSAccessKeyId,omitempty"`
-	Timestamp      time.Time `xml:"Timestamp,omitempty"`
-	Signature      string    `xml:"Signature,omitempty"`
-	Credential     string    `xml:"Credential,omitempty"`
+	Bucket         string        `xml:"Bucket"`
+	AWSAccessKeyId *string       `xml:"AWSAccessKeyId,omitempty"`
+	Timestamp      *xsd.DateTime `xml:"Timestamp,omitempty"`
+	Signature      *string       `xml:"Signature,omitempty"`
+	Credential     *string       `xml:"Credential,omitempty"`
 }
 
 type DeleteBucketResponse struct {
 	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ DeleteBucketResponse"`
 
-	DeleteBucketResponse *Status `xml:"DeleteBucketResponse,omitempty"`
+	DeleteBucketResponse *Status `xml:"DeleteBucketResponse"`
 }
 
 type GetBucketLoggingStatus struct {
 	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ GetBucketLoggingStatus"`
 
-	Bucket         string    `xml:"Bucket,omitempty"`
-	AWSAccessKeyId string    `xml:"AWSAccessKeyId,omitempty"`
-	Timestamp      time.Time `xml:"Timestamp,omitempty"`
-	Signature      string    `xml:"Signature,omitempty"`
-	Credential     string    `xml:"Credential,omitempty"`
+	Bucket         string        `xml:"Bucket"`
+	AWSAccessKeyId *string       `xml:"AWSAccessKeyId,omitempty"`
+	Timestamp      *xsd.DateTime `xml:"Timestamp,omitempty"`
+	Signature      *string       `xml:"Signature,omitempty"`
+	Credential     *string       `xml:"Credential,omitempty"`
 }
 
 type GetBucketLoggingStatusResponse struct {
 	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ GetBucketLoggingStatusResponse"`
 
-	GetBucketLoggingStatusResponse *BucketLoggingStatus `xml:"GetBucketLoggingStatusResponse,omitempty"`
+	GetBucketLoggingStatusResponse *BucketLoggingStatus `xml:"GetBucketLoggingStatusResponse"`
 }
 
 type SetBucketLoggingStatus struct {
 	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ SetBucketLoggingStatus"`
 
-	Bucket              string               `xml:"Bucket,omitempty"`
-	AWSAccessKeyId      string               `xml:"AWSAccessKeyId,omitempty"`
-	Timestamp           time.Time            `xml:"Timestamp,omitempty"`
-	Signature           string               `xml:"Signature,omitempty"`
-	Credential          string               `xml:"Credential,omitempty"`
-	BucketLoggingStatus *BucketLoggingStatus `xml:"BucketLoggingStatus,omitempty"`
+	Bucket              string               `xml:"Bucket"`
+	AWSAccessKeyId      *string              `xml:"AWSAccessKeyId,omitempty"`
+	Timestamp           *xsd.DateTime        `xml:"Timestamp,omitempty"`
+	Signature           *string              `xml:"Signature,omitempty"`
+	Credential          *string              `xml:"Credential,omitempty"`
+	BucketLoggingStatus *BucketLoggingStatus `xml:"BucketLoggingStatus"`
 }
 
 type SetBucketLoggingStatusResponse struct {
@@ -139,46 +357,46 @@ type SetBucketLoggingStatusResponse struct {
 type GetObjectAccessControlPolicy struct {
 	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ GetObjectAccessControlPolicy"`
 
-	Bucket         string    `xml:"Bucket,omitempty"`
-	Key            string    `xml:"Key,omitempty"`
-	AWSAccessKeyId string    `xml:"AWSAccessKeyId,omitempty"`
-	Timestamp      time.Time `xml:"Timestamp,omitempty"`
-	Signature      string    `xml:"Signature,omitempty"`
-	Credential     string    `xml:"Credential,omitempty"`
+	Bucket         string        `xml:"Bucket"`
+	Key            string        `xml:"Key"`
+	AWSAccessKeyId *string       `xml:"AWSAccessKeyId,omitempty"`
+	Timestamp      *xsd.DateTime `xml:"Timestamp,omitempty"`
+	Signature      *string       `xml:"Signature,omitempty"`
+	Credential     *string       `xml:"Credential,omitempty"`
 }
 
 type GetObjectAccessControlPolicyResponse struct {
 	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ GetObjectAccessControlPolicyResponse"`
 
-	GetObjectAccessControlPolicyResponse *AccessControlPolicy `xml:"GetObjectAccessControlPolicyResponse,omitempty"`
+	GetObjectAccessControlPolicyResponse *AccessControlPolicy `xml:"GetObjectAccessControlPolicyResponse"`
 }
 
 type GetBucketAccessControlPolicy struct {
 	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ GetBucketAccessControlPolicy"`
 
-	Bucket         string    `xml:"Bucket,omitempty"`
-	AWSAccessKeyId string    `xml:"AWSAccessKeyId,omitempty"`
-	Timestamp      time.Time `xml:"Timestamp,omitempty"`
-	Signature      string    `xml:"Signature,omitempty"`
-	Credential     string    `xml:"Credential,omitempty"`
+	Bucket         string        `xml:"Bucket"`
+	AWSAccessKeyId *string       `xml:"AWSAccessKeyId,omitempty"`
+	Timestamp      *xsd.DateTime `xml:"Timestamp,omitempty"`
+	Signature      *string       `xml:"Signature,omitempty"`
+	Credential     *string       `xml:"Credential,omitempty"`
 }
 
 type GetBucketAccessControlPolicyResponse struct {
 	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ GetBucketAccessControlPolicyResponse"`
 
-	GetBucketAccessControlPolicyResponse *AccessControlPolicy `xml:"GetBucketAccessControlPolicyResponse,omitempty"`
+	GetBucketAccessControlPolicyResponse *AccessControlPolicy `xml:"GetBucketAccessControlPolicyResponse"`
 }
 
 type SetObjectAccessControlPolicy struct {
 	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ SetObjectAccessControlPolicy"`
 
-	Bucket            string             `xml:"Bucket,omitempty"`
-	Key               string             `xml:"Key,omitempty"`
-	AccessControlList *AccessControlList `xml:"AccessControlList,omitempty"`
-	AWSAccessKeyId    string             `xml:"AWSAccessKeyId,omitempty"`
-	Timestamp         time.Time          `xml:"Timestamp,omitempty"`
-	Signature         string             `xml:"Signature,omitempty"`
-	Credential        string             `xml:"Credential,omitempty"`
+	Bucket            string             `xml:"Bucket"`
+	Key               string             `xml:"Key"`
+	AccessControlList *AccessControlList `xml:"AccessControlList"`
+	AWSAccessKeyId    *string            `xml:"AWSAccessKeyId,omitempty"`
+	Timestamp         *xsd.DateTime      `xml:"Timestamp,omitempty"`
+	Signature         *string            `xml:"Signature,omitempty"`
+	Credential        *string            `xml:"Credential,omitempty"`
 }
 
 type SetObjectAccessControlPolicyResponse struct {
@@ -188,12 +406,12 @@ type SetObjectAccessControlPolicyResponse struct {
 type SetBucketAccessControlPolicy struct {
 	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ SetBucketAccessControlPolicy"`
 
-	Bucket            string             `xml:"Bucket,omitempty"`
+	Bucket            string             `xml:"Bucket"`
 	AccessControlList *AccessControlList `xml:"AccessControlList,omitempty"`
-	AWSAccessKeyId    string             `xml:"AWSAccessKeyId,omitempty"`
-	Timestamp         time.Time          `xml:"Timestamp,omitempty"`
-	Signature         string             `xml:"Signature,omitempty"`
-	Credential        string             `xml:"Credential,omitempty"`
+	AWSAccessKeyId    *string            `xml:"AWSAccessKeyId,omitempty"`
+	Timestamp         *xsd.DateTime      `xml:"Timestamp,omitempty"`
+	Signature         *string            `xml:"Signature,omitempty"`
+	Credential        *string            `xml:"Credential,omitempty"`
 }
 
 type SetBucketAccessControlPolicyResponse struct {
@@ -203,447 +421,483 @@ type SetBucketAccessControlPolicyResponse struct {
 type GetObject struct {
 	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ GetObject"`
 
-	Bucket         string    `xml:"Bucket,omitempty"`
-	Key            string    `xml:"Key,omitempty"`
-	GetMetadata    bool      `xml:"GetMetadata,omitempty"`
-	GetData        bool      `xml:"GetData,omitempty"`
-	InlineData     bool      `xml:"InlineData,omitempty"`
-	AWSAccessKeyId string    `xml:"AWSAccessKeyId,omitempty"`
-	Timestamp      time.Time `xml:"Timestamp,omitempty"`
-	Signature      string    `xml:"Signature,omitempty"`
-	Credential     string    `xml:"Credential,omitempty"`
+	Bucket         string        `xml:"Bucket"`
+	Key            string        `xml:"Key"`
+	GetMetadata    bool          `xml:"GetMetadata"`
+	GetData        bool          `xml:"GetData"`
+	InlineData     bool          `xml:"InlineData"`
+	AWSAccessKeyId *string       `xml:"AWSAccessKeyId,omitempty"`
+	Timestamp      *xsd.DateTime `xml:"Timestamp,omitempty"`
+	Signature      *string       `xml:"Signature,omitempty"`
+	Credential     *string       `xml:"Credential,omitempty"`
 }
 
 type GetObjectResponse struct {
 	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ GetObjectResponse"`
 
-	GetObjectResponse *GetObjectResult `xml:"GetObjectResponse,omitempty"`
+	GetObjectResponse *GetObjectResult `xml:"GetObjectResponse"`
 }
 
 type GetObjectExtended struct {
 	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ GetObjectExtended"`
 
-	Bucket                                 string    `xml:"Bucket,omitempty"`
-	Key                                    string    `xml:"Key,omitempty"`
-	GetMetadata                            bool      `xml:"GetMetadata,omitempty"`
-	GetData                                bool      `xml:"GetData,omitempty"`
-	InlineData                             bool      `xml:"InlineData,omitempty"`
-	ByteRangeStart                         int64     `xml:"ByteRangeStart,omitempty"`
-	ByteRangeEnd                           int64     `xml:"ByteRangeEnd,omitempty"`
-	IfModifiedSince                        time.Time `xml:"IfModifiedSince,omitempty"`
-	IfUnmodifiedSince                      time.Time `xml:"IfUnmodifiedSince,omitempty"`
-	IfMatch                                string    `xml:"IfMatch,omitempty"`
-	IfNoneMatch                            string    `xml:"IfNoneMatch,omitempty"`
-	ReturnCompleteObjectOnConditionFailure bool      `xml:"ReturnCompleteObjectOnConditionFailure,omitempty"`
-	AWSAccessKeyId                         string    `xml:"AWSAccessKeyId,omitempty"`
-	Timestamp                              time.Time `xml:"Timestamp,omitempty"`
-	Signature                              string    `xml:"Signature,omitempty"`
-	Credential                             string    `xml:"Credential,omitempty"`
+	Bucket                                 string        `xml:"Bucket"`
+	Key                                    string        `xml:"Key"`
+	GetMetadata                            bool          `xml:"GetMetadata"`
+	GetData                                bool          `xml:"GetData"`
+	InlineData                             bool          `xml:"InlineData"`
+	ByteRangeStart                         *int64        `xml:"ByteRangeStart,omitempty"`
+	ByteRangeEnd                           *int64        `xml:"ByteRangeEnd,omitempty"`
+	IfModifiedSince                        *xsd.DateTime `xml:"IfModifiedSince,omitempty"`
+	IfUnmodifiedSince                      *xsd.DateTime `xml:"IfUnmodifiedSince,omitempty"`
+	IfMatch                                *string       `xml:"IfMatch,omitempty"`
+	IfNoneMatch                            *string       `xml:"IfNoneMatch,omitempty"`
+	ReturnCompleteObjectOnConditionFailure *bool         `xml:"ReturnCompleteObjectOnConditionFailure,omitempty"`
+	AWSAccessKeyId                         *string       `xml:"AWSAccessKeyId,omitempty"`
+	Timestamp                              *xsd.DateTime `xml:"Timestamp,omitempty"`
+	Signature                              *string       `xml:"Signature,omitempty"`
+	Credential                             *string       `xml:"Credential,omitempty"`
 }
 
 type GetObjectExtendedResponse struct {
 	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ GetObjectExtendedResponse"`
 
-	GetObjectResponse *GetObjectResult `xml:"GetObjectResponse,omitempty"`
+	GetObjectResponse *GetObjectResult `xml:"GetObjectResponse"`
 }
 
 type PutObject struct {
 	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ PutObject"`
 
-	Bucket            string             `xml:"Bucket,omitempty"`
-	Key               string             `xml:"Key,omitempty"`
-	Metadata          *MetadataEntry     `xml:"Metadata,omitempty"`
-	ContentLength     int64              `xml:"ContentLength,omitempty"`
+	Bucket            string             `xml:"Bucket"`
+	Key               string             `xml:"Key"`
+	Metadata          []*MetadataEntry   `xml:"Metadata,omitempty"`
+	ContentLength     int64              `xml:"ContentLength"`
 	AccessControlList *AccessControlList `xml:"AccessControlList,omitempty"`
 	StorageClass      *StorageClass      `xml:"StorageClass,omitempty"`
-	AWSAccessKeyId    string             `xml:"AWSAccessKeyId,omitempty"`
-	Timestamp         time.Time          `xml:"Timestamp,omitempty"`
-	Signature         string             `xml:"Signature,omitempty"`
-	Credential        string             `xml:"Credential,omitempty"`
+	AWSAccessKeyId    *string            `xml:"AWSAccessKeyId,omitempty"`
+	Timestamp         *xsd.DateTime      `xml:"Timestamp,omitempty"`
+	Signature         *string            `xml:"Signature,omitempty"`
+	Credential        *string            `xml:"Credential,omitempty"`
 }
 
 type PutObjectResponse struct {
 	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ PutObjectResponse"`
 
-	PutObjectResponse *PutObjectResult `xml:"PutObjectResponse,omitempty"`
+	PutObjectResponse *PutObjectResult `xml:"PutObjectResponse"`
 }
 
 type PutObjectInline struct {
 	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ PutObjectInline"`
 
-	Bucket            string             `xml:"Bucket,omitempty"`
-	Key               string             `xml:"Key,omitempty"`
-	Metadata          *MetadataEntry     `xml:"Metadata,omitempty"`
-	Data              []byte             `xml:"Data,omitempty"`
-	ContentLength     int64              `xml:"ContentLength,omitempty"`
+	Bucket            string             `xml:"Bucket"`
+	Key               string             `xml:"Key"`
+	Metadata          []*MetadataEntry   `xml:"Metadata,omitempty"`
+	Data              xsd.Base64Binary   `xml:"Data"`
+	ContentLength     int64              `xml:"ContentLength"`
 	AccessControlList *AccessControlList `xml:"AccessControlList,omitempty"`
 	StorageClass      *StorageClass      `xml:"StorageClass,omitempty"`
-	AWSAccessKeyId    string             `xml:"AWSAccessKeyId,omitempty"`
-	Timestamp         time.Time          `xml:"Timestamp,omitempty"`
-	Signature         string             `xml:"Signature,omitempty"`
-	Credential        string             `xml:"Credential,omitempty"`
+	AWSAccessKeyId    *string            `xml:"AWSAccessKeyId,omitempty"`
+	Timestamp         *xsd.DateTime      `xml:"Timestamp,omitempty"`
+	Signature         *string            `xml:"Signature,omitempty"`
+	Credential        *string            `xml:"Credential,omitempty"`
 }
 
 type PutObjectInlineResponse struct {
 	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ PutObjectInlineResponse"`
 
-	PutObjectInlineResponse *PutObjectResult `xml:"PutObjectInlineResponse,omitempty"`
+	PutObjectInlineResponse *PutObjectResult `xml:"PutObjectInlineResponse"`
 }
 
 type DeleteObject struct {
 	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ DeleteObject"`
 
-	Bucket         string    `xml:"Bucket,omitempty"`
-	Key            string    `xml:"Key,omitempty"`
-	AWSAccessKeyId string    `xml:"AWSAccessKeyId,omitempty"`
-	Timestamp      time.Time `xml:"Timestamp,omitempty"`
-	Signature      string    `xml:"Signature,omitempty"`
-	Credential     string    `xml:"Credential,omitempty"`
+	Bucket         string        `xml:"Bucket"`
+	Key            string        `xml:"Key"`
+	AWSAccessKeyId *string       `xml:"AWSAccessKeyId,omitempty"`
+	Timestamp      *xsd.DateTime `xml:"Timestamp,omitempty"`
+	Signature      *string       `xml:"Signature,omitempty"`
+	Credential     *string       `xml:"Credential,omitempty"`
 }
 
 type DeleteObjectResponse struct {
 	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ DeleteObjectResponse"`
 
-	DeleteObjectResponse *Status `xml:"DeleteObjectResponse,omitempty"`
+	DeleteObjectResponse *Status `xml:"DeleteObjectResponse"`
 }
 
 type ListBucket struct {
 	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ ListBucket"`
 
-	Bucket         string    `xml:"Bucket,omitempty"`
-	Prefix         string    `xml:"Prefix,omitempty"`
-	Marker         string    `xml:"Marker,omitempty"`
-	MaxKeys        int32     `xml:"MaxKeys,omitempty"`
-	Delimiter      string    `xml:"Delimiter,omitempty"`
-	AWSAccessKeyId string    `xml:"AWSAccessKeyId,omitempty"`
-	Timestamp      time.Time `xml:"Timestamp,omitempty"`
-	Signature      string    `xml:"Signature,omitempty"`
-	Credential     string    `xml:"Credential,omitempty"`
+	Bucket         string        `xml:"Bucket"`
+	Prefix         *string       `xml:"Prefix,omitempty"`
+	Marker         *string       `xml:"Marker,omitempty"`
+	MaxKeys        *int32        `xml:"MaxKeys,omitempty"`
+	Delimiter      *string       `xml:"Delimiter,omitempty"`
+	AWSAccessKeyId *string       `xml:"AWSAccessKeyId,omitempty"`
+	Timestamp      *xsd.DateTime `xml:"Timestamp,omitempty"`
+	Signature      *string       `xml:"Signature,omitempty"`
+	Credential     *string       `xml:"Credential,omitempty"`
 }
 
 type ListBucketResponse struct {
 	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ ListBucketResponse"`
 
-	ListBucketResponse *ListBucketResult `xml:"ListBucketResponse,omitempty"`
+	ListBucketResponse *ListBucketResult `xml:"ListBucketResponse"`
 }
 
 type ListVersionsResponse struct {
 	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ ListVersionsResponse"`
 
-	ListVersionsResponse *ListVersionsResult `xml:"ListVersionsResponse,omitempty"`
+	ListVersionsResponse *ListVersionsResult `xml:"ListVersionsResponse"`
 }
 
 type ListAllMyBuckets struct {
 	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ ListAllMyBuckets"`
 
-	AWSAccessKeyId string    `xml:"AWSAccessKeyId,omitempty"`
-	Timestamp      time.Time `xml:"Timestamp,omitempty"`
-	Signature      string    `xml:"Signature,omitempty"`
+	AWSAccessKeyId *string       `xml:"AWSAccessKeyId,omitempty"`
+	Timestamp      *xsd.DateTime `xml:"Timestamp,omitempty"`
+	Signature      *string       `xml:"Signature,omitempty"`
 }
 
 type ListAllMyBucketsResponse struct {
 	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ ListAllMyBucketsResponse"`
 
-	ListAllMyBucketsResponse *ListAllMyBucketsResult `xml:"ListAllMyBucketsResponse,omitempty"`
+	ListAllMyBucketsResponse *ListAllMyBucketsResult `xml:"ListAllMyBucketsResponse"`
 }
 
 type PostResponse struct {
 	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ PostResponse"`
 
-	Bucket string `xml:"Bucket,omitempty"`
-	Key    string `xml:"Key,omitempty"`
-	ETag   string `xml:"ETag,omitempty"`
+	Bucket string `xml:"Bucket"`
+	Key    string `xml:"Key"`
+	ETag   string `xml:"ETag"`
 }
 
 type CopyObject struct {
 	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ CopyObject"`
 
-	SourceBucket                string             `xml:"SourceBucket,omitempty"`
-	SourceKey                   string             `xml:"SourceKey,omitempty"`
-	DestinationBucket           string             `xml:"DestinationBucket,omitempty"`
-	DestinationKey              string             `xml:"DestinationKey,omitempty"`
+	SourceBucket                string             `xml:"SourceBucket"`
+	SourceKey                   string             `xml:"SourceKey"`
+	DestinationBucket           string             `xml:"DestinationBucket"`
+	DestinationKey              string             `xml:"DestinationKey"`
 	MetadataDirective           *MetadataDirective `xml:"MetadataDirective,omitempty"`
-	Metadata                    *MetadataEntry     `xml:"Metadata,omitempty"`
+	Metadata                    []*MetadataEntry   `xml:"Metadata,omitempty"`
 	AccessControlList           *AccessControlList `xml:"AccessControlList,omitempty"`
-	CopySourceIfModifiedSince   time.Time          `xml:"CopySourceIfModifiedSince,omitempty"`
-	CopySourceIfUnmodifiedSince time.Time          `xml:"CopySourceIfUnmodifiedSince,omitempty"`
-	CopySourceIfMatch           string             `xml:"CopySourceIfMatch,omitempty"`
-	CopySourceIfNoneMatch       string             `xml:"CopySourceIfNoneMatch,omitempty"`
+	CopySourceIfModifiedSince   *xsd.DateTime      `xml:"CopySourceIfModifiedSince,omitempty"`
+	CopySourceIfUnmodifiedSince *xsd.DateTime      `xml:"CopySourceIfUnmodifiedSince,omitempty"`
+	CopySourceIfMatch           *string            `xml:"CopySourceIfMatch,omitempty"`
+	CopySourceIfNoneMatch       *string            `xml:"CopySourceIfNoneMatch,omitempty"`
 	StorageClass                *StorageClass      `xml:"StorageClass,omitempty"`
-	AWSAccessKeyId              string             `xml:"AWSAccessKeyId,omitempty"`
-	Timestamp                   time.Time          `xml:"Timestamp,omitempty"`
-	Signature                   string             `xml:"Signature,omitempty"`
-	Credential                  string             `xml:"Credential,omitempty"`
+	AWSAccessKeyId              *string            `xml:"AWSAccessKeyId,omitempty"`
+	Timestamp                   *xsd.DateTime      `xml:"Timestamp,omitempty"`
+	Signature                   *string            `xml:"Signature,omitempty"`
+	Credential                  *string            `xml:"Credential,omitempty"`
 }
 
 type CopyObjectResponse struct {
 	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ CopyObjectResponse"`
 
-	CopyObjectResult *CopyObjectResult `xml:"CopyObjectResult,omitempty"`
+	CopyObjectResult *CopyObjectResult `xml:"CopyObjectResult"`
 }
 
-type MetadataEntry struct {
-	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ MetadataEntry"`
+type GetBucketNotification struct {
+	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ GetBucketNotification"`
 
-	Name  string `xml:"Name,omitempty"`
-	Value string `xml:"Value,omitempty"`
+	Bucket         string        `xml:"Bucket"`
+	AWSAccessKeyId *string       `xml:"AWSAccessKeyId,omitempty"`
+	Timestamp      *xsd.DateTime `xml:"Timestamp,omitempty"`
+	Signature      *string       `xml:"Signature,omitempty"`
+	Credential     *string       `xml:"Credential,omitempty"`
 }
 
-type Status struct {
-	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ Status"`
+type GetBucketNotificationResponse struct {
+	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ GetBucketNotificationResponse"`
 
-	Code        int32  `xml:"Code,omitempty"`
-	Description string `xml:"Description,omitempty"`
+	GetBucketNotificationResponse *NotificationConfiguration `xml:"GetBucketNotificationResponse"`
 }
 
-type Result struct {
-	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ Result"`
+type SetBucketNotification struct {
+	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ SetBucketNotification"`
 
-	Status *Status `xml:"Status,omitempty"`
+	Bucket                    string                     `xml:"Bucket"`
+	AWSAccessKeyId            *string                    `xml:"AWSAccessKeyId,omitempty"`
+	Timestamp                 *xsd.DateTime              `xml:"Timestamp,omitempty"`
+	Signature                 *string                    `xml:"Signature,omitempty"`
+	Credential                *string                    `xml:"Credential,omitempty"`
+	NotificationConfiguration *NotificationConfiguration `xml:"NotificationConfiguration"`
 }
 
-type CreateBucketResult struct {
-	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ CreateBucketResult"`
+type SetBucketNotificationResponse struct {
+	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ SetBucketNotificationResponse"`
+}
 
-	BucketName string `xml:"BucketName,omitempty"`
+type GetBucketLocation struct {
+	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ GetBucketLocation"`
+
+	Bucket         string        `xml:"Bucket"`
+	AWSAccessKeyId *string       `xml:"AWSAccessKeyId,omitempty"`
+	Timestamp      *xsd.DateTime `xml:"Timestamp,omitempty"`
+	Signature      *string       `xml:"Signature,omitempty"`
+	Credential     *string       `xml:"Credential,omitempty"`
 }
 
-type BucketLoggingStatus struct {
-	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ BucketLoggingStatus"`
+type GetBucketLocationResponse struct {
+	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ GetBucketLocationResponse"`
+
+	GetBucketLocationResponse *LocationConstraint `xml:"GetBucketLocationResponse"`
+}
+
+type MetadataEntry struct {
+	Name  string `xml:"Name"`
+	Value string `xml:"Value"`
+}
+
+type Status struct {
+	Code        int32  `xml:"Code"`
+	Description string `xml:"Description"`
+}
+
+type Result struct {
+	Status *Status `xml:"Status"`
+}
+
+type CreateBucketResult struct {
+	BucketName string `xml:"BucketName"`
+}
 
+type BucketLoggingStatus struct {
 	LoggingEnabled *LoggingSettings `xml:"LoggingEnabled,omitempty"`
 }
 
 type LoggingSettings struct {
-	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ LoggingSettings"`
-
-	TargetBucket string             `xml:"TargetBucket,omitempty"`
-	TargetPrefix string             `xml:"TargetPrefix,omitempty"`
+	TargetBucket string             `xml:"TargetBucket"`
+	TargetPrefix string             `xml:"TargetPrefix"`
 	TargetGrants *AccessControlList `xml:"TargetGrants,omitempty"`
 }
 
 type Grantee struct {
-	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ Grantee"`
+	Type string `xml:"http://www.w3.org/2001/XMLSchema-instance type,attr,omitempty"`
+
+	EmailAddress *string `xml:"EmailAddress,omitempty"`
+	ID           *string `xml:"ID,omitempty"`
+	DisplayName  *string `xml:"DisplayName,omitempty"`
+	URI          *string `xml:"URI,omitempty"`
 }
 
 type User struct {
-	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ User"`
+	Type string `xml:"http://www.w3.org/2001/XMLSchema-instance type,attr,omitempty"`
 
-	*Grantee
+	EmailAddress *string `xml:"EmailAddress,omitempty"`
+	ID           *string `xml:"ID,omitempty"`
+	DisplayName  *string `xml:"DisplayName,omitempty"`
 }
 
 type AmazonCustomerByEmail struct {
-	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ AmazonCustomerByEmail"`
-
-	*User
-
-	EmailAddress string `xml:"EmailAddress,omitempty"`
+	EmailAddress string `xml:"EmailAddress"`
 }
 
 type CanonicalUser struct {
-	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ CanonicalUser"`
-
-	*User
-
-	ID          string `xml:"ID,omitempty"`
-	DisplayName string `xml:"DisplayName,omitempty"`
+	ID          string  `xml:"ID"`
+	DisplayName *string `xml:"DisplayName,omitempty"`
 }
 
 type Group struct {
-	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ Group"`
-
-	*Grantee
-
-	URI string `xml:"URI,omitempty"`
+	URI string `xml:"URI"`
 }
 
 type Grant struct {
-	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ Grant"`
-
-	Grantee    *Grantee    `xml:"Grantee,omitempty"`
-	Permission *Permission `xml:"Permission,omitempty"`
+	Grantee    *Grantee   `xml:"Grantee"`
+	Permission Permission `xml:"Permission"`
 }
 
 type AccessControlList struct {
-	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ AccessControlList"`
-
-	Grant *Grant `xml:"Grant,omitempty"`
+	Grant []*Grant `xml:"Grant,omitempty"`
 }
 
 type CreateBucketConfiguration struct {
-	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ CreateBucketConfiguration"`
-
-	LocationConstraint *LocationConstraint `xml:"LocationConstraint,omitempty"`
+	LocationConstraint *LocationConstraint `xml:"LocationConstraint"`
 }
 
 type LocationConstraint struct {
-	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ LocationConstraint"`
-
-	Value string
+	Value string `xml:",chardata"`
 }
 
 type AccessControlPolicy struct {
-	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ AccessControlPolicy"`
-
-	Owner             *CanonicalUser     `xml:"Owner,omitempty"`
-	AccessControlList *AccessControlList `xml:"AccessControlList,omitempty"`
+	Owner             *CanonicalUser     `xml:"Owner"`
+	AccessControlList *AccessControlList `xml:"AccessControlList"`
 }
 
 type GetObjectResult struct {
-	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ GetObjectResult"`
-
-	*Result
-
-	Metadata     []*MetadataEntry `xml:"Metadata,omitempty"`
-	Data         []byte           `xml:"Data,omitempty"`
-	LastModified time.Time        `xml:"LastModified,omitempty"`
-	ETag         string           `xml:"ETag,omitempty"`
+	Status       *Status              `xml:"Status"`
+	Metadata     []*MetadataEntry     `xml:"Metadata,omitempty"`
+	Data         NillableBase64Binary `xml:"Data"`
+	LastModified xsd.DateTime         `xml:"LastModified"`
+	ETag         string               `xml:"ETag"`
 }
 
 type PutObjectResult struct {
-	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ PutObjectResult"`
-
-	ETag         string    `xml:"ETag,omitempty"`
-	LastModified time.Time `xml:"LastModified,omitempty"`
+	ETag         string       `xml:"ETag"`
+	LastModified xsd.DateTime `xml:"LastModified"`
 }
 
 type ListEntry struct {
-	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ ListEntry"`
-
-	Key          string         `xml:"Key,omitempty"`
-	LastModified time.Time      `xml:"LastModified,omitempty"`
-	ETag         string         `xml:"ETag,omitempty"`
-	Size         int64          `xml:"Size,omitempty"`
+	Key          string         `xml:"Key"`
+	LastModified xsd.DateTime   `xml:"LastModified"`
+	ETag         string         `xml:"ETag"`
+	Size         int64          `xml:"Size"`
 	Owner        *CanonicalUser `xml:"Owner,omitempty"`
-	StorageClass *StorageClass  `xml:"StorageClass,omitempty"`
+	StorageClass StorageClass   `xml:"StorageClass"`
 }
 
 type VersionEntry struct {
-	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ VersionEntry"`
-
-	Key          string         `xml:"Key,omitempty"`
-	VersionId    string         `xml:"VersionId,omitempty"`
-	IsLatest     bool           `xml:"IsLatest,omitempty"`
-	LastModified time.Time      `xml:"LastModified,omitempty"`
-	ETag         string         `xml:"ETag,omitempty"`
-	Size         int64          `xml:"Size,omitempty"`
+	Key          string         `xml:"Key"`
+	VersionId    string         `xml:"VersionId"`
+	IsLatest     bool           `xml:"IsLatest"`
+	LastModified xsd.DateTime   `xml:"LastModified"`
+	ETag         string         `xml:"ETag"`
+	Size         int64          `xml:"Size"`
 	Owner        *CanonicalUser `xml:"Owner,omitempty"`
-	StorageClass *StorageClass  `xml:"StorageClass,omitempty"`
+	StorageClass StorageClass   `xml:"StorageClass"`
 }
 
 type DeleteMarkerEntry struct {
-	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ DeleteMarkerEntry"`
-
-	Key          string         `xml:"Key,omitempty"`
-	VersionId    string         `xml:"VersionId,omitempty"`
-	IsLatest     bool           `xml:"IsLatest,omitempty"`
-	LastModified time.Time      `xml:"LastModified,omitempty"`
+	Key          string         `xml:"Key"`
+	VersionId    string         `xml:"VersionId"`
+	IsLatest     bool           `xml:"IsLatest"`
+	LastModified xsd.DateTime   `xml:"LastModified"`
 	Owner        *CanonicalUser `xml:"Owner,omitempty"`
 }
 
 type PrefixEntry struct {
-	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ PrefixEntry"`
-
-	Prefix string `xml:"Prefix,omitempty"`
+	Prefix string `xml:"Prefix"`
 }
 
 type ListBucketResult struct {
-	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ ListBucketResult"`
-
 	Metadata       []*MetadataEntry `xml:"Metadata,omitempty"`
-	Name           string           `xml:"Name,omitempty"`
-	Prefix         string           `xml:"Prefix,omitempty"`
-	Marker         string           `xml:"Marker,omitempty"`
-	NextMarker     string           `xml:"NextMarker,omitempty"`
-	MaxKeys        int32            `xml:"MaxKeys,omitempty"`
-	Delimiter      string           `xml:"Delimiter,omitempty"`
-	IsTruncated    bool             `xml:"IsTruncated,omitempty"`
+	Name           string           `xml:"Name"`
+	Prefix         string           `xml:"Prefix"`
+	Marker         string           `xml:"Marker"`
+	NextMarker     *string          `xml:"NextMarker,omitempty"`
+	MaxKeys        int32            `xml:"MaxKeys"`
+	Delimiter      *string          `xml:"Delimiter,omitempty"`
+	IsTruncated    bool             `xml:"IsTruncated"`
 	Contents       []*ListEntry     `xml:"Contents,omitempty"`
 	CommonPrefixes []*PrefixEntry   `xml:"CommonPrefixes,omitempty"`
 }
 
 type ListVersionsResult struct {
-	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ ListVersionsResult"`
-
-	Metadata            []*MetadataEntry `xml:"Metadata,omitempty"`
-	Name                string           `xml:"Name,omitempty"`
-	Prefix              string           `xml:"Prefix,omitempty"`
-	KeyMarker           string           `xml:"KeyMarker,omitempty"`
-	VersionIdMarker     string           `xml:"VersionIdMarker,omitempty"`
-	NextKeyMarker       string           `xml:"NextKeyMarker,omitempty"`
-	NextVersionIdMarker string           `xml:"NextVersionIdMarker,omitempty"`
-	MaxKeys             int32            `xml:"MaxKeys,omitempty"`
-	Delimiter           string           `xml:"Delimiter,omitempty"`
-	IsTruncated         bool             `xml:"IsTruncated,omitempty"`
-	CommonPrefixes      []*PrefixEntry   `xml:"CommonPrefixes,omitempty"`
-
-	Version      *VersionEntry      `xml:"Version,omitempty"`
-	DeleteMarker *DeleteMarkerEntry `xml:"DeleteMarker,omitempty"`
+	Metadata            []*MetadataEntry     `xml:"Metadata,omitempty"`
+	Name                string               `xml:"Name"`
+	Prefix              string               `xml:"Prefix"`
+	KeyMarker           string               `xml:"KeyMarker"`
+	VersionIdMarker     string               `xml:"VersionIdMarker"`
+	NextKeyMarker       *string              `xml:"NextKeyMarker,omitempty"`
+	NextVersionIdMarker *string              `xml:"NextVersionIdMarker,omitempty"`
+	MaxKeys             int32                `xml:"MaxKeys"`
+	Delimiter           *string              `xml:"Delimiter,omitempty"`
+	IsTruncated         bool                 `xml:"IsTruncated"`
+	Version             []*VersionEntry      `xml:"Version,omitempty"`
+	DeleteMarker        []*DeleteMarkerEntry `xml:"DeleteMarker,omitempty"`
+	CommonPrefixes      []*PrefixEntry       `xml:"CommonPrefixes,omitempty"`
 }
 
 type ListAllMyBucketsEntry struct {
-	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ ListAllMyBucketsEntry"`
-
-	Name         string    `xml:"Name,omitempty"`
-	CreationDate time.Time `xml:"CreationDate,omitempty"`
+	Name         string       `xml:"Name"`
+	CreationDate xsd.DateTime `xml:"CreationDate"`
 }
 
 type ListAllMyBucketsResult struct {
-	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ ListAllMyBucketsResult"`
-
-	Owner   *CanonicalUser        `xml:"Owner,omitempty"`
-	Buckets *ListAllMyBucketsList `xml:"Buckets,omitempty"`
+	Owner   *CanonicalUser        `xml:"Owner"`
+	Buckets *ListAllMyBucketsList `xml:"Buckets"`
 }
 
 type ListAllMyBucketsList struct {
-	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ ListAllMyBucketsList"`
-
 	Bucket []*ListAllMyBucketsEntry `xml:"Bucket,omitempty"`
 }
 
 type CopyObjectResult struct {
-	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ CopyObjectResult"`
-
-	LastModified time.Time `xml:"LastModified,omitempty"`
-	ETag         string    `xml:"ETag,omitempty"`
+	LastModified xsd.DateTime `xml:"LastModified"`
+	ETag         string       `xml:"ETag"`
 }
 
 type RequestPaymentConfiguration struct {
-	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ RequestPaymentConfiguration"`
-
-	Payer *Payer `xml:"Payer,omitempty"`
+	Payer Payer `xml:"Payer"`
 }
 
 type VersioningConfiguration struct {
-	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ VersioningConfiguration"`
-
 	Status    *VersioningStatus `xml:"Status,omitempty"`
 	MfaDelete *MfaDeleteStatus  `xml:"MfaDelete,omitempty"`
 }
 
 type NotificationConfiguration struct {
-	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ NotificationConfiguration"`
-
 	TopicConfiguration []*TopicConfiguration `xml:"TopicConfiguration,omitempty"`
 }
 
 type TopicConfiguration struct {
-	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ TopicConfiguration"`
+	Topic string   `xml:"Topic"`
+	Event []string `xml:"Event"`
+}
+
+// NillableBase64Binary is the value of a nillable element; Nil stands for xsi:nil.
+type NillableBase64Binary struct {
+	Value xsd.Base64Binary
+	Nil   bool
+}
+
+func (n NillableBase64Binary) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return xsd.MarshalNillable(e, start, n.Nil, n.Value)
+}
+
+func (n *NillableBase64Binary) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var err error
+	n.Nil, err = xsd.UnmarshalNillable(d, start, &n.Value)
+
+	return err
+}
 
-	Topic string   `xml:"Topic,omitempty"`
-	Event []string `xml:"Event,omitempty"`
+// AmazonS3Client is the interface of the AmazonS3 port.
+//
+// AmazonS3 implements it, and so does the fake of the same name in
+// package awsfake, for tests.
+type AmazonS3Client interface {
+	CreateBucket(request *CreateBucket) (*CreateBucketResponse, error)
+	DeleteBucket(request *DeleteBucket) (*DeleteBucketResponse, error)
+	GetObjectAccessControlPolicy(request *GetObjectAccessControlPolicy) (*GetObjectAccessControlPolicyResponse, error)
+	GetBucketAccessControlPolicy(request *GetBucketAccessControlPolicy) (*GetBucketAccessControlPolicyResponse, error)
+	SetObjectAccessControlPolicy(request *SetObjectAccessControlPolicy) (*SetObjectAccessControlPolicyResponse, error)
+	SetBucketAccessControlPolicy(request *SetBucketAccessControlPolicy) (*SetBucketAccessControlPolicyResponse, error)
+	GetObject(request *GetObject) (*GetObjectResponse, error)
+	GetObjectExtended(request *GetObjectExtended) (*GetObjectExtendedResponse, error)
+	PutObject(request *PutObject) (*PutObjectResponse, error)
+	PutObjectInline(request *PutObjectInline) (*PutObjectInlineResponse, error)
+	DeleteObject(request *DeleteObject) (*DeleteObjectResponse, error)
+	ListBucket(request *ListBucket) (*ListBucketResponse, error)
+	ListAllMyBuckets(request *ListAllMyBuckets) (*ListAllMyBucketsResponse, error)
+	GetBucketLoggingStatus(request *GetBucketLoggingStatus) (*GetBucketLoggingStatusResponse, error)
+	SetBucketLoggingStatus(request *SetBucketLoggingStatus) (*SetBucketLoggingStatusResponse, error)
+	CopyObject(request *CopyObject) (*CopyObjectResponse, error)
+	GetBucketNotification(request *GetBucketNotification) (*GetBucketNotificationResponse, error)
+	SetBucketNotification(request *SetBucketNotification) (*SetBucketNotificationResponse, error)
+	GetBucketLocation(request *GetBucketLocation) (*GetBucketLocationResponse, error)
 }
 
 type AmazonS3 struct {
-	client *SOAPClient
+	client *soap.Client
 }
 
-func NewAmazonS3(url string, tls bool, auth *BasicAuth) *AmazonS3 {
+var _ AmazonS3Client = (*AmazonS3)(nil)
+
+func NewAmazonS3(url string, tls bool, auth *soap.BasicAuth) *AmazonS3 {
 	if url == "" {
 		url = "https://s3.amazonaws.com/soap"
 	}
-	client := NewSOAPClient(url, tls, auth)
+	client := soap.NewClient(url, tls, auth)
+
+	return NewAmazonS3WithClient(client)
+}
 
+// NewAmazonS3WithClient returns a AmazonS3 making its calls through client.
+func NewAmazonS3WithClient(client *soap.Client) *AmazonS3 {
 	return &AmazonS3{
 		client: client,
 	}
@@ -761,7 +1015,7 @@ func (service *AmazonS3) DeleteObject(request *DeleteObject) (*DeleteObjectRespo
 
 func (service *AmazonS3) ListBucket(request *ListBucket) (*ListBucketResponse, error) {
 	response := new(ListBucketResponse)
-	err := service.client.Call("http://s3.amazonaws.com/doc/2006-03-01/ListBucket", request, response)
+	err := service.client.Call("", request, response)
 	if err != nil {
 		return nil, err
 	}
@@ -771,7 +1025,7 @@ func (service *AmazonS3) ListBucket(request *ListBucket) (*ListBucketResponse, e
 
 func (service *AmazonS3) ListAllMyBuckets(request *ListAllMyBuckets) (*ListAllMyBucketsResponse, error) {
 	response := new(ListAllMyBucketsResponse)
-	err := service.client.Call("http://s3.amazonaws.com/doc/2006-03-01/ListAllMyBuckets", request, response)
+	err := service.client.Call("", request, response)
 	if err != nil {
 		return nil, err
 	}
@@ -809,183 +1063,234 @@ func (service *AmazonS3) CopyObject(request *CopyObject) (*CopyObjectResponse, e
 	return response, nil
 }
 
-var timeout = time.Duration(30 * time.Second)
-
-func dialTimeout(network, addr string) (net.Conn, error) {
-	return net.DialTimeout(network, addr, timeout)
-}
-
-type SOAPEnvelope struct {
-	XMLName xml.Name `xml:"http://schemas.xmlsoap.org/soap/envelope/ Envelope"`
-	Header  SOAPHeader
-	Body    SOAPBody
-}
-
-type SOAPHeader struct {
-	XMLName xml.Name `xml:"http://schemas.xmlsoap.org/soap/envelope/ Header"`
-
-	Header interface{}
-}
-
-type SOAPBody struct {
-	XMLName xml.Name `xml:"http://schemas.xmlsoap.org/soap/envelope/ Body"`
-
-	Fault   *SOAPFault  `xml:",omitempty"`
-	Content interface{} `xml:",omitempty"`
-}
-
-type SOAPFault struct {
-	XMLName xml.Name `xml:"http://schemas.xmlsoap.org/soap/envelope/ Fault"`
-
-	Code   string `xml:"faultcode,omitempty"`
-	String string `xml:"faultstring,omitempty"`
-	Actor  string `xml:"faultactor,omitempty"`
-	Detail string `xml:"detail,omitempty"`
-}
-
-type BasicAuth struct {
-	Login    string
-	Password string
-}
-
-type SOAPClient struct {
-	url  string
-	tls  bool
-	auth *BasicAuth
-}
-
-func (b *SOAPBody) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
-	if b.Content == nil {
-		return xml.UnmarshalError("Content must be a pointer to a struct")
-	}
-
-	var (
-		token    xml.Token
-		err      error
-		consumed bool
-	)
-
-Loop:
-	for {
-		if token, err = d.Token(); err != nil {
-			return err
-		}
-
-		if token == nil {
-			break
-		}
-
-		switch se := token.(type) {
-		case xml.StartElement:
-			if consumed {
-				return xml.UnmarshalError("Found multiple elements inside SOAP body; not wrapped-document/literal WS-I compliant")
-			} else if se.Name.Space == "http://schemas.xmlsoap.org/soap/envelope/" && se.Name.Local == "Fault" {
-				b.Fault = &SOAPFault{}
-				b.Content = nil
-
-				err = d.DecodeElement(b.Fault, &se)
-				if err != nil {
-					return err
-				}
-
-				consumed = true
-			} else {
-				if err = d.DecodeElement(b.Content, &se); err != nil {
-					return err
-				}
-
-				consumed = true
-			}
-		case xml.EndElement:
-			break Loop
-		}
-	}
-
-	return nil
-}
-
-func (f *SOAPFault) Error() string {
-	return f.String
-}
-
-func NewSOAPClient(url string, tls bool, auth *BasicAuth) *SOAPClient {
-	return &SOAPClient{
-		url:  url,
-		tls:  tls,
-		auth: auth,
-	}
-}
-
-func (s *SOAPClient) Call(soapAction string, request, response interface{}) error {
-	envelope := SOAPEnvelope{}
-
-	envelope.Body.Content = request
-	buffer := new(bytes.Buffer)
-
-	encoder := xml.NewEncoder(buffer)
-	//encoder.Indent("  ", "    ")
-
-	if err := encoder.Encode(envelope); err != nil {
-		return err
-	}
-
-	if err := encoder.Flush(); err != nil {
-		return err
-	}
-
-	log.Println(buffer.String())
-
-	req, err := http.NewRequest("POST", s.url, buffer)
+func (service *AmazonS3) GetBucketNotification(request *GetBucketNotification) (*GetBucketNotificationResponse, error) {
+	response := new(GetBucketNotificationResponse)
+	err := service.client.Call("", request, response)
 	if err != nil {
-		return err
-	}
-	if s.auth != nil {
-		req.SetBasicAuth(s.auth.Login, s.auth.Password)
-	}
-
-	req.Header.Add("Content-Type", "text/xml; charset=\"utf-8\"")
-	if soapAction != "" {
-		req.Header.Add("SOAPAction", soapAction)
+		return nil, err
 	}
 
-	req.Header.Set("User-Agent", "gowsdl/0.1")
-	req.Close = true
-
-	tr := &http.Transport{
-		TLSClientConfig: &tls.Config{
-			InsecureSkipVerify: s.tls,
-		},
-		Dial: dialTimeout,
-	}
+	return response, nil
+}
 
-	client := &http.Client{Transport: tr}
-	res, err := client.Do(req)
+func (service *AmazonS3) SetBucketNotification(request *SetBucketNotification) (*SetBucketNotificationResponse, error) {
+	response := new(SetBucketNotificationResponse)
+	err := service.client.Call("", request, response)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	defer res.Body.Close()
 
-	rawbody, err := ioutil.ReadAll(res.Body)
-	if err != nil {
-		return err
-	}
-	if len(rawbody) == 0 {
-		log.Println("empty response")
-		return nil
-	}
+	return response, nil
+}
 
-	log.Println(string(rawbody))
-	respEnvelope := new(SOAPEnvelope)
-	respEnvelope.Body = SOAPBody{Content: response}
-	err = xml.Unmarshal(rawbody, respEnvelope)
+func (service *AmazonS3) GetBucketLocation(request *GetBucketLocation) (*GetBucketLocationResponse, error) {
+	response := new(GetBucketLocationResponse)
+	err := service.client.Call("", request, response)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	fault := respEnvelope.Body.Fault
-	if fault != nil {
-		return fault
-	}
+	return response, nil
+}
 
-	return nil
+// AmazonS3Server is implemented by services serving the AmazonS3 port.
+type AmazonS3Server interface {
+	CreateBucket(ctx context.Context, request *CreateBucket) (*CreateBucketResponse, error)
+	DeleteBucket(ctx context.Context, request *DeleteBucket) (*DeleteBucketResponse, error)
+	GetObjectAccessControlPolicy(ctx context.Context, request *GetObjectAccessControlPolicy) (*GetObjectAccessControlPolicyResponse, error)
+	GetBucketAccessControlPolicy(ctx context.Context, request *GetBucketAccessControlPolicy) (*GetBucketAccessControlPolicyResponse, error)
+	SetObjectAccessControlPolicy(ctx context.Context, request *SetObjectAccessControlPolicy) (*SetObjectAccessControlPolicyResponse, error)
+	SetBucketAccessControlPolicy(ctx context.Context, request *SetBucketAccessControlPolicy) (*SetBucketAccessControlPolicyResponse, error)
+	GetObject(ctx context.Context, request *GetObject) (*GetObjectResponse, error)
+	GetObjectExtended(ctx context.Context, request *GetObjectExtended) (*GetObjectExtendedResponse, error)
+	PutObject(ctx context.Context, request *PutObject) (*PutObjectResponse, error)
+	PutObjectInline(ctx context.Context, request *PutObjectInline) (*PutObjectInlineResponse, error)
+	DeleteObject(ctx context.Context, request *DeleteObject) (*DeleteObjectResponse, error)
+	ListBucket(ctx context.Context, request *ListBucket) (*ListBucketResponse, error)
+	ListAllMyBuckets(ctx context.Context, request *ListAllMyBuckets) (*ListAllMyBucketsResponse, error)
+	GetBucketLoggingStatus(ctx context.Context, request *GetBucketLoggingStatus) (*GetBucketLoggingStatusResponse, error)
+	SetBucketLoggingStatus(ctx context.Context, request *SetBucketLoggingStatus) (*SetBucketLoggingStatusResponse, error)
+	CopyObject(ctx context.Context, request *CopyObject) (*CopyObjectResponse, error)
+	GetBucketNotification(ctx context.Context, request *GetBucketNotification) (*GetBucketNotificationResponse, error)
+	SetBucketNotification(ctx context.Context, request *SetBucketNotification) (*SetBucketNotificationResponse, error)
+	GetBucketLocation(ctx context.Context, request *GetBucketLocation) (*GetBucketLocationResponse, error)
+}
+
+// NewAmazonS3Handler returns an http.Handler serving impl as the AmazonS3
+// port. Errors impl returns are sent as faults, a *soap.Fault as it is. The
+// WSDL document wsdl, when not nil, is served at ?wsdl.
+func NewAmazonS3Handler(impl AmazonS3Server, wsdl []byte) *soap.Server {
+	return soap.NewServer(wsdl,
+		&soap.Operation{
+			Name:       "CreateBucket",
+			SOAPAction: "",
+			Element:    xml.Name{Space: "http://s3.amazonaws.com/doc/2006-03-01/", Local: "CreateBucket"},
+			NewRequest: func() interface{} { return new(CreateBucket) },
+			Handle: func(ctx context.Context, request interface{}) (interface{}, error) {
+				return impl.CreateBucket(ctx, request.(*CreateBucket))
+			},
+		},
+		&soap.Operation{
+			Name:       "DeleteBucket",
+			SOAPAction: "",
+			Element:    xml.Name{Space: "http://s3.amazonaws.com/doc/2006-03-01/", Local: "DeleteBucket"},
+			NewRequest: func() interface{} { return new(DeleteBucket) },
+			Handle: func(ctx context.Context, request interface{}) (interface{}, error) {
+				return impl.DeleteBucket(ctx, request.(*DeleteBucket))
+			},
+		},
+		&soap.Operation{
+			Name:       "GetObjectAccessControlPolicy",
+			SOAPAction: "",
+			Element:    xml.Name{Space: "http://s3.amazonaws.com/doc/2006-03-01/", Local: "GetObjectAccessControlPolicy"},
+			NewRequest: func() interface{} { return new(GetObjectAccessControlPolicy) },
+			Handle: func(ctx context.Context, request interface{}) (interface{}, error) {
+				return impl.GetObjectAccessControlPolicy(ctx, request.(*GetObjectAccessControlPolicy))
+			},
+		},
+		&soap.Operation{
+			Name:       "GetBucketAccessControlPolicy",
+			SOAPAction: "",
+			Element:    xml.Name{Space: "http://s3.amazonaws.com/doc/2006-03-01/", Local: "GetBucketAccessControlPolicy"},
+			NewRequest: func() interface{} { return new(GetBucketAccessControlPolicy) },
+			Handle: func(ctx context.Context, request interface{}) (interface{}, error) {
+				return impl.GetBucketAccessControlPolicy(ctx, request.(*GetBucketAccessControlPolicy))
+			},
+		},
+		&soap.Operation{
+			Name:       "SetObjectAccessControlPolicy",
+			SOAPAction: "",
+			Element:    xml.Name{Space: "http://s3.amazonaws.com/doc/2006-03-01/", Local: "SetObjectAccessControlPolicy"},
+			NewRequest: func() interface{} { return new(SetObjectAccessControlPolicy) },
+			Handle: func(ctx context.Context, request interface{}) (interface{}, error) {
+				return impl.SetObjectAccessControlPolicy(ctx, request.(*SetObjectAccessControlPolicy))
+			},
+		},
+		&soap.Operation{
+			Name:       "SetBucketAccessControlPolicy",
+			SOAPAction: "",
+			Element:    xml.Name{Space: "http://s3.amazonaws.com/doc/2006-03-01/", Local: "SetBucketAccessControlPolicy"},
+			NewRequest: func() interface{} { return new(SetBucketAccessControlPolicy) },
+			Handle: func(ctx context.Context, request interface{}) (interface{}, error) {
+				return impl.SetBucketAccessControlPolicy(ctx, request.(*SetBucketAccessControlPolicy))
+			},
+		},
+		&soap.Operation{
+			Name:       "GetObject",
+			SOAPAction: "",
+			Element:    xml.Name{Space: "http://s3.amazonaws.com/doc/2006-03-01/", Local: "GetObject"},
+			NewRequest: func() interface{} { return new(GetObject) },
+			Handle: func(ctx context.Context, request interface{}) (interface{}, error) {
+				return impl.GetObject(ctx, request.(*GetObject))
+			},
+		},
+		&soap.Operation{
+			Name:       "GetObjectExtended",
+			SOAPAction: "",
+			Element:    xml.Name{Space: "http://s3.amazonaws.com/doc/2006-03-01/", Local: "GetObjectExtended"},
+			NewRequest: func() interface{} { return new(GetObjectExtended) },
+			Handle: func(ctx context.Context, request interface{}) (interface{}, error) {
+				return impl.GetObjectExtended(ctx, request.(*GetObjectExtended))
+			},
+		},
+		&soap.Operation{
+			Name:       "PutObject",
+			SOAPAction: "",
+			Element:    xml.Name{Space: "http://s3.amazonaws.com/doc/2006-03-01/", Local: "PutObject"},
+			NewRequest: func() interface{} { return new(PutObject) },
+			Handle: func(ctx context.Context, request interface{}) (interface{}, error) {
+				return impl.PutObject(ctx, request.(*PutObject))
+			},
+		},
+		&soap.Operation{
+			Name:       "PutObjectInline",
+			SOAPAction: "",
+			Element:    xml.Name{Space: "http://s3.amazonaws.com/doc/2006-03-01/", Local: "PutObjectInline"},
+			NewRequest: func() interface{} { return new(PutObjectInline) },
+			Handle: func(ctx context.Context, request interface{}) (interface{}, error) {
+				return impl.PutObjectInline(ctx, request.(*PutObjectInline))
+			},
+		},
+		&soap.Operation{
+			Name:       "DeleteObject",
+			SOAPAction: "",
+			Element:    xml.Name{Space: "http://s3.amazonaws.com/doc/2006-03-01/", Local: "DeleteObject"},
+			NewRequest: func() interface{} { return new(DeleteObject) },
+			Handle: func(ctx context.Context, request interface{}) (interface{}, error) {
+				return impl.DeleteObject(ctx, request.(*DeleteObject))
+			},
+		},
+		&soap.Operation{
+			Name:       "ListBucket",
+			SOAPAction: "",
+			Element:    xml.Name{Space: "http://s3.amazonaws.com/doc/2006-03-01/", Local: "ListBucket"},
+			NewRequest: func() interface{} { return new(ListBucket) },
+			Handle: func(ctx context.Context, request interface{}) (interface{}, error) {
+				return impl.ListBucket(ctx, request.(*ListBucket))
+			},
+		},
+		&soap.Operation{
+			Name:       "ListAllMyBuckets",
+			SOAPAction: "",
+			Element:    xml.Name{Space: "http://s3.amazonaws.com/doc/2006-03-01/", Local: "ListAllMyBuckets"},
+			NewRequest: func() interface{} { return new(ListAllMyBuckets) },
+			Handle: func(ctx context.Context, request interface{}) (interface{}, error) {
+				return impl.ListAllMyBuckets(ctx, request.(*ListAllMyBuckets))
+			},
+		},
+		&soap.Operation{
+			Name:       "GetBucketLoggingStatus",
+			SOAPAction: "",
+			Element:    xml.Name{Space: "http://s3.amazonaws.com/doc/2006-03-01/", Local: "GetBucketLoggingStatus"},
+			NewRequest: func() interface{} { return new(GetBucketLoggingStatus) },
+			Handle: func(ctx context.Context, request interface{}) (interface{}, error) {
+				return impl.GetBucketLoggingStatus(ctx, request.(*GetBucketLoggingStatus))
+			},
+		},
+		&soap.Operation{
+			Name:       "SetBucketLoggingStatus",
+			SOAPAction: "",
+			Element:    xml.Name{Space: "http://s3.amazonaws.com/doc/2006-03-01/", Local: "SetBucketLoggingStatus"},
+			NewRequest: func() interface{} { return new(SetBucketLoggingStatus) },
+			Handle: func(ctx context.Context, request interface{}) (interface{}, error) {
+				return impl.SetBucketLoggingStatus(ctx, request.(*SetBucketLoggingStatus))
+			},
+		},
+		&soap.Operation{
+			Name:       "CopyObject",
+			SOAPAction: "",
+			Element:    xml.Name{Space: "http://s3.amazonaws.com/doc/2006-03-01/", Local: "CopyObject"},
+			NewRequest: func() interface{} { return new(CopyObject) },
+			Handle: func(ctx context.Context, request interface{}) (interface{}, error) {
+				return impl.CopyObject(ctx, request.(*CopyObject))
+			},
+		},
+		&soap.Operation{
+			Name:       "GetBucketNotification",
+			SOAPAction: "",
+			Element:    xml.Name{Space: "http://s3.amazonaws.com/doc/2006-03-01/", Local: "GetBucketNotification"},
+			NewRequest: func() interface{} { return new(GetBucketNotification) },
+			Handle: func(ctx context.Context, request interface{}) (interface{}, error) {
+				return impl.GetBucketNotification(ctx, request.(*GetBucketNotification))
+			},
+		},
+		&soap.Operation{
+			Name:       "SetBucketNotification",
+			SOAPAction: "",
+			Element:    xml.Name{Space: "http://s3.amazonaws.com/doc/2006-03-01/", Local: "SetBucketNotification"},
+			NewRequest: func() interface{} { return new(SetBucketNotification) },
+			Handle: func(ctx context.Context, request interface{}) (interface{}, error) {
+				return impl.SetBucketNotification(ctx, request.(*SetBucketNotification))
+			},
+		},
+		&soap.Operation{
+			Name:       "GetBucketLocation",
+			SOAPAction: "",
+			Element:    xml.Name{Space: "http://s3.amazonaws.com/doc/2006-03-01/", Local: "GetBucketLocation"},
+			NewRequest: func() interface{} { return new(GetBucketLocation) },
+			Handle: func(ctx context.Context, request interface{}) (interface{}, error) {
+				return impl.GetBucketLocation(ctx, request.(*GetBucketLocation))
+			},
+		},
+	)
 }
@@ -1,24 +1,142 @@
 package aws
 
 import (
+	"encoding/xml"
 	"testing"
 	"time"
 
+	"github.com/luhonghai/wsdl-example/pkg/cassette"
+	"github.com/luhonghai/wsdl-example/pkg/soap"
+	"github.com/luhonghai/wsdl-example/pkg/xsd"
 	"github.com/magiconair/properties/assert"
 )
 
 func TestListAllMyBuckets(t *testing.T) {
-	auth := &BasicAuth{}
-	s3 := NewAmazonS3("", false, auth)
+	// The cassette is a hand-written fixture, not traffic recorded from S3:
+	// the request is unsigned, which S3 would refuse. It is only replayed.
+	recorder, err := cassette.New("testdata/list_all_my_buckets.json", cassette.Replay, "Timestamp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	auth := &soap.BasicAuth{}
+	client := soap.NewClient("https://s3.amazonaws.com/soap", false, auth)
+	client.SetTransport(recorder)
+	s3 := NewAmazonS3WithClient(client)
 
 	request := &ListAllMyBuckets{
-		Timestamp: time.Now(),
+		Timestamp: &xsd.DateTime{Time: time.Now()},
 	}
 	resp, err := s3.ListAllMyBuckets(request)
 
 	if err != nil {
 		t.Error("Could not request", err)
 	} else {
-		assert.Equal(t, resp.ListAllMyBucketsResponse.Owner, "test")
+		assert.Equal(t, resp.ListAllMyBucketsResponse.Owner.ID, "test")
+	}
+}
+
+func TestBucketNotification(t *testing.T) {
+	fake := newFakeS3(t)
+	s3 := NewAmazonS3(fake.URL, false, nil)
+
+	topic := &TopicConfiguration{
+		Topic: "arn:aws:sns:us-east-1:123456789012:uploads",
+		Event: []string{"s3:ReducedRedundancyLostObject"},
+	}
+	_, err := s3.SetBucketNotification(&SetBucketNotification{
+		Bucket: "events",
+		NotificationConfiguration: &NotificationConfiguration{
+			TopicConfiguration: []*TopicConfiguration{topic},
+		},
+	})
+	if err != nil {
+		t.Fatal("Could not set notification", err)
+	}
+
+	recorded := fake.topics("events")
+	assert.Equal(t, len(recorded), 1)
+	assert.Equal(t, recorded[0].Topic, topic.Topic)
+	assert.Equal(t, recorded[0].Event, topic.Event)
+
+	resp, err := s3.GetBucketNotification(&GetBucketNotification{Bucket: "events"})
+	if err != nil {
+		t.Fatal("Could not get notification", err)
+	}
+	assert.Equal(t, len(resp.GetBucketNotificationResponse.TopicConfiguration), 1)
+	assert.Equal(t, resp.GetBucketNotificationResponse.TopicConfiguration[0].Topic, topic.Topic)
+
+	_, err = s3.SetBucketNotification(&SetBucketNotification{Bucket: "events"})
+	if err != nil {
+		t.Fatal("Could not clear notification", err)
+	}
+	assert.Equal(t, len(fake.topics("events")), 0)
+}
+
+func TestCreateBucketWithLocation(t *testing.T) {
+	fake := newFakeS3(t)
+	s3 := NewAmazonS3(fake.URL, false, nil)
+
+	resp, err := s3.CreateBucket(&CreateBucket{
+		Bucket: "eu-bucket",
+		CreateBucketConfiguration: &CreateBucketConfiguration{
+			LocationConstraint: &LocationConstraint{Value: "EU"},
+		},
+	})
+	if err != nil {
+		t.Fatal("Could not create bucket", err)
+	}
+	assert.Equal(t, resp.CreateBucketReturn.BucketName, "eu-bucket")
+
+	_, err = s3.CreateBucket(&CreateBucket{Bucket: "us-bucket"})
+	if err != nil {
+		t.Fatal("Could not create bucket", err)
+	}
+
+	location, err := s3.GetBucketLocation(&GetBucketLocation{Bucket: "eu-bucket"})
+	if err != nil {
+		t.Fatal("Could not get location", err)
+	}
+	assert.Equal(t, location.GetBucketLocationResponse.Value, "EU")
+
+	location, err = s3.GetBucketLocation(&GetBucketLocation{Bucket: "us-bucket"})
+	if err != nil {
+		t.Fatal("Could not get location", err)
+	}
+	assert.Equal(t, location.GetBucketLocationResponse.Value, "")
+
+	_, err = s3.GetBucketLocation(&GetBucketLocation{Bucket: "missing"})
+	if fault, ok := err.(*soap.Fault); !ok || fault.Code != "Client.NoSuchBucket" {
+		t.Error("Expected NoSuchBucket fault, got", err)
+	}
+}
+
+func TestEnumValidation(t *testing.T) {
+	assert.Equal(t, PermissionREAD.Values(), []Permission{
+		PermissionREAD, PermissionWRITE, PermissionREADACP, PermissionWRITEACP, PermissionFULLCONTROL,
+	})
+	assert.Equal(t, StorageClass("GLACIER").Validate(), nil)
+
+	_, err := xml.Marshal(&Grant{Grantee: NewGroupGrantee(AllUsersGroup), Permission: "READ_ALL"})
+	enumErr, ok := err.(*xsd.EnumError)
+	if !ok {
+		t.Fatalf("expected an *xsd.EnumError, got %v", err)
+	}
+	assert.Equal(t, enumErr.Type, "Permission")
+	assert.Equal(t, enumErr.Error(), `xsd: "READ_ALL" is not a valid Permission; expected one of READ, WRITE, READ_ACP, WRITE_ACP, FULL_CONTROL`)
+
+	entry := []byte("<Contents><Key>a</Key><StorageClass>DEEP_ARCHIVE</StorageClass></Contents>")
+	if err := xml.Unmarshal(entry, new(ListEntry)); err == nil {
+		t.Error("expected an error for an unknown storage class")
+	}
+
+	xsd.SetLenientEnums(true)
+	defer xsd.SetLenientEnums(false)
+	var lenient ListEntry
+	if err := xml.Unmarshal(entry, &lenient); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, lenient.StorageClass, StorageClass("DEEP_ARCHIVE"))
+	if lenient.StorageClass.Validate() == nil {
+		t.Error("expected Validate to report the unknown storage class")
 	}
 }
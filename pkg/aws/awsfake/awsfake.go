@@ -0,0 +1,307 @@
+// Code generated by wsdl-example generate; DO NOT EDIT.
+
+// Package awsfake holds in-memory implementations of the ports of
+// package aws, for tests.
+package awsfake
+
+import (
+	"sync"
+
+	"github.com/luhonghai/wsdl-example/pkg/aws"
+)
+
+// AmazonS3 is an in-memory aws.AmazonS3Client.
+//
+// It records the requests of every call and answers with the function set
+// for the operation, or with an empty response when there is none.
+type AmazonS3 struct {
+	mu sync.Mutex
+
+	CreateBucketFunc                     func(request *aws.CreateBucket) (*aws.CreateBucketResponse, error)
+	CreateBucketRequests                 []*aws.CreateBucket
+	DeleteBucketFunc                     func(request *aws.DeleteBucket) (*aws.DeleteBucketResponse, error)
+	DeleteBucketRequests                 []*aws.DeleteBucket
+	GetObjectAccessControlPolicyFunc     func(request *aws.GetObjectAccessControlPolicy) (*aws.GetObjectAccessControlPolicyResponse, error)
+	GetObjectAccessControlPolicyRequests []*aws.GetObjectAccessControlPolicy
+	GetBucketAccessControlPolicyFunc     func(request *aws.GetBucketAccessControlPolicy) (*aws.GetBucketAccessControlPolicyResponse, error)
+	GetBucketAccessControlPolicyRequests []*aws.GetBucketAccessControlPolicy
+	SetObjectAccessControlPolicyFunc     func(request *aws.SetObjectAccessControlPolicy) (*aws.SetObjectAccessControlPolicyResponse, error)
+	SetObjectAccessControlPolicyRequests []*aws.SetObjectAccessControlPolicy
+	SetBucketAccessControlPolicyFunc     func(request *aws.SetBucketAccessControlPolicy) (*aws.SetBucketAccessControlPolicyResponse, error)
+	SetBucketAccessControlPolicyRequests []*aws.SetBucketAccessControlPolicy
+	GetObjectFunc                        func(request *aws.GetObject) (*aws.GetObjectResponse, error)
+	GetObjectRequests                    []*aws.GetObject
+	GetObjectExtendedFunc                func(request *aws.GetObjectExtended) (*aws.GetObjectExtendedResponse, error)
+	GetObjectExtendedRequests            []*aws.GetObjectExtended
+	PutObjectFunc                        func(request *aws.PutObject) (*aws.PutObjectResponse, error)
+	PutObjectRequests                    []*aws.PutObject
+	PutObjectInlineFunc                  func(request *aws.PutObjectInline) (*aws.PutObjectInlineResponse, error)
+	PutObjectInlineRequests              []*aws.PutObjectInline
+	DeleteObjectFunc                     func(request *aws.DeleteObject) (*aws.DeleteObjectResponse, error)
+	DeleteObjectRequests                 []*aws.DeleteObject
+	ListBucketFunc                       func(request *aws.ListBucket) (*aws.ListBucketResponse, error)
+	ListBucketRequests                   []*aws.ListBucket
+	ListAllMyBucketsFunc                 func(request *aws.ListAllMyBuckets) (*aws.ListAllMyBucketsResponse, error)
+	ListAllMyBucketsRequests             []*aws.ListAllMyBuckets
+	GetBucketLoggingStatusFunc           func(request *aws.GetBucketLoggingStatus) (*aws.GetBucketLoggingStatusResponse, error)
+	GetBucketLoggingStatusRequests       []*aws.GetBucketLoggingStatus
+	SetBucketLoggingStatusFunc           func(request *aws.SetBucketLoggingStatus) (*aws.SetBucketLoggingStatusResponse, error)
+	SetBucketLoggingStatusRequests       []*aws.SetBucketLoggingStatus
+	CopyObjectFunc                       func(request *aws.CopyObject) (*aws.CopyObjectResponse, error)
+	CopyObjectRequests                   []*aws.CopyObject
+	GetBucketNotificationFunc            func(request *aws.GetBucketNotification) (*aws.GetBucketNotificationResponse, error)
+	GetBucketNotificationRequests        []*aws.GetBucketNotification
+	SetBucketNotificationFunc            func(request *aws.SetBucketNotification) (*aws.SetBucketNotificationResponse, error)
+	SetBucketNotificationRequests        []*aws.SetBucketNotification
+	GetBucketLocationFunc                func(request *aws.GetBucketLocation) (*aws.GetBucketLocationResponse, error)
+	GetBucketLocationRequests            []*aws.GetBucketLocation
+}
+
+var _ aws.AmazonS3Client = (*AmazonS3)(nil)
+
+func (fake *AmazonS3) CreateBucket(request *aws.CreateBucket) (*aws.CreateBucketResponse, error) {
+	fake.mu.Lock()
+	fake.CreateBucketRequests = append(fake.CreateBucketRequests, request)
+	fn := fake.CreateBucketFunc
+	fake.mu.Unlock()
+
+	if fn != nil {
+		return fn(request)
+	}
+
+	return new(aws.CreateBucketResponse), nil
+}
+
+func (fake *AmazonS3) DeleteBucket(request *aws.DeleteBucket) (*aws.DeleteBucketResponse, error) {
+	fake.mu.Lock()
+	fake.DeleteBucketRequests = append(fake.DeleteBucketRequests, request)
+	fn := fake.DeleteBucketFunc
+	fake.mu.Unlock()
+
+	if fn != nil {
+		return fn(request)
+	}
+
+	return new(aws.DeleteBucketResponse), nil
+}
+
+func (fake *AmazonS3) GetObjectAccessControlPolicy(request *aws.GetObjectAccessControlPolicy) (*aws.GetObjectAccessControlPolicyResponse, error) {
+	fake.mu.Lock()
+	fake.GetObjectAccessControlPolicyRequests = append(fake.GetObjectAccessControlPolicyRequests, request)
+	fn := fake.GetObjectAccessControlPolicyFunc
+	fake.mu.Unlock()
+
+	if fn != nil {
+		return fn(request)
+	}
+
+	return new(aws.GetObjectAccessControlPolicyResponse), nil
+}
+
+func (fake *AmazonS3) GetBucketAccessControlPolicy(request *aws.GetBucketAccessControlPolicy) (*aws.GetBucketAccessControlPolicyResponse, error) {
+	fake.mu.Lock()
+	fake.GetBucketAccessControlPolicyRequests = append(fake.GetBucketAccessControlPolicyRequests, request)
+	fn := fake.GetBucketAccessControlPolicyFunc
+	fake.mu.Unlock()
+
+	if fn != nil {
+		return fn(request)
+	}
+
+	return new(aws.GetBucketAccessControlPolicyResponse), nil
+}
+
+func (fake *AmazonS3) SetObjectAccessControlPolicy(request *aws.SetObjectAccessControlPolicy) (*aws.SetObjectAccessControlPolicyResponse, error) {
+	fake.mu.Lock()
+	fake.SetObjectAccessControlPolicyRequests = append(fake.SetObjectAccessControlPolicyRequests, request)
+	fn := fake.SetObjectAccessControlPolicyFunc
+	fake.mu.Unlock()
+
+	if fn != nil {
+		return fn(request)
+	}
+
+	return new(aws.SetObjectAccessControlPolicyResponse), nil
+}
+
+func (fake *AmazonS3) SetBucketAccessControlPolicy(request *aws.SetBucketAccessControlPolicy) (*aws.SetBucketAccessControlPolicyResponse, error) {
+	fake.mu.Lock()
+	fake.SetBucketAccessControlPolicyRequests = append(fake.SetBucketAccessControlPolicyRequests, request)
+	fn := fake.SetBucketAccessControlPolicyFunc
+	fake.mu.Unlock()
+
+	if fn != nil {
+		return fn(request)
+	}
+
+	return new(aws.SetBucketAccessControlPolicyResponse), nil
+}
+
+func (fake *AmazonS3) GetObject(request *aws.GetObject) (*aws.GetObjectResponse, error) {
+	fake.mu.Lock()
+	fake.GetObjectRequests = append(fake.GetObjectRequests, request)
+	fn := fake.GetObjectFunc
+	fake.mu.Unlock()
+
+	if fn != nil {
+		return fn(request)
+	}
+
+	return new(aws.GetObjectResponse), nil
+}
+
+func (fake *AmazonS3) GetObjectExtended(request *aws.GetObjectExtended) (*aws.GetObjectExtendedResponse, error) {
+	fake.mu.Lock()
+	fake.GetObjectExtendedRequests = append(fake.GetObjectExtendedRequests, request)
+	fn := fake.GetObjectExtendedFunc
+	fake.mu.Unlock()
+
+	if fn != nil {
+		return fn(request)
+	}
+
+	return new(aws.GetObjectExtendedResponse), nil
+}
+
+func (fake *AmazonS3) PutObject(request *aws.PutObject) (*aws.PutObjectResponse, error) {
+	fake.mu.Lock()
+	fake.PutObjectRequests = append(fake.PutObjectRequests, request)
+	fn := fake.PutObjectFunc
+	fake.mu.Unlock()
+
+	if fn != nil {
+		return fn(request)
+	}
+
+	return new(aws.PutObjectResponse), nil
+}
+
+func (fake *AmazonS3) PutObjectInline(request *aws.PutObjectInline) (*aws.PutObjectInlineResponse, error) {
+	fake.mu.Lock()
+	fake.PutObjectInlineRequests = append(fake.PutObjectInlineRequests, request)
+	fn := fake.PutObjectInlineFunc
+	fake.mu.Unlock()
+
+	if fn != nil {
+		return fn(request)
+	}
+
+	return new(aws.PutObjectInlineResponse), nil
+}
+
+func (fake *AmazonS3) DeleteObject(request *aws.DeleteObject) (*aws.DeleteObjectResponse, error) {
+	fake.mu.Lock()
+	fake.DeleteObjectRequests = append(fake.DeleteObjectRequests, request)
+	fn := fake.DeleteObjectFunc
+	fake.mu.Unlock()
+
+	if fn != nil {
+		return fn(request)
+	}
+
+	return new(aws.DeleteObjectResponse), nil
+}
+
+func (fake *AmazonS3) ListBucket(request *aws.ListBucket) (*aws.ListBucketResponse, error) {
+	fake.mu.Lock()
+	fake.ListBucketRequests = append(fake.ListBucketRequests, request)
+	fn := fake.ListBucketFunc
+	fake.mu.Unlock()
+
+	if fn != nil {
+		return fn(request)
+	}
+
+	return new(aws.ListBucketResponse), nil
+}
+
+func (fake *AmazonS3) ListAllMyBuckets(request *aws.ListAllMyBuckets) (*aws.ListAllMyBucketsResponse, error) {
+	fake.mu.Lock()
+	fake.ListAllMyBucketsRequests = append(fake.ListAllMyBucketsRequests, request)
+	fn := fake.ListAllMyBucketsFunc
+	fake.mu.Unlock()
+
+	if fn != nil {
+		return fn(request)
+	}
+
+	return new(aws.ListAllMyBucketsResponse), nil
+}
+
+func (fake *AmazonS3) GetBucketLoggingStatus(request *aws.GetBucketLoggingStatus) (*aws.GetBucketLoggingStatusResponse, error) {
+	fake.mu.Lock()
+	fake.GetBucketLoggingStatusRequests = append(fake.GetBucketLoggingStatusRequests, request)
+	fn := fake.GetBucketLoggingStatusFunc
+	fake.mu.Unlock()
+
+	if fn != nil {
+		return fn(request)
+	}
+
+	return new(aws.GetBucketLoggingStatusResponse), nil
+}
+
+func (fake *AmazonS3) SetBucketLoggingStatus(request *aws.SetBucketLoggingStatus) (*aws.SetBucketLoggingStatusResponse, error) {
+	fake.mu.Lock()
+	fake.SetBucketLoggingStatusRequests = append(fake.SetBucketLoggingStatusRequests, request)
+	fn := fake.SetBucketLoggingStatusFunc
+	fake.mu.Unlock()
+
+	if fn != nil {
+		return fn(request)
+	}
+
+	return new(aws.SetBucketLoggingStatusResponse), nil
+}
+
+func (fake *AmazonS3) CopyObject(request *aws.CopyObject) (*aws.CopyObjectResponse, error) {
+	fake.mu.Lock()
+	fake.CopyObjectRequests = append(fake.CopyObjectRequests, request)
+	fn := fake.CopyObjectFunc
+	fake.mu.Unlock()
+
+	if fn != nil {
+		return fn(request)
+	}
+
+	return new(aws.CopyObjectResponse), nil
+}
+
+func (fake *AmazonS3) GetBucketNotification(request *aws.GetBucketNotification) (*aws.GetBucketNotificationResponse, error) {
+	fake.mu.Lock()
+	fake.GetBucketNotificationRequests = append(fake.GetBucketNotificationRequests, request)
+	fn := fake.GetBucketNotificationFunc
+	fake.mu.Unlock()
+
+	if fn != nil {
+		return fn(request)
+	}
+
+	return new(aws.GetBucketNotificationResponse), nil
+}
+
+func (fake *AmazonS3) SetBucketNotification(request *aws.SetBucketNotification) (*aws.SetBucketNotificationResponse, error) {
+	fake.mu.Lock()
+	fake.SetBucketNotificationRequests = append(fake.SetBucketNotificationRequests, request)
+	fn := fake.SetBucketNotificationFunc
+	fake.mu.Unlock()
+
+	if fn != nil {
+		return fn(request)
+	}
+
+	return new(aws.SetBucketNotificationResponse), nil
+}
+
+func (fake *AmazonS3) GetBucketLocation(request *aws.GetBucketLocation) (*aws.GetBucketLocationResponse, error) {
+	fake.mu.Lock()
+	fake.GetBucketLocationRequests = append(fake.GetBucketLocationRequests, request)
+	fn := fake.GetBucketLocationFunc
+	fake.mu.Unlock()
+
+	if fn != nil {
+		return fn(request)
+	}
+
+	return new(aws.GetBucketLocationResponse), nil
+}
@@ -0,0 +1,174 @@
+package aws
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DefaultDeleteConcurrency is the number of deletes DeleteObjects runs at
+// once when the options leave it unset.
+const DefaultDeleteConcurrency = 8
+
+// BulkDeleteOptions tune DeleteObjects and DeletePrefix.
+type BulkDeleteOptions struct {
+	// Credentials sign every request; nil sends them unsigned.
+	Credentials *Credentials
+	// Concurrency is the number of deletes in flight at once.
+	Concurrency int
+	// RequestsPerSecond caps the rate deletes are issued at; zero means no
+	// limit. Rates beyond one per nanosecond are as good as none.
+	RequestsPerSecond float64
+}
+
+// DeleteResult is the outcome of deleting a single key.
+type DeleteResult struct {
+	Key string
+	Err error
+}
+
+// DeleteReport collects the per-key results of a bulk delete, in the order
+// the keys were given.
+type DeleteReport struct {
+	Results []*DeleteResult
+}
+
+// Failed returns the results of the keys that could not be deleted.
+func (r *DeleteReport) Failed() []*DeleteResult {
+	var failed []*DeleteResult
+	for _, result := range r.Results {
+		if result.Err != nil {
+			failed = append(failed, result)
+		}
+	}
+
+	return failed
+}
+
+// Err summarises the failures of the report, or returns nil when every key
+// was deleted.
+func (r *DeleteReport) Err() error {
+	failed := r.Failed()
+	if len(failed) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("failed to delete %d of %d keys, first %s: %v", len(failed), len(r.Results), failed[0].Key, failed[0].Err)
+}
+
+// DeleteObjects deletes keys from bucket concurrently. A failure to delete
+// one key does not stop the others; check the report for the outcome of each.
+func (service *AmazonS3) DeleteObjects(bucket string, keys []string, options *BulkDeleteOptions) *DeleteReport {
+	if options == nil {
+		options = &BulkDeleteOptions{}
+	}
+	concurrency := options.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultDeleteConcurrency
+	}
+
+	var limit <-chan time.Time
+	if options.RequestsPerSecond > 0 {
+		interval := time.Duration(float64(time.Second) / options.RequestsPerSecond)
+		if interval < 1 {
+			interval = 1
+		}
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		limit = ticker.C
+	}
+
+	report := &DeleteReport{Results: make([]*DeleteResult, len(keys))}
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range indexes {
+				if limit != nil {
+					<-limit
+				}
+				report.Results[index] = &DeleteResult{
+					Key: keys[index],
+					Err: service.deleteObject(options.Credentials, bucket, keys[index]),
+				}
+			}
+		}()
+	}
+	for i := range keys {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	return report
+}
+
+// DeletePrefix deletes every key in bucket starting with prefix. An empty
+// prefix empties the bucket.
+func (service *AmazonS3) DeletePrefix(bucket, prefix string, options *BulkDeleteOptions) (*DeleteReport, error) {
+	var credentials *Credentials
+	if options != nil {
+		credentials = options.Credentials
+	}
+	keys, err := service.ListKeys(credentials, bucket, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.DeleteObjects(bucket, keys, options), nil
+}
+
+// ListKeys returns every key in bucket starting with prefix, following the
+// listing across pages.
+func (service *AmazonS3) ListKeys(credentials *Credentials, bucket, prefix string) ([]string, error) {
+	var keys []string
+	var marker *string
+	for {
+		key, timestamp, signature := credentials.Sign("ListBucket")
+		request := &ListBucket{
+			Bucket:         bucket,
+			Marker:         marker,
+			AWSAccessKeyId: key,
+			Timestamp:      timestamp,
+			Signature:      signature,
+		}
+		if prefix != "" {
+			request.Prefix = &prefix
+		}
+		resp, err := service.ListBucket(request)
+		if err != nil {
+			return nil, err
+		}
+
+		result := resp.ListBucketResponse
+		if result == nil {
+			return keys, nil
+		}
+		for _, entry := range result.Contents {
+			keys = append(keys, entry.Key)
+		}
+		if !result.IsTruncated || len(result.Contents) == 0 {
+			return keys, nil
+		}
+
+		marker = result.NextMarker
+		if marker == nil {
+			marker = &result.Contents[len(result.Contents)-1].Key
+		}
+	}
+}
+
+func (service *AmazonS3) deleteObject(credentials *Credentials, bucket, objectKey string) error {
+	key, timestamp, signature := credentials.Sign("DeleteObject")
+	_, err := service.DeleteObject(&DeleteObject{
+		Bucket:         bucket,
+		Key:            objectKey,
+		AWSAccessKeyId: key,
+		Timestamp:      timestamp,
+		Signature:      signature,
+	})
+
+	return err
+}
@@ -0,0 +1,99 @@
+package aws
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/luhonghai/wsdl-example/pkg/soap"
+	"github.com/magiconair/properties/assert"
+)
+
+func TestDeleteObjects(t *testing.T) {
+	fake := newFakeS3(t)
+	s3 := NewAmazonS3(fake.URL, false, nil)
+
+	var keys []string
+	for i := 0; i < 10; i++ {
+		key := fmt.Sprintf("logs/%02d", i)
+		fake.put("archive", key, []byte("x"))
+		keys = append(keys, key)
+	}
+	fake.failDeletes["logs/04"] = true
+
+	report := s3.DeleteObjects("archive", keys, &BulkDeleteOptions{Concurrency: 4})
+	assert.Equal(t, len(report.Results), 10)
+	assert.Equal(t, report.Results[3].Key, "logs/03")
+	assert.Equal(t, report.Results[3].Err, nil)
+
+	failed := report.Failed()
+	assert.Equal(t, len(failed), 1)
+	assert.Equal(t, failed[0].Key, "logs/04")
+	if report.Err() == nil {
+		t.Error("Expected the report to carry an error")
+	}
+	assert.Equal(t, fake.keys("archive"), []string{"logs/04"})
+}
+
+func TestDeleteObjectsRateLimit(t *testing.T) {
+	fake := newFakeS3(t)
+	s3 := NewAmazonS3(fake.URL, false, nil)
+
+	keys := []string{"a", "b", "c", "d", "e"}
+	for _, key := range keys {
+		fake.put("slow", key, nil)
+	}
+
+	start := time.Now()
+	report := s3.DeleteObjects("slow", keys, &BulkDeleteOptions{Concurrency: 5, RequestsPerSecond: 50})
+	if err := report.Err(); err != nil {
+		t.Fatal(err)
+	}
+	// Five requests at 50 per second need at least four intervals of 20ms.
+	if elapsed := time.Since(start); elapsed < 80*time.Millisecond {
+		t.Error("Deletes were not rate limited, took", elapsed)
+	}
+}
+
+func TestDeleteObjectsHugeRate(t *testing.T) {
+	fake := newFakeS3(t)
+	s3 := NewAmazonS3(fake.URL, false, nil)
+	fake.put("fast", "a", nil)
+
+	report := s3.DeleteObjects("fast", []string{"a"}, &BulkDeleteOptions{RequestsPerSecond: 1e12})
+	if err := report.Err(); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, len(fake.keys("fast")), 0)
+}
+
+func TestDeletePrefix(t *testing.T) {
+	fake := newFakeS3(t)
+	s3 := NewAmazonS3(fake.URL, false, nil)
+
+	for i := 0; i < 7; i++ {
+		fake.put("media", fmt.Sprintf("tmp/%d", i), nil)
+	}
+	fake.put("media", "keep/me", nil)
+
+	report, err := s3.DeletePrefix("media", "tmp/", nil)
+	if err != nil {
+		t.Fatal("Could not purge prefix", err)
+	}
+	assert.Equal(t, len(report.Results), 7)
+	assert.Equal(t, report.Err(), nil)
+	assert.Equal(t, fake.keys("media"), []string{"keep/me"})
+
+	_, err = s3.DeleteBucket(&DeleteBucket{Bucket: "media"})
+	if fault, ok := err.(*soap.Fault); !ok || fault.Code != "Client.BucketNotEmpty" {
+		t.Error("Expected BucketNotEmpty fault, got", err)
+	}
+
+	if _, err := s3.DeletePrefix("media", "", nil); err != nil {
+		t.Fatal("Could not empty bucket", err)
+	}
+	if _, err := s3.DeleteBucket(&DeleteBucket{Bucket: "media"}); err != nil {
+		t.Fatal("Could not delete bucket", err)
+	}
+	assert.Equal(t, fake.hasBucket("media"), false)
+}
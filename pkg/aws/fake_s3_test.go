@@ -0,0 +1,362 @@
+package aws
+
+import (
+	"crypto/md5"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/luhonghai/wsdl-example/pkg/soap"
+	"github.com/luhonghai/wsdl-example/pkg/xsd"
+)
+
+// fakeS3 is an in-memory stand-in for the S3 SOAP endpoint. It understands
+// enough of the AmazonS3 port to drive the client end to end and keeps the
+// state tests want to assert on.
+type fakeS3 struct {
+	*httptest.Server
+
+	mu          sync.Mutex
+	buckets     map[string]*fakeBucket
+	aclUpdates  int
+	failDeletes map[string]bool
+	// transient holds, per key, how many more object requests fail with a
+	// retryable fault before succeeding.
+	transient     map[string]int
+	requests      int
+	notifications map[string][]*TopicConfiguration
+}
+
+type fakeBucket struct {
+	location string
+	acl      *AccessControlList
+	objects  map[string][]byte
+}
+
+// fakeMaxKeys is the page size of listings, kept small so tests page.
+const fakeMaxKeys = 3
+
+// fakeOwner owns every bucket created on a fakeS3.
+var fakeOwner = &CanonicalUser{ID: "fake-owner-id", DisplayName: xsd.String("fake")}
+
+func newFakeS3(t *testing.T) *fakeS3 {
+	f := &fakeS3{
+		buckets:       make(map[string]*fakeBucket),
+		failDeletes:   make(map[string]bool),
+		transient:     make(map[string]int),
+		notifications: make(map[string][]*TopicConfiguration),
+	}
+	f.Server = httptest.NewServer(f)
+	t.Cleanup(f.Close)
+
+	return f
+}
+
+// put stores an object directly, creating its bucket when needed.
+func (f *fakeS3) put(bucket, key string, data []byte) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	b, ok := f.buckets[bucket]
+	if !ok {
+		b = &fakeBucket{objects: make(map[string][]byte)}
+		b.acl, _ = NewCannedACL(CannedPrivate, fakeOwner)
+		f.buckets[bucket] = b
+	}
+	b.objects[key] = data
+}
+
+// keys returns the sorted keys stored in bucket.
+func (f *fakeS3) keys(bucket string) []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	b, ok := f.buckets[bucket]
+	if !ok {
+		return nil
+	}
+
+	return b.sortedKeys()
+}
+
+// hasBucket reports whether bucket exists.
+func (f *fakeS3) hasBucket(bucket string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	_, ok := f.buckets[bucket]
+	return ok
+}
+
+func (b *fakeBucket) sortedKeys() []string {
+	keys := make([]string, 0, len(b.objects))
+	for key := range b.objects {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// topics returns the topic configurations recorded for bucket.
+func (f *fakeS3) topics(bucket string) []*TopicConfiguration {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.notifications[bucket]
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	d := xml.NewDecoder(r.Body)
+	start, err := bodyElement(d)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	f.mu.Lock()
+	f.requests++
+	response, fault := f.dispatch(d, start)
+	f.mu.Unlock()
+
+	envelope := soap.Envelope{}
+	if fault != nil {
+		envelope.Body.Fault = fault
+		w.WriteHeader(http.StatusInternalServerError)
+	} else {
+		envelope.Body.Content = response
+	}
+	w.Header().Set("Content-Type", "text/xml; charset=\"utf-8\"")
+	xml.NewEncoder(w).Encode(envelope)
+}
+
+func (f *fakeS3) dispatch(d *xml.Decoder, start *xml.StartElement) (interface{}, *soap.Fault) {
+	switch start.Name.Local {
+	case "CreateBucket":
+		request := new(CreateBucket)
+		if err := d.DecodeElement(request, start); err != nil {
+			return nil, clientFault(err.Error())
+		}
+		if _, ok := f.buckets[request.Bucket]; ok {
+			return nil, &soap.Fault{Code: "Client.BucketAlreadyExists", String: "The requested bucket name is not available."}
+		}
+		bucket := &fakeBucket{acl: request.AccessControlList, objects: make(map[string][]byte)}
+		if bucket.acl == nil {
+			bucket.acl, _ = NewCannedACL(CannedPrivate, fakeOwner)
+		}
+		if config := request.CreateBucketConfiguration; config != nil && config.LocationConstraint != nil {
+			bucket.location = config.LocationConstraint.Value
+		}
+		f.buckets[request.Bucket] = bucket
+		return &CreateBucketResponse{
+			CreateBucketReturn: &CreateBucketResult{BucketName: request.Bucket},
+		}, nil
+	case "GetBucketLocation":
+		request := new(GetBucketLocation)
+		if err := d.DecodeElement(request, start); err != nil {
+			return nil, clientFault(err.Error())
+		}
+		bucket, ok := f.buckets[request.Bucket]
+		if !ok {
+			return nil, noSuchBucket()
+		}
+		return &GetBucketLocationResponse{
+			GetBucketLocationResponse: &LocationConstraint{Value: bucket.location},
+		}, nil
+	case "DeleteBucket":
+		request := new(DeleteBucket)
+		if err := d.DecodeElement(request, start); err != nil {
+			return nil, clientFault(err.Error())
+		}
+		bucket, ok := f.buckets[request.Bucket]
+		if !ok {
+			return nil, noSuchBucket()
+		}
+		if len(bucket.objects) > 0 {
+			return nil, &soap.Fault{Code: "Client.BucketNotEmpty", String: "The bucket you tried to delete is not empty."}
+		}
+		delete(f.buckets, request.Bucket)
+		return &DeleteBucketResponse{DeleteBucketResponse: &Status{Code: 204}}, nil
+	case "ListBucket":
+		request := new(ListBucket)
+		if err := d.DecodeElement(request, start); err != nil {
+			return nil, clientFault(err.Error())
+		}
+		bucket, ok := f.buckets[request.Bucket]
+		if !ok {
+			return nil, noSuchBucket()
+		}
+		maxKeys := fakeMaxKeys
+		if request.MaxKeys != nil && *request.MaxKeys > 0 && *request.MaxKeys < fakeMaxKeys {
+			maxKeys = int(*request.MaxKeys)
+		}
+		result := &ListBucketResult{
+			Name:    request.Bucket,
+			Prefix:  xsd.StringValue(request.Prefix),
+			Marker:  xsd.StringValue(request.Marker),
+			MaxKeys: int32(maxKeys),
+		}
+		for _, key := range bucket.sortedKeys() {
+			if key <= result.Marker || !strings.HasPrefix(key, result.Prefix) {
+				continue
+			}
+			if len(result.Contents) == maxKeys {
+				result.IsTruncated = true
+				result.NextMarker = &result.Contents[maxKeys-1].Key
+				break
+			}
+			result.Contents = append(result.Contents, &ListEntry{
+				Key:          key,
+				Size:         int64(len(bucket.objects[key])),
+				StorageClass: StorageClassSTANDARD,
+			})
+		}
+		return &ListBucketResponse{ListBucketResponse: result}, nil
+	case "PutObjectInline":
+		request := new(PutObjectInline)
+		if err := d.DecodeElement(request, start); err != nil {
+			return nil, clientFault(err.Error())
+		}
+		bucket, ok := f.buckets[request.Bucket]
+		if !ok {
+			return nil, noSuchBucket()
+		}
+		if fault := f.transientFault(request.Key); fault != nil {
+			return nil, fault
+		}
+		bucket.objects[request.Key] = request.Data
+		return &PutObjectInlineResponse{
+			PutObjectInlineResponse: &PutObjectResult{ETag: fakeETag(request.Data)},
+		}, nil
+	case "GetObject":
+		request := new(GetObject)
+		if err := d.DecodeElement(request, start); err != nil {
+			return nil, clientFault(err.Error())
+		}
+		bucket, ok := f.buckets[request.Bucket]
+		if !ok {
+			return nil, noSuchBucket()
+		}
+		if fault := f.transientFault(request.Key); fault != nil {
+			return nil, fault
+		}
+		data, ok := bucket.objects[request.Key]
+		if !ok {
+			return nil, &soap.Fault{Code: "Client.NoSuchKey", String: "The specified key does not exist."}
+		}
+		result := &GetObjectResult{ETag: fakeETag(data)}
+		if request.GetData {
+			result.Data.Value = data
+		}
+		return &GetObjectResponse{GetObjectResponse: result}, nil
+	case "DeleteObject":
+		request := new(DeleteObject)
+		if err := d.DecodeElement(request, start); err != nil {
+			return nil, clientFault(err.Error())
+		}
+		bucket, ok := f.buckets[request.Bucket]
+		if !ok {
+			return nil, noSuchBucket()
+		}
+		if f.failDeletes[request.Key] {
+			return nil, &soap.Fault{Code: "Server.InternalError", String: "We encountered an internal error. Please try again."}
+		}
+		delete(bucket.objects, request.Key)
+		return &DeleteObjectResponse{DeleteObjectResponse: &Status{Code: 204}}, nil
+	case "GetBucketAccessControlPolicy":
+		request := new(GetBucketAccessControlPolicy)
+		if err := d.DecodeElement(request, start); err != nil {
+			return nil, clientFault(err.Error())
+		}
+		bucket, ok := f.buckets[request.Bucket]
+		if !ok {
+			return nil, noSuchBucket()
+		}
+		return &GetBucketAccessControlPolicyResponse{
+			GetBucketAccessControlPolicyResponse: &AccessControlPolicy{
+				Owner:             fakeOwner,
+				AccessControlList: bucket.acl,
+			},
+		}, nil
+	case "SetBucketAccessControlPolicy":
+		request := new(SetBucketAccessControlPolicy)
+		if err := d.DecodeElement(request, start); err != nil {
+			return nil, clientFault(err.Error())
+		}
+		bucket, ok := f.buckets[request.Bucket]
+		if !ok {
+			return nil, noSuchBucket()
+		}
+		bucket.acl = request.AccessControlList
+		f.aclUpdates++
+		return &SetBucketAccessControlPolicyResponse{}, nil
+	case "GetBucketNotification":
+		request := new(GetBucketNotification)
+		if err := d.DecodeElement(request, start); err != nil {
+			return nil, clientFault(err.Error())
+		}
+		return &GetBucketNotificationResponse{
+			GetBucketNotificationResponse: &NotificationConfiguration{
+				TopicConfiguration: f.notifications[request.Bucket],
+			},
+		}, nil
+	case "SetBucketNotification":
+		request := new(SetBucketNotification)
+		if err := d.DecodeElement(request, start); err != nil {
+			return nil, clientFault(err.Error())
+		}
+		if request.NotificationConfiguration == nil || len(request.NotificationConfiguration.TopicConfiguration) == 0 {
+			delete(f.notifications, request.Bucket)
+		} else {
+			f.notifications[request.Bucket] = request.NotificationConfiguration.TopicConfiguration
+		}
+		return &SetBucketNotificationResponse{}, nil
+	}
+
+	return nil, clientFault("unsupported operation " + start.Name.Local)
+}
+
+// bodyElement advances d to the first element inside the SOAP body.
+func bodyElement(d *xml.Decoder) (*xml.StartElement, error) {
+	inBody := false
+	for {
+		token, err := d.Token()
+		if err != nil {
+			return nil, err
+		}
+		se, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		if inBody {
+			return &se, nil
+		}
+		inBody = se.Name.Local == "Body"
+	}
+}
+
+func clientFault(message string) *soap.Fault {
+	return &soap.Fault{Code: "Client", String: message}
+}
+
+func (f *fakeS3) transientFault(key string) *soap.Fault {
+	if f.transient[key] == 0 {
+		return nil
+	}
+	f.transient[key]--
+
+	return &soap.Fault{Code: "Server.SlowDown", String: "Please reduce your request rate."}
+}
+
+func fakeETag(data []byte) string {
+	return fmt.Sprintf("\"%x\"", md5.Sum(data))
+}
+
+func noSuchBucket() *soap.Fault {
+	return &soap.Fault{Code: "Client.NoSuchBucket", String: "The specified bucket does not exist."}
+}
@@ -0,0 +1,44 @@
+package aws
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"time"
+
+	"github.com/luhonghai/wsdl-example/pkg/xsd"
+)
+
+// Credentials identify the AWS account requests are made on behalf of.
+type Credentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+}
+
+// Sign returns the access key, timestamp and signature to put on a request
+// for operation, ready for the optional fields of the request. A nil
+// receiver or missing secret key leaves the request unsigned, with no key
+// and no signature respectively.
+func (c *Credentials) Sign(operation string) (*string, *xsd.DateTime, *string) {
+	timestamp := &xsd.DateTime{Time: time.Now().UTC().Truncate(time.Millisecond)}
+	if c == nil {
+		return nil, timestamp, nil
+	}
+	if c.SecretAccessKey == "" {
+		return &c.AccessKeyID, timestamp, nil
+	}
+	signature := Sign(c.SecretAccessKey, operation, timestamp.Time)
+
+	return &c.AccessKeyID, timestamp, &signature
+}
+
+// Sign computes the signature the S3 SOAP API expects for an operation: the
+// base64 encoded HMAC-SHA1 of "AmazonS3" + operation + timestamp, keyed with
+// the secret access key. The timestamp must be sent exactly as signed, so
+// callers should pass the same value they put in the request.
+func Sign(secretKey, operation string, timestamp time.Time) string {
+	mac := hmac.New(sha1.New, []byte(secretKey))
+	mac.Write([]byte("AmazonS3" + operation + timestamp.Format(time.RFC3339Nano)))
+
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
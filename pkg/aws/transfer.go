@@ -0,0 +1,258 @@
+package aws
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/luhonghai/wsdl-example/pkg/soap"
+)
+
+// Defaults used by a TransferManager whose fields are left unset.
+const (
+	DefaultTransferConcurrency = 4
+	DefaultTransferRetries     = 3
+	DefaultTransferRetryDelay  = 200 * time.Millisecond
+)
+
+// Transfer moves one object between a local file and S3.
+type Transfer struct {
+	Bucket string
+	Key    string
+	// Path is the local file read by uploads and written by downloads.
+	Path string
+}
+
+func (t *Transfer) String() string {
+	return t.Bucket + "/" + t.Key
+}
+
+// ProgressEvent reports on a transfer managed by a TransferManager.
+type ProgressEvent struct {
+	Transfer *Transfer
+	// Attempt counts the tries so far, starting at 1.
+	Attempt int
+	// Bytes is the size of the object once the transfer is done.
+	Bytes int64
+	// Err is set when the attempt failed. Retrying tells whether another
+	// attempt follows.
+	Err      error
+	Retrying bool
+	// Done and Total count finished transfers, failed ones included.
+	Done  int
+	Total int
+}
+
+// TransferFailure is a transfer that failed after its last attempt.
+type TransferFailure struct {
+	Transfer *Transfer
+	Err      error
+}
+
+// TransferError aggregates the failures of a batch of transfers.
+type TransferError struct {
+	Failures []*TransferFailure
+	Total    int
+}
+
+func (e *TransferError) Error() string {
+	messages := make([]string, 0, len(e.Failures))
+	for _, failure := range e.Failures {
+		messages = append(messages, failure.Transfer.String()+": "+failure.Err.Error())
+	}
+
+	return fmt.Sprintf("%d of %d transfers failed: %s", len(e.Failures), e.Total, strings.Join(messages, "; "))
+}
+
+// TransferManager runs uploads and downloads on a pool of workers sharing
+// one AmazonS3 client, retrying failures that are likely to go away.
+type TransferManager struct {
+	service AmazonS3Client
+
+	Credentials *Credentials
+	// Concurrency is the number of transfers in flight at once.
+	Concurrency int
+	// MaxRetries is how often a transient failure is retried.
+	MaxRetries int
+	// RetryDelay is the wait before the first retry, doubled for each
+	// following one.
+	RetryDelay time.Duration
+	// OnProgress, when set, is called after every attempt. Calls are
+	// serialised, so the callback needs no locking of its own.
+	OnProgress func(ProgressEvent)
+}
+
+// NewTransferManager returns a manager with the default concurrency and retry
+// policy sending requests through service.
+func NewTransferManager(service AmazonS3Client, credentials *Credentials) *TransferManager {
+	return &TransferManager{
+		service:     service,
+		Credentials: credentials,
+		Concurrency: DefaultTransferConcurrency,
+		MaxRetries:  DefaultTransferRetries,
+		RetryDelay:  DefaultTransferRetryDelay,
+	}
+}
+
+// Upload puts the local file of every transfer to S3. It returns a
+// *TransferError listing the transfers that failed.
+func (m *TransferManager) Upload(transfers []*Transfer) error {
+	return m.run(transfers, m.upload)
+}
+
+// Download writes every object to the local file of its transfer, creating
+// directories as needed. It returns a *TransferError listing the transfers
+// that failed.
+func (m *TransferManager) Download(transfers []*Transfer) error {
+	return m.run(transfers, m.download)
+}
+
+func (m *TransferManager) upload(transfer *Transfer) (int64, error) {
+	data, err := ioutil.ReadFile(transfer.Path)
+	if err != nil {
+		return 0, err
+	}
+
+	key, timestamp, signature := m.Credentials.Sign("PutObjectInline")
+	_, err = m.service.PutObjectInline(&PutObjectInline{
+		Bucket:         transfer.Bucket,
+		Key:            transfer.Key,
+		Data:           data,
+		ContentLength:  int64(len(data)),
+		AWSAccessKeyId: key,
+		Timestamp:      timestamp,
+		Signature:      signature,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return int64(len(data)), nil
+}
+
+func (m *TransferManager) download(transfer *Transfer) (int64, error) {
+	key, timestamp, signature := m.Credentials.Sign("GetObject")
+	resp, err := m.service.GetObject(&GetObject{
+		Bucket:         transfer.Bucket,
+		Key:            transfer.Key,
+		GetData:        true,
+		InlineData:     true,
+		AWSAccessKeyId: key,
+		Timestamp:      timestamp,
+		Signature:      signature,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	var data []byte
+	if resp.GetObjectResponse != nil {
+		data = resp.GetObjectResponse.Data.Value
+	}
+	if err := os.MkdirAll(filepath.Dir(transfer.Path), 0755); err != nil {
+		return 0, err
+	}
+	if err := ioutil.WriteFile(transfer.Path, data, 0644); err != nil {
+		return 0, err
+	}
+
+	return int64(len(data)), nil
+}
+
+func (m *TransferManager) run(transfers []*Transfer, do func(*Transfer) (int64, error)) error {
+	concurrency := m.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultTransferConcurrency
+	}
+
+	var (
+		mu       sync.Mutex
+		done     int
+		failures []*TransferFailure
+	)
+	report := func(event ProgressEvent) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if !event.Retrying {
+			done++
+		}
+		if event.Err != nil && !event.Retrying {
+			failures = append(failures, &TransferFailure{Transfer: event.Transfer, Err: event.Err})
+		}
+		if m.OnProgress != nil {
+			event.Done = done
+			event.Total = len(transfers)
+			m.OnProgress(event)
+		}
+	}
+
+	queue := make(chan *Transfer)
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for transfer := range queue {
+				m.attempt(transfer, do, report)
+			}
+		}()
+	}
+	for _, transfer := range transfers {
+		queue <- transfer
+	}
+	close(queue)
+	wg.Wait()
+
+	if len(failures) > 0 {
+		return &TransferError{Failures: failures, Total: len(transfers)}
+	}
+
+	return nil
+}
+
+// attempt runs do for transfer until it succeeds, fails permanently or runs
+// out of retries.
+func (m *TransferManager) attempt(transfer *Transfer, do func(*Transfer) (int64, error), report func(ProgressEvent)) {
+	delay := m.RetryDelay
+	for attempt := 1; ; attempt++ {
+		bytes, err := do(transfer)
+		retrying := err != nil && attempt <= m.MaxRetries && IsTransient(err)
+		report(ProgressEvent{
+			Transfer: transfer,
+			Attempt:  attempt,
+			Bytes:    bytes,
+			Err:      err,
+			Retrying: retrying,
+		})
+		if !retrying {
+			return
+		}
+
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
+
+// IsTransient reports whether err is worth retrying: a network failure or a
+// fault S3 blames on its own side, such as Server.InternalError or
+// Server.SlowDown.
+func IsTransient(err error) bool {
+	switch err := err.(type) {
+	case *soap.Fault:
+		code := err.Code
+		if i := strings.LastIndex(code, ":"); i >= 0 {
+			code = code[i+1:]
+		}
+		return strings.HasPrefix(code, "Server")
+	case net.Error:
+		return true
+	}
+
+	return false
+}
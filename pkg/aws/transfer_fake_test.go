@@ -0,0 +1,47 @@
+package aws_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/luhonghai/wsdl-example/pkg/aws"
+	"github.com/luhonghai/wsdl-example/pkg/aws/awsfake"
+	"github.com/luhonghai/wsdl-example/pkg/soap"
+	"github.com/magiconair/properties/assert"
+)
+
+func TestTransferManagerWithFake(t *testing.T) {
+	fake := &awsfake.AmazonS3{
+		PutObjectInlineFunc: func(request *aws.PutObjectInline) (*aws.PutObjectInlineResponse, error) {
+			if request.Key == "denied.txt" {
+				return nil, &soap.Fault{Code: "soap:Client", String: "AccessDenied"}
+			}
+			return new(aws.PutObjectInlineResponse), nil
+		},
+	}
+
+	src := t.TempDir()
+	var uploads []*aws.Transfer
+	for _, name := range []string{"a.txt", "denied.txt"} {
+		if err := ioutil.WriteFile(filepath.Join(src, name), []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+		uploads = append(uploads, &aws.Transfer{Bucket: "docs", Key: name, Path: filepath.Join(src, name)})
+	}
+
+	manager := aws.NewTransferManager(fake, &aws.Credentials{AccessKeyID: "key", SecretAccessKey: "secret"})
+	manager.Concurrency = 1
+	manager.MaxRetries = 0
+	err := manager.Upload(uploads)
+
+	transferErr, ok := err.(*aws.TransferError)
+	if !ok {
+		t.Fatal("Expected a *TransferError, got", err)
+	}
+	assert.Equal(t, len(transferErr.Failures), 1)
+	assert.Equal(t, transferErr.Failures[0].Transfer.Key, "denied.txt")
+	assert.Equal(t, len(fake.PutObjectInlineRequests), 2)
+	assert.Equal(t, []byte(fake.PutObjectInlineRequests[0].Data), []byte("a.txt"))
+	assert.Equal(t, *fake.PutObjectInlineRequests[0].AWSAccessKeyId, "key")
+}
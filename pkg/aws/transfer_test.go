@@ -0,0 +1,88 @@
+package aws
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/magiconair/properties/assert"
+)
+
+func TestTransferManagerRoundTrip(t *testing.T) {
+	fake := newFakeS3(t)
+	s3 := NewAmazonS3(fake.URL, false, nil)
+	if _, err := s3.CreateBucket(&CreateBucket{Bucket: "photos"}); err != nil {
+		t.Fatal("Could not create bucket", err)
+	}
+
+	src, dst := t.TempDir(), t.TempDir()
+	var uploads, downloads []*Transfer
+	for i := 0; i < 6; i++ {
+		name := fmt.Sprintf("img-%d.bin", i)
+		data := bytes.Repeat([]byte{byte(i), 0, 0xff}, i+1)
+		if err := ioutil.WriteFile(filepath.Join(src, name), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+		uploads = append(uploads, &Transfer{Bucket: "photos", Key: name, Path: filepath.Join(src, name)})
+		downloads = append(downloads, &Transfer{Bucket: "photos", Key: name, Path: filepath.Join(dst, "nested", name)})
+	}
+	fake.transient["img-2.bin"] = 2
+
+	var events []ProgressEvent
+	manager := NewTransferManager(s3, nil)
+	manager.RetryDelay = 0
+	manager.OnProgress = func(event ProgressEvent) {
+		events = append(events, event)
+	}
+
+	if err := manager.Upload(uploads); err != nil {
+		t.Fatal("Could not upload", err)
+	}
+	// Six transfers plus two retried attempts.
+	assert.Equal(t, len(events), 8)
+	assert.Equal(t, events[len(events)-1].Done, 6)
+	assert.Equal(t, events[len(events)-1].Total, 6)
+	assert.Equal(t, len(fake.keys("photos")), 6)
+
+	if err := manager.Download(downloads); err != nil {
+		t.Fatal("Could not download", err)
+	}
+	for i, download := range downloads {
+		want, _ := ioutil.ReadFile(uploads[i].Path)
+		got, err := ioutil.ReadFile(download.Path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, got, want)
+	}
+}
+
+func TestTransferManagerAggregatesErrors(t *testing.T) {
+	fake := newFakeS3(t)
+	s3 := NewAmazonS3(fake.URL, false, nil)
+	fake.put("docs", "a.txt", []byte("a"))
+	fake.transient["flaky.txt"] = 10
+
+	manager := NewTransferManager(s3, nil)
+	manager.RetryDelay = 0
+	manager.MaxRetries = 1
+	before := fake.requests
+
+	dst := t.TempDir()
+	err := manager.Download([]*Transfer{
+		{Bucket: "docs", Key: "a.txt", Path: filepath.Join(dst, "a.txt")},
+		{Bucket: "docs", Key: "missing.txt", Path: filepath.Join(dst, "missing.txt")},
+		{Bucket: "docs", Key: "flaky.txt", Path: filepath.Join(dst, "flaky.txt")},
+	})
+
+	transferErr, ok := err.(*TransferError)
+	if !ok {
+		t.Fatal("Expected a *TransferError, got", err)
+	}
+	assert.Equal(t, transferErr.Total, 3)
+	assert.Equal(t, len(transferErr.Failures), 2)
+	// a.txt once, missing.txt is not retried, flaky.txt once plus one retry.
+	assert.Equal(t, fake.requests-before, 4)
+}
@@ -1,14 +1,15 @@
+// Code generated by wsdl-example generate; DO NOT EDIT.
+
+//go:generate go run github.com/luhonghai/wsdl-example generate --wsdl ../calculator.xml --package calculator --out . --import github.com/luhonghai/wsdl-example/pkg/calculator
+
 package calculator
 
 import (
-	"bytes"
-	"crypto/tls"
+	"context"
 	"encoding/xml"
-	"io/ioutil"
-	"log"
-	"net"
-	"net/http"
 	"time"
+
+	"github.com/luhonghai/wsdl-example/pkg/soap"
 )
 
 // against "unused imports"
@@ -18,65 +19,83 @@ var _ xml.Name
 type Add struct {
 	XMLName xml.Name `xml:"http://tempuri.org/ Add"`
 
-	IntA int32 `xml:"intA,omitempty"`
-	IntB int32 `xml:"intB,omitempty"`
+	IntA int32 `xml:"intA"`
+	IntB int32 `xml:"intB"`
 }
 
 type AddResponse struct {
 	XMLName xml.Name `xml:"http://tempuri.org/ AddResponse"`
 
-	AddResult int32 `xml:"AddResult,omitempty"`
+	AddResult int32 `xml:"AddResult"`
 }
 
 type Subtract struct {
 	XMLName xml.Name `xml:"http://tempuri.org/ Subtract"`
 
-	IntA int32 `xml:"intA,omitempty"`
-	IntB int32 `xml:"intB,omitempty"`
+	IntA int32 `xml:"intA"`
+	IntB int32 `xml:"intB"`
 }
 
 type SubtractResponse struct {
 	XMLName xml.Name `xml:"http://tempuri.org/ SubtractResponse"`
 
-	SubtractResult int32 `xml:"SubtractResult,omitempty"`
+	SubtractResult int32 `xml:"SubtractResult"`
 }
 
 type Multiply struct {
 	XMLName xml.Name `xml:"http://tempuri.org/ Multiply"`
 
-	IntA int32 `xml:"intA,omitempty"`
-	IntB int32 `xml:"intB,omitempty"`
+	IntA int32 `xml:"intA"`
+	IntB int32 `xml:"intB"`
 }
 
 type MultiplyResponse struct {
 	XMLName xml.Name `xml:"http://tempuri.org/ MultiplyResponse"`
 
-	MultiplyResult int32 `xml:"MultiplyResult,omitempty"`
+	MultiplyResult int32 `xml:"MultiplyResult"`
 }
 
 type Divide struct {
 	XMLName xml.Name `xml:"http://tempuri.org/ Divide"`
 
-	IntA int32 `xml:"intA,omitempty"`
-	IntB int32 `xml:"intB,omitempty"`
+	IntA int32 `xml:"intA"`
+	IntB int32 `xml:"intB"`
 }
 
 type DivideResponse struct {
 	XMLName xml.Name `xml:"http://tempuri.org/ DivideResponse"`
 
-	DivideResult int32 `xml:"DivideResult,omitempty"`
+	DivideResult int32 `xml:"DivideResult"`
+}
+
+// CalculatorSoapClient is the interface of the CalculatorSoap port.
+//
+// CalculatorSoap implements it, and so does the fake of the same name in
+// package calculatorfake, for tests.
+type CalculatorSoapClient interface {
+	Add(request *Add) (*AddResponse, error)
+	Subtract(request *Subtract) (*SubtractResponse, error)
+	Multiply(request *Multiply) (*MultiplyResponse, error)
+	Divide(request *Divide) (*DivideResponse, error)
 }
 
 type CalculatorSoap struct {
-	client *SOAPClient
+	client *soap.Client
 }
 
-func NewCalculatorSoap(url string, tls bool, auth *BasicAuth) *CalculatorSoap {
+var _ CalculatorSoapClient = (*CalculatorSoap)(nil)
+
+func NewCalculatorSoap(url string, tls bool, auth *soap.BasicAuth) *CalculatorSoap {
 	if url == "" {
 		url = "http://www.dneonline.com/calculator.asmx"
 	}
-	client := NewSOAPClient(url, tls, auth)
+	client := soap.NewClient(url, tls, auth)
+
+	return NewCalculatorSoapWithClient(client)
+}
 
+// NewCalculatorSoapWithClient returns a CalculatorSoap making its calls through client.
+func NewCalculatorSoapWithClient(client *soap.Client) *CalculatorSoap {
 	return &CalculatorSoap{
 		client: client,
 	}
@@ -123,185 +142,54 @@ func (service *CalculatorSoap) Divide(request *Divide) (*DivideResponse, error)
 	return response, nil
 }
 
-var timeout = time.Duration(30 * time.Second)
-
-func dialTimeout(network, addr string) (net.Conn, error) {
-	return net.DialTimeout(network, addr, timeout)
-}
-
-type SOAPEnvelope struct {
-	XMLName xml.Name `xml:"http://schemas.xmlsoap.org/soap/envelope/ Envelope"`
-
-	Body SOAPBody
-}
-
-type SOAPHeader struct {
-	XMLName xml.Name `xml:"http://schemas.xmlsoap.org/soap/envelope/ Header"`
-
-	Header interface{}
-}
-
-type SOAPBody struct {
-	XMLName xml.Name `xml:"http://schemas.xmlsoap.org/soap/envelope/ Body"`
-
-	Fault   *SOAPFault  `xml:",omitempty"`
-	Content interface{} `xml:",omitempty"`
-}
-
-type SOAPFault struct {
-	XMLName xml.Name `xml:"http://schemas.xmlsoap.org/soap/envelope/ Fault"`
-
-	Code   string `xml:"faultcode,omitempty"`
-	String string `xml:"faultstring,omitempty"`
-	Actor  string `xml:"faultactor,omitempty"`
-	Detail string `xml:"detail,omitempty"`
-}
-
-type BasicAuth struct {
-	Login    string
-	Password string
-}
-
-type SOAPClient struct {
-	url  string
-	tls  bool
-	auth *BasicAuth
-}
-
-func (b *SOAPBody) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
-	if b.Content == nil {
-		return xml.UnmarshalError("Content must be a pointer to a struct")
-	}
-
-	var (
-		token    xml.Token
-		err      error
-		consumed bool
-	)
-
-Loop:
-	for {
-		if token, err = d.Token(); err != nil {
-			return err
-		}
-
-		if token == nil {
-			break
-		}
-
-		switch se := token.(type) {
-		case xml.StartElement:
-			if consumed {
-				return xml.UnmarshalError("Found multiple elements inside SOAP body; not wrapped-document/literal WS-I compliant")
-			} else if se.Name.Space == "http://schemas.xmlsoap.org/soap/envelope/" && se.Name.Local == "Fault" {
-				b.Fault = &SOAPFault{}
-				b.Content = nil
-
-				err = d.DecodeElement(b.Fault, &se)
-				if err != nil {
-					return err
-				}
-
-				consumed = true
-			} else {
-				if err = d.DecodeElement(b.Content, &se); err != nil {
-					return err
-				}
-
-				consumed = true
-			}
-		case xml.EndElement:
-			break Loop
-		}
-	}
-
-	return nil
-}
-
-func (f *SOAPFault) Error() string {
-	return f.String
-}
-
-func NewSOAPClient(url string, tls bool, auth *BasicAuth) *SOAPClient {
-	return &SOAPClient{
-		url:  url,
-		tls:  tls,
-		auth: auth,
-	}
-}
-
-func (s *SOAPClient) Call(soapAction string, request, response interface{}) error {
-	envelope := SOAPEnvelope{
-		//Header:        SoapHeader{},
-	}
-
-	envelope.Body.Content = request
-	buffer := new(bytes.Buffer)
-
-	encoder := xml.NewEncoder(buffer)
-	//encoder.Indent("  ", "    ")
-
-	if err := encoder.Encode(envelope); err != nil {
-		return err
-	}
-
-	if err := encoder.Flush(); err != nil {
-		return err
-	}
-
-	//log.Println(buffer.String())
-
-	req, err := http.NewRequest("POST", s.url, buffer)
-	if err != nil {
-		return err
-	}
-	if s.auth != nil {
-		req.SetBasicAuth(s.auth.Login, s.auth.Password)
-	}
-
-	req.Header.Add("Content-Type", "text/xml; charset=\"utf-8\"")
-	if soapAction != "" {
-		req.Header.Add("SOAPAction", soapAction)
-	}
-
-	req.Header.Set("User-Agent", "gowsdl/0.1")
-	req.Close = true
-
-	tr := &http.Transport{
-		TLSClientConfig: &tls.Config{
-			InsecureSkipVerify: s.tls,
+// CalculatorSoapServer is implemented by services serving the CalculatorSoap port.
+type CalculatorSoapServer interface {
+	Add(ctx context.Context, request *Add) (*AddResponse, error)
+	Subtract(ctx context.Context, request *Subtract) (*SubtractResponse, error)
+	Multiply(ctx context.Context, request *Multiply) (*MultiplyResponse, error)
+	Divide(ctx context.Context, request *Divide) (*DivideResponse, error)
+}
+
+// NewCalculatorSoapHandler returns an http.Handler serving impl as the CalculatorSoap
+// port. Errors impl returns are sent as faults, a *soap.Fault as it is. The
+// WSDL document wsdl, when not nil, is served at ?wsdl.
+func NewCalculatorSoapHandler(impl CalculatorSoapServer, wsdl []byte) *soap.Server {
+	return soap.NewServer(wsdl,
+		&soap.Operation{
+			Name:       "Add",
+			SOAPAction: "http://tempuri.org/Add",
+			Element:    xml.Name{Space: "http://tempuri.org/", Local: "Add"},
+			NewRequest: func() interface{} { return new(Add) },
+			Handle: func(ctx context.Context, request interface{}) (interface{}, error) {
+				return impl.Add(ctx, request.(*Add))
+			},
 		},
-		Dial: dialTimeout,
-	}
-
-	client := &http.Client{Transport: tr}
-	res, err := client.Do(req)
-	if err != nil {
-		return err
-	}
-	defer res.Body.Close()
-
-	rawbody, err := ioutil.ReadAll(res.Body)
-	if err != nil {
-		return err
-	}
-	if len(rawbody) == 0 {
-		log.Println("empty response")
-		return nil
-	}
-
-	//log.Println(string(rawbody))
-	respEnvelope := new(SOAPEnvelope)
-	respEnvelope.Body = SOAPBody{Content: response}
-	err = xml.Unmarshal(rawbody, respEnvelope)
-	if err != nil {
-		return err
-	}
-
-	fault := respEnvelope.Body.Fault
-	if fault != nil {
-		return fault
-	}
-
-	return nil
+		&soap.Operation{
+			Name:       "Subtract",
+			SOAPAction: "http://tempuri.org/Subtract",
+			Element:    xml.Name{Space: "http://tempuri.org/", Local: "Subtract"},
+			NewRequest: func() interface{} { return new(Subtract) },
+			Handle: func(ctx context.Context, request interface{}) (interface{}, error) {
+				return impl.Subtract(ctx, request.(*Subtract))
+			},
+		},
+		&soap.Operation{
+			Name:       "Multiply",
+			SOAPAction: "http://tempuri.org/Multiply",
+			Element:    xml.Name{Space: "http://tempuri.org/", Local: "Multiply"},
+			NewRequest: func() interface{} { return new(Multiply) },
+			Handle: func(ctx context.Context, request interface{}) (interface{}, error) {
+				return impl.Multiply(ctx, request.(*Multiply))
+			},
+		},
+		&soap.Operation{
+			Name:       "Divide",
+			SOAPAction: "http://tempuri.org/Divide",
+			Element:    xml.Name{Space: "http://tempuri.org/", Local: "Divide"},
+			NewRequest: func() interface{} { return new(Divide) },
+			Handle: func(ctx context.Context, request interface{}) (interface{}, error) {
+				return impl.Divide(ctx, request.(*Divide))
+			},
+		},
+	)
 }
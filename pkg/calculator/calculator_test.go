@@ -1,13 +1,26 @@
 package calculator
 
 import (
+	"encoding/xml"
+	"errors"
+	"math"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
+	"github.com/luhonghai/wsdl-example/pkg/soap"
 	"github.com/magiconair/properties/assert"
 )
 
+func newTestService(t *testing.T) *CalculatorSoap {
+	server := httptest.NewServer(NewCalculatorSoapHandler(Server{}, nil))
+	t.Cleanup(server.Close)
+
+	return NewCalculatorSoap(server.URL, false, nil)
+}
+
 func TestAdd(t *testing.T) {
-	service := NewCalculatorSoap("", false, nil)
+	service := newTestService(t)
 	resp, err := service.Add(&Add{IntA: 10, IntB: 5})
 	if err != nil {
 		t.Error("Could not request", err)
@@ -17,7 +30,7 @@ func TestAdd(t *testing.T) {
 }
 
 func TestSubtract(t *testing.T) {
-	service := NewCalculatorSoap("", false, nil)
+	service := newTestService(t)
 
 	resp, err := service.Subtract(&Subtract{IntA: 10, IntB: 5})
 	if err != nil {
@@ -28,7 +41,7 @@ func TestSubtract(t *testing.T) {
 }
 
 func TestDevine(t *testing.T) {
-	service := NewCalculatorSoap("", false, nil)
+	service := newTestService(t)
 	resp, err := service.Divide(&Divide{IntA: 10, IntB: 5})
 	if err != nil {
 		t.Error("Could not request", err)
@@ -38,7 +51,7 @@ func TestDevine(t *testing.T) {
 }
 
 func TestMultiply(t *testing.T) {
-	service := NewCalculatorSoap("", false, nil)
+	service := newTestService(t)
 	resp, err := service.Multiply(&Multiply{IntA: 10, IntB: 5})
 	if err != nil {
 		t.Error("Could not request", err)
@@ -46,3 +59,43 @@ func TestMultiply(t *testing.T) {
 		assert.Equal(t, resp.MultiplyResult, int32(50))
 	}
 }
+
+func TestRequiredZeroValues(t *testing.T) {
+	data, err := xml.Marshal(&Add{IntA: 0, IntB: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "<intA>0</intA>") {
+		t.Errorf("intA missing from %s", data)
+	}
+}
+
+func TestFaults(t *testing.T) {
+	service := newTestService(t)
+
+	_, err := service.Divide(&Divide{IntA: 10, IntB: 0})
+	var fault *soap.Fault
+	if !errors.As(err, &fault) {
+		t.Fatalf("got %v, want a fault", err)
+	}
+	assert.Equal(t, fault.Code, "soap:Server")
+	assert.Equal(t, fault.String, "Attempted to divide by zero.")
+
+	_, err = service.Add(&Add{IntA: math.MaxInt32, IntB: 1})
+	if !errors.As(err, &fault) {
+		t.Fatalf("got %v, want a fault", err)
+	}
+	assert.Equal(t, fault.Code, "soap:Server")
+	assert.Equal(t, fault.String, "Arithmetic operation resulted in an overflow.")
+
+	_, err = service.Divide(&Divide{IntA: math.MinInt32, IntB: -1})
+	if !errors.As(err, &fault) {
+		t.Fatalf("got %v, want a fault", err)
+	}
+
+	resp, err := service.Multiply(&Multiply{IntA: -65536, IntB: 32768})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, resp.MultiplyResult, int32(math.MinInt32))
+}
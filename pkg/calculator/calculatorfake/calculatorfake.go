@@ -0,0 +1,82 @@
+// Code generated by wsdl-example generate; DO NOT EDIT.
+
+// Package calculatorfake holds in-memory implementations of the ports of
+// package calculator, for tests.
+package calculatorfake
+
+import (
+	"sync"
+
+	"github.com/luhonghai/wsdl-example/pkg/calculator"
+)
+
+// CalculatorSoap is an in-memory calculator.CalculatorSoapClient.
+//
+// It records the requests of every call and answers with the function set
+// for the operation, or with an empty response when there is none.
+type CalculatorSoap struct {
+	mu sync.Mutex
+
+	AddFunc          func(request *calculator.Add) (*calculator.AddResponse, error)
+	AddRequests      []*calculator.Add
+	SubtractFunc     func(request *calculator.Subtract) (*calculator.SubtractResponse, error)
+	SubtractRequests []*calculator.Subtract
+	MultiplyFunc     func(request *calculator.Multiply) (*calculator.MultiplyResponse, error)
+	MultiplyRequests []*calculator.Multiply
+	DivideFunc       func(request *calculator.Divide) (*calculator.DivideResponse, error)
+	DivideRequests   []*calculator.Divide
+}
+
+var _ calculator.CalculatorSoapClient = (*CalculatorSoap)(nil)
+
+func (fake *CalculatorSoap) Add(request *calculator.Add) (*calculator.AddResponse, error) {
+	fake.mu.Lock()
+	fake.AddRequests = append(fake.AddRequests, request)
+	fn := fake.AddFunc
+	fake.mu.Unlock()
+
+	if fn != nil {
+		return fn(request)
+	}
+
+	return new(calculator.AddResponse), nil
+}
+
+func (fake *CalculatorSoap) Subtract(request *calculator.Subtract) (*calculator.SubtractResponse, error) {
+	fake.mu.Lock()
+	fake.SubtractRequests = append(fake.SubtractRequests, request)
+	fn := fake.SubtractFunc
+	fake.mu.Unlock()
+
+	if fn != nil {
+		return fn(request)
+	}
+
+	return new(calculator.SubtractResponse), nil
+}
+
+func (fake *CalculatorSoap) Multiply(request *calculator.Multiply) (*calculator.MultiplyResponse, error) {
+	fake.mu.Lock()
+	fake.MultiplyRequests = append(fake.MultiplyRequests, request)
+	fn := fake.MultiplyFunc
+	fake.mu.Unlock()
+
+	if fn != nil {
+		return fn(request)
+	}
+
+	return new(calculator.MultiplyResponse), nil
+}
+
+func (fake *CalculatorSoap) Divide(request *calculator.Divide) (*calculator.DivideResponse, error) {
+	fake.mu.Lock()
+	fake.DivideRequests = append(fake.DivideRequests, request)
+	fn := fake.DivideFunc
+	fake.mu.Unlock()
+
+	if fn != nil {
+		return fn(request)
+	}
+
+	return new(calculator.DivideResponse), nil
+}
@@ -0,0 +1,64 @@
+package calculator
+
+import (
+	"context"
+	"math"
+
+	"github.com/luhonghai/wsdl-example/pkg/soap"
+)
+
+// Server is a reference implementation of the CalculatorSoap port. Results
+// that do not fit an int32, and divisions by zero, are reported as server
+// faults rather than wrapped around, as the original service reports the
+// exceptions they raise.
+type Server struct{}
+
+var _ CalculatorSoapServer = Server{}
+
+func (Server) Add(ctx context.Context, request *Add) (*AddResponse, error) {
+	result, err := checkResult(int64(request.IntA) + int64(request.IntB))
+	if err != nil {
+		return nil, err
+	}
+
+	return &AddResponse{AddResult: result}, nil
+}
+
+func (Server) Subtract(ctx context.Context, request *Subtract) (*SubtractResponse, error) {
+	result, err := checkResult(int64(request.IntA) - int64(request.IntB))
+	if err != nil {
+		return nil, err
+	}
+
+	return &SubtractResponse{SubtractResult: result}, nil
+}
+
+func (Server) Multiply(ctx context.Context, request *Multiply) (*MultiplyResponse, error) {
+	result, err := checkResult(int64(request.IntA) * int64(request.IntB))
+	if err != nil {
+		return nil, err
+	}
+
+	return &MultiplyResponse{MultiplyResult: result}, nil
+}
+
+// Divide truncates the quotient toward zero, as the original service does.
+func (Server) Divide(ctx context.Context, request *Divide) (*DivideResponse, error) {
+	if request.IntB == 0 {
+		return nil, &soap.Fault{Code: "soap:Server", String: "Attempted to divide by zero."}
+	}
+	result, err := checkResult(int64(request.IntA) / int64(request.IntB))
+	if err != nil {
+		return nil, err
+	}
+
+	return &DivideResponse{DivideResult: result}, nil
+}
+
+func checkResult(result int64) (int32, error) {
+	if result < math.MinInt32 || result > math.MaxInt32 {
+		return 0, &soap.Fault{Code: "soap:Server", String: "Arithmetic operation resulted in an overflow."}
+	}
+
+	return int32(result), nil
+}
@@ -0,0 +1,250 @@
+// Package cassette records the SOAP traffic of a client to a file and plays
+// it back, so that tests of services one cannot reach from everywhere run
+// offline and deterministically. Plug a Recorder into a client with
+// soap.Client.SetTransport.
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Mode tells whether a Recorder records or replays.
+type Mode int
+
+const (
+	// Replay answers from the cassette without any network access.
+	Replay Mode = iota
+	// Record sends requests on and writes the traffic to the cassette,
+	// replacing what it held.
+	Record
+)
+
+// ModeFromEnv returns Record when the environment variable SOAP_RECORD is
+// set to a non-empty value, and Replay otherwise. Tests use it to refresh
+// their cassettes on demand.
+func ModeFromEnv() Mode {
+	if os.Getenv("SOAP_RECORD") != "" {
+		return Record
+	}
+
+	return Replay
+}
+
+// Interaction is a request and the response it got.
+type Interaction struct {
+	SOAPAction string `json:"soapAction,omitempty"`
+	Request    string `json:"request"`
+	Status     int    `json:"status"`
+	Response   string `json:"response"`
+}
+
+// Cassette is the content of a cassette file.
+type Cassette struct {
+	// Comment tells where the interactions come from, notably for
+	// cassettes written by hand rather than recorded.
+	Comment      string         `json:"comment,omitempty"`
+	Interactions []*Interaction `json:"interactions"`
+}
+
+// Recorder is an http.RoundTripper recording to or replaying from a
+// cassette file. Replayed requests are matched on their SOAPAction and on
+// their body, compared regardless of namespace prefixes, attribute order
+// and indentation, and without the elements named as ignored.
+type Recorder struct {
+	// Transport sends the requests being recorded. http.DefaultTransport
+	// is used when it is nil.
+	Transport http.RoundTripper
+
+	path     string
+	mode     Mode
+	ignore   map[string]bool
+	mu       sync.Mutex
+	cassette *Cassette
+	used     map[*Interaction]bool
+}
+
+// New returns a recorder for the cassette at path. In Replay mode the
+// cassette is read at once. ignore holds the local names of elements whose
+// content changes from run to run, such as "Timestamp" and "Signature" in
+// signed S3 requests.
+func New(path string, mode Mode, ignore ...string) (*Recorder, error) {
+	r := &Recorder{
+		path:     path,
+		mode:     mode,
+		ignore:   make(map[string]bool),
+		cassette: new(Cassette),
+		used:     make(map[*Interaction]bool),
+	}
+	for _, local := range ignore {
+		r.ignore[local] = true
+	}
+
+	if mode == Replay {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(data, r.cassette); err != nil {
+			return nil, fmt.Errorf("cassette: reading %s: %v", path, err)
+		}
+	}
+
+	return r, nil
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		if body, err = ioutil.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		req.Body.Close()
+	}
+	soapAction := strings.Trim(req.Header.Get("SOAPAction"), `"`)
+
+	if r.mode == Replay {
+		interaction, err := r.find(soapAction, body)
+		if err != nil {
+			return nil, err
+		}
+
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", interaction.Status, http.StatusText(interaction.Status)),
+			StatusCode:    interaction.Status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        http.Header{"Content-Type": {`text/xml; charset="utf-8"`}},
+			Body:          ioutil.NopCloser(strings.NewReader(interaction.Response)),
+			ContentLength: int64(len(interaction.Response)),
+			Request:       req,
+		}, nil
+	}
+
+	transport := r.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	res, err := transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	response, err := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	res.Body = ioutil.NopCloser(bytes.NewReader(response))
+
+	err = r.record(&Interaction{
+		SOAPAction: soapAction,
+		Request:    string(body),
+		Status:     res.StatusCode,
+		Response:   string(response),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// record adds interaction to the cassette and writes it out, so that the
+// cassette is complete whenever the test stops.
+func (r *Recorder) record(interaction *Interaction) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+	// The envelopes are kept readable, without escaping their markup.
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(r.cassette); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0755); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(r.path, buffer.Bytes(), 0644)
+}
+
+// find returns the first interaction not replayed yet matching a request,
+// or the last one matching when all were, so that identical requests are
+// answered in the order they were recorded.
+func (r *Recorder) find(soapAction string, body []byte) (*Interaction, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := r.normalize(body)
+	var last *Interaction
+	for _, interaction := range r.cassette.Interactions {
+		if interaction.SOAPAction != soapAction || r.normalize([]byte(interaction.Request)) != key {
+			continue
+		}
+		if !r.used[interaction] {
+			r.used[interaction] = true
+			return interaction, nil
+		}
+		last = interaction
+	}
+	if last == nil {
+		return nil, fmt.Errorf("cassette: %s holds no interaction for SOAPAction %q and this request", r.path, soapAction)
+	}
+
+	return last, nil
+}
+
+// normalize returns the form of a request body requests are matched on.
+// Bodies that are not XML are compared as they are.
+func (r *Recorder) normalize(body []byte) string {
+	var b strings.Builder
+	d := xml.NewDecoder(bytes.NewReader(body))
+	for {
+		token, err := d.Token()
+		if err == io.EOF {
+			return b.String()
+		}
+		if err != nil {
+			return string(body)
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			if r.ignore[t.Name.Local] {
+				if err := d.Skip(); err != nil {
+					return string(body)
+				}
+				continue
+			}
+			var attrs []string
+			for _, attr := range t.Attr {
+				if attr.Name.Space == "xmlns" || attr.Name.Space == "" && attr.Name.Local == "xmlns" {
+					continue
+				}
+				attrs = append(attrs, fmt.Sprintf(" {%s}%s=%q", attr.Name.Space, attr.Name.Local, attr.Value))
+			}
+			sort.Strings(attrs)
+			b.WriteString("<{" + t.Name.Space + "}" + t.Name.Local + strings.Join(attrs, "") + ">")
+		case xml.EndElement:
+			b.WriteString("</>")
+		case xml.CharData:
+			b.WriteString(strings.TrimSpace(string(t)))
+		}
+	}
+}
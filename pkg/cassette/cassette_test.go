@@ -0,0 +1,86 @@
+package cassette
+
+import (
+	"encoding/xml"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/luhonghai/wsdl-example/pkg/calculator"
+	"github.com/luhonghai/wsdl-example/pkg/soap"
+	"github.com/magiconair/properties/assert"
+)
+
+func TestRecordReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "calculator.json")
+
+	server := httptest.NewServer(calculator.NewCalculatorSoapHandler(calculator.Server{}, nil))
+	recorder, err := New(path, Record)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := soap.NewClient(server.URL, false, nil)
+	client.SetTransport(recorder)
+	service := calculator.NewCalculatorSoapWithClient(client)
+	if _, err := service.Add(&calculator.Add{IntA: 1, IntB: 2}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.Divide(&calculator.Divide{IntA: 1, IntB: 0}); err == nil {
+		t.Fatal("division by zero succeeded")
+	}
+	server.Close()
+
+	replayer, err := New(path, Replay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.SetTransport(replayer)
+
+	resp, err := service.Add(&calculator.Add{IntA: 1, IntB: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, resp.AddResult, int32(3))
+	_, err = service.Divide(&calculator.Divide{IntA: 1, IntB: 0})
+	assert.Equal(t, err.Error(), "Attempted to divide by zero.")
+
+	_, err = service.Add(&calculator.Add{IntA: 2, IntB: 2})
+	assert.Equal(t, err.Error(), `Post "`+server.URL+`": cassette: `+path+` holds no interaction for SOAPAction "http://tempuri.org/Add" and this request`)
+}
+
+type signed struct {
+	XMLName   xml.Name `xml:"urn:test Get"`
+	Key       string   `xml:"Key"`
+	Timestamp string   `xml:"Timestamp"`
+}
+
+func TestReplayIgnoresVolatileElements(t *testing.T) {
+	r := &Recorder{
+		ignore: map[string]bool{"Timestamp": true},
+		cassette: &Cassette{Interactions: []*Interaction{{
+			SOAPAction: "urn:test/Get",
+			Request: `<s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/"><s:Body>
+  <Get xmlns="urn:test">
+    <Key>a</Key>
+    <Timestamp>2009-10-12T17:50:30Z</Timestamp>
+  </Get>
+</s:Body></s:Envelope>`,
+			Status:   200,
+			Response: `<Envelope xmlns="http://schemas.xmlsoap.org/soap/envelope/"><Body><GetResponse xmlns="urn:test"/></Body></Envelope>`,
+		}}},
+		used: make(map[*Interaction]bool),
+	}
+	client := soap.NewClient("http://example.invalid", false, nil)
+	client.SetTransport(r)
+
+	if err := client.Call("urn:test/Get", &signed{Key: "a", Timestamp: "now"}, new(struct{})); err != nil {
+		t.Fatal(err)
+	}
+	// Replayed interactions are answered again once all were used.
+	if err := client.Call("urn:test/Get", &signed{Key: "a"}, new(struct{})); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Call("urn:test/Get", &signed{Key: "b"}, new(struct{})); err == nil {
+		t.Error("a request for another key was replayed")
+	}
+}
@@ -0,0 +1,369 @@
+// Package contract summarises WSDL documents: what a service offers, where,
+// and the shape of the documents it exchanges.
+package contract
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/luhonghai/wsdl-example/pkg/wsdl"
+)
+
+// Description is the outline of a WSDL document.
+type Description struct {
+	Name            string     `json:"name,omitempty"`
+	TargetNamespace string     `json:"targetNamespace"`
+	Services        []*Service `json:"services"`
+}
+
+type Service struct {
+	Name          string  `json:"name"`
+	Documentation string  `json:"documentation,omitempty"`
+	Ports         []*Port `json:"ports"`
+}
+
+// Port is an endpoint along with the operations it serves.
+type Port struct {
+	Name        string       `json:"name"`
+	Address     string       `json:"address"`
+	Binding     string       `json:"binding"`
+	PortType    string       `json:"portType"`
+	SOAPVersion string       `json:"soapVersion,omitempty"`
+	Operations  []*Operation `json:"operations"`
+}
+
+type Operation struct {
+	Name          string   `json:"name"`
+	Documentation string   `json:"documentation,omitempty"`
+	SOAPAction    string   `json:"soapAction,omitempty"`
+	Style         string   `json:"style,omitempty"`
+	Input         *Element `json:"input,omitempty"`
+	Output        *Element `json:"output,omitempty"`
+}
+
+// Element describes an element and, for complex types, its content.
+type Element struct {
+	Name string `json:"name"`
+	// Type is the name of the element type: prefixed xsd: for built-in
+	// types, unqualified for types of the target namespace and empty for
+	// anonymous ones.
+	Type      string `json:"type,omitempty"`
+	MinOccurs int    `json:"minOccurs"`
+	// MaxOccurs is wsdl.Unbounded, -1, for elements repeating at will.
+	MaxOccurs   int      `json:"maxOccurs"`
+	Nillable    bool     `json:"nillable,omitempty"`
+	Enumeration []string `json:"enumeration,omitempty"`
+	// Derived lists the types that may stand in for an abstract type.
+	Derived    []string     `json:"derived,omitempty"`
+	Attributes []*Attribute `json:"attributes,omitempty"`
+	Children   []*Element   `json:"children,omitempty"`
+	// Recursive is set on elements whose type encloses them, whose content
+	// is described further up.
+	Recursive bool `json:"recursive,omitempty"`
+}
+
+type Attribute struct {
+	Name     string `json:"name"`
+	Type     string `json:"type,omitempty"`
+	Required bool   `json:"required,omitempty"`
+}
+
+// Describe returns the outline of defs.
+func Describe(defs *wsdl.Definitions) *Description {
+	d := &describer{defs: defs, derived: make(map[wsdl.QName][]wsdl.QName)}
+	for _, schema := range defs.Schemas {
+		for _, ct := range schema.ComplexTypes {
+			if !ct.Base.IsZero() && !ct.SimpleContent {
+				name := wsdl.QName{Space: schema.TargetNamespace, Local: ct.Name}
+				d.derived[ct.Base] = append(d.derived[ct.Base], name)
+			}
+		}
+	}
+
+	description := &Description{Name: defs.Name, TargetNamespace: defs.TargetNamespace}
+	for _, s := range defs.Services {
+		service := &Service{Name: s.Name, Documentation: s.Documentation}
+		for _, p := range s.Ports {
+			service.Ports = append(service.Ports, d.port(p))
+		}
+		description.Services = append(description.Services, service)
+	}
+
+	return description
+}
+
+type describer struct {
+	defs    *wsdl.Definitions
+	derived map[wsdl.QName][]wsdl.QName
+}
+
+func (d *describer) port(p *wsdl.Port) *Port {
+	port := &Port{Name: p.Name, Address: p.Address, Binding: p.Binding.Local}
+	binding := d.defs.Binding(p.Binding)
+	if binding == nil {
+		return port
+	}
+	port.SOAPVersion = binding.SOAPVersion
+	portType := d.defs.PortType(binding.Type)
+	if portType == nil {
+		return port
+	}
+	port.PortType = portType.Name
+
+	for _, op := range portType.Operations {
+		operation := &Operation{Name: op.Name, Documentation: op.Documentation}
+		if bop := binding.Operation(op.Name); bop != nil {
+			operation.SOAPAction = bop.SOAPAction
+			operation.Style = bop.Style
+		}
+		if op.Input != nil {
+			operation.Input = d.message(op.Input.Message)
+		}
+		if op.Output != nil {
+			operation.Output = d.message(op.Output.Message)
+		}
+		port.Operations = append(port.Operations, operation)
+	}
+
+	return port
+}
+
+// message describes the document carried by a message: its element, or
+// for rpc style a wrapper holding a child per part.
+func (d *describer) message(name wsdl.QName) *Element {
+	message := d.defs.Message(name)
+	if message == nil {
+		return nil
+	}
+	if len(message.Parts) == 1 && !message.Parts[0].Element.IsZero() {
+		e := d.defs.Element(message.Parts[0].Element)
+		if e == nil {
+			return &Element{Name: message.Parts[0].Element.Local, MinOccurs: 1, MaxOccurs: 1}
+		}
+		return d.element(e, make(map[*wsdl.ComplexType]bool))
+	}
+
+	wrapper := &Element{Name: message.Name, MinOccurs: 1, MaxOccurs: 1}
+	for _, part := range message.Parts {
+		if !part.Element.IsZero() {
+			if e := d.defs.Element(part.Element); e != nil {
+				wrapper.Children = append(wrapper.Children, d.element(e, make(map[*wsdl.ComplexType]bool)))
+			}
+			continue
+		}
+		e := &wsdl.Element{Name: part.Name, Type: part.Type, MinOccurs: 1, MaxOccurs: 1}
+		wrapper.Children = append(wrapper.Children, d.element(e, make(map[*wsdl.ComplexType]bool)))
+	}
+
+	return wrapper
+}
+
+// element describes e. Types being described on the way down are in seen.
+func (d *describer) element(e *wsdl.Element, seen map[*wsdl.ComplexType]bool) *Element {
+	if !e.Ref.IsZero() {
+		if ref := d.defs.Element(e.Ref); ref != nil {
+			resolved := *ref
+			resolved.MinOccurs, resolved.MaxOccurs = e.MinOccurs, e.MaxOccurs
+			e = &resolved
+		}
+	}
+
+	element := &Element{
+		Name:      e.Name,
+		Type:      d.typeName(e.Type),
+		MinOccurs: e.MinOccurs,
+		MaxOccurs: e.MaxOccurs,
+		Nillable:  e.Nillable,
+	}
+	if e.SimpleType != nil {
+		element.Type = d.typeName(e.SimpleType.Base)
+		element.Enumeration = e.SimpleType.Enumeration
+	}
+	if st := d.defs.SimpleType(e.Type); st != nil {
+		element.Enumeration = st.Enumeration
+	}
+
+	ct := e.ComplexType
+	if ct == nil {
+		ct = d.defs.ComplexType(e.Type)
+	}
+	if ct == nil {
+		return element
+	}
+	if seen[ct] {
+		element.Recursive = true
+		return element
+	}
+	seen[ct] = true
+	defer delete(seen, ct)
+
+	if ct.SimpleContent && element.Type == "" {
+		element.Type = d.typeName(ct.Base)
+	}
+	if ct.Abstract {
+		for _, derived := range d.concrete(e.Type) {
+			element.Derived = append(element.Derived, d.typeName(derived))
+		}
+	}
+	for _, a := range d.attributes(ct) {
+		element.Attributes = append(element.Attributes, &Attribute{
+			Name:     a.Name,
+			Type:     d.typeName(a.Type),
+			Required: a.Use == "required",
+		})
+	}
+	for _, child := range d.content(ct) {
+		element.Children = append(element.Children, d.element(child, seen))
+	}
+
+	return element
+}
+
+// concrete returns the types deriving from base, directly or not, which are
+// not abstract themselves.
+func (d *describer) concrete(base wsdl.QName) []wsdl.QName {
+	var types []wsdl.QName
+	for _, derived := range d.derived[base] {
+		if ct := d.defs.ComplexType(derived); ct != nil && !ct.Abstract {
+			types = append(types, derived)
+		}
+		types = append(types, d.concrete(derived)...)
+	}
+
+	return types
+}
+
+// content returns the elements of ct, those inherited from its base first.
+func (d *describer) content(ct *wsdl.ComplexType) []*wsdl.Element {
+	var elements []*wsdl.Element
+	if !ct.SimpleContent && !ct.Base.IsZero() {
+		if base := d.defs.ComplexType(ct.Base); base != nil {
+			elements = append(elements, d.content(base)...)
+		}
+	}
+
+	return append(elements, ct.Elements...)
+}
+
+func (d *describer) attributes(ct *wsdl.ComplexType) []*wsdl.Attribute {
+	var attributes []*wsdl.Attribute
+	if !ct.SimpleContent && !ct.Base.IsZero() {
+		if base := d.defs.ComplexType(ct.Base); base != nil {
+			attributes = append(attributes, d.attributes(base)...)
+		}
+	}
+
+	return append(attributes, ct.Attributes...)
+}
+
+func (d *describer) typeName(name wsdl.QName) string {
+	switch {
+	case name.IsZero():
+		return ""
+	case wsdl.IsBuiltin(name):
+		return "xsd:" + name.Local
+	case name.Space == d.defs.TargetNamespace:
+		return name.Local
+	}
+
+	return name.String()
+}
+
+// WriteTree writes d as an indented tree.
+func (d *Description) WriteTree(w io.Writer) error {
+	p := &printer{w: w}
+	if d.Name != "" {
+		p.line(0, "Definitions %s {%s}", d.Name, d.TargetNamespace)
+	} else {
+		p.line(0, "Definitions {%s}", d.TargetNamespace)
+	}
+	for _, s := range d.Services {
+		p.line(1, "Service %s", s.Name)
+		p.documentation(2, s.Documentation)
+		for _, port := range s.Ports {
+			version := "no SOAP binding"
+			if port.SOAPVersion != "" {
+				version = "SOAP " + port.SOAPVersion
+			}
+			p.line(2, "Port %s (%s, binding %s, port type %s)", port.Name, version, port.Binding, port.PortType)
+			p.line(3, "Address %s", port.Address)
+			for _, op := range port.Operations {
+				p.line(3, "Operation %s (soapAction %q, %s)", op.Name, op.SOAPAction, op.Style)
+				p.documentation(4, op.Documentation)
+				if op.Input != nil {
+					p.line(4, "Input")
+					p.element(5, op.Input)
+				}
+				if op.Output != nil {
+					p.line(4, "Output")
+					p.element(5, op.Output)
+				}
+			}
+		}
+	}
+
+	return p.err
+}
+
+type printer struct {
+	w   io.Writer
+	err error
+}
+
+func (p *printer) line(depth int, format string, args ...interface{}) {
+	if p.err == nil {
+		_, p.err = fmt.Fprintf(p.w, strings.Repeat("  ", depth)+format+"\n", args...)
+	}
+}
+
+func (p *printer) documentation(depth int, doc string) {
+	if doc != "" {
+		p.line(depth, "# %s", strings.Join(strings.Fields(doc), " "))
+	}
+}
+
+func (p *printer) element(depth int, e *Element) {
+	text := e.Name
+	if e.Type != "" {
+		text += " " + e.Type
+	}
+	if e.MinOccurs != 1 || e.MaxOccurs != 1 {
+		text += " " + Cardinality(e.MinOccurs, e.MaxOccurs)
+	}
+	if e.Nillable {
+		text += " nillable"
+	}
+	if len(e.Enumeration) > 0 {
+		text += " (" + strings.Join(e.Enumeration, " | ") + ")"
+	}
+	if len(e.Derived) > 0 {
+		text += " abstract, one of " + strings.Join(e.Derived, ", ")
+	}
+	if e.Recursive {
+		text += " (recursive)"
+	}
+	p.line(depth, "%s", text)
+
+	for _, a := range e.Attributes {
+		use := "optional"
+		if a.Required {
+			use = "required"
+		}
+		p.line(depth+1, "@%s %s %s", a.Name, a.Type, use)
+	}
+	for _, child := range e.Children {
+		p.element(depth+1, child)
+	}
+}
+
+// Cardinality formats occurrence bounds as [min..max], with * for
+// unbounded.
+func Cardinality(min, max int) string {
+	upper := "*"
+	if max != wsdl.Unbounded {
+		upper = fmt.Sprint(max)
+	}
+
+	return fmt.Sprintf("[%d..%s]", min, upper)
+}
@@ -0,0 +1,77 @@
+package contract
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/luhonghai/wsdl-example/pkg/wsdl"
+	"github.com/magiconair/properties/assert"
+)
+
+func load(t *testing.T, path string) *wsdl.Definitions {
+	catalog, err := wsdl.ReadCatalog("../schemas/catalog.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defs, err := (&wsdl.Loader{Catalog: catalog}).Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return defs
+}
+
+func TestDescribeCalculator(t *testing.T) {
+	description := Describe(load(t, "../calculator.xml"))
+
+	assert.Equal(t, len(description.Services), 1)
+	ports := description.Services[0].Ports
+	assert.Equal(t, len(ports), 2)
+	assert.Equal(t, ports[0].SOAPVersion, "1.1")
+	assert.Equal(t, ports[1].SOAPVersion, "1.2")
+	assert.Equal(t, ports[1].PortType, "CalculatorSoap")
+	assert.Equal(t, ports[0].Address, "http://www.dneonline.com/calculator.asmx")
+
+	add := ports[0].Operations[0]
+	assert.Equal(t, add.SOAPAction, "http://tempuri.org/Add")
+	assert.Equal(t, add.Input.Children[1], &Element{Name: "intB", Type: "xsd:int", MinOccurs: 1, MaxOccurs: 1})
+
+	var tree bytes.Buffer
+	if err := description.WriteTree(&tree); err != nil {
+		t.Fatal(err)
+	}
+	want := `      Operation Add (soapAction "http://tempuri.org/Add", document)
+        # Adds two integers. This is a test WebService. ©DNE Online
+        Input
+          Add
+            intA xsd:int
+            intB xsd:int
+        Output
+          AddResponse
+            AddResult xsd:int
+`
+	if !strings.Contains(tree.String(), want) {
+		t.Errorf("tree lacks\n%s\ngot\n%s", want, tree.String())
+	}
+}
+
+func TestDescribeNestedTypes(t *testing.T) {
+	description := Describe(load(t, "../AmazonS3.wsdl"))
+
+	var policy *Operation
+	for _, op := range description.Services[0].Ports[0].Operations {
+		if op.Name == "SetBucketAccessControlPolicy" {
+			policy = op
+		}
+	}
+	acl := policy.Input.Children[1]
+	assert.Equal(t, acl.Name, "AccessControlList")
+	assert.Equal(t, acl.MinOccurs, 0)
+	grant := acl.Children[0]
+	assert.Equal(t, grant.MaxOccurs, 100)
+	assert.Equal(t, grant.Children[0].Derived, []string{"AmazonCustomerByEmail", "CanonicalUser", "Group"})
+	assert.Equal(t, grant.Children[1].Enumeration, []string{"READ", "WRITE", "READ_ACP", "WRITE_ACP", "FULL_CONTROL"})
+
+	assert.Equal(t, Cardinality(0, wsdl.Unbounded), "[0..*]")
+}
@@ -0,0 +1,363 @@
+package contract
+
+import (
+	"fmt"
+	"io"
+)
+
+// Change is a difference between two versions of a contract.
+type Change struct {
+	// Path locates the change, as in "Calculator.CalculatorSoap.Add.input.Add.intA".
+	Path    string `json:"path"`
+	Message string `json:"message"`
+	// Breaking is set when clients generated from the old version may fail
+	// against the new one.
+	Breaking bool `json:"breaking"`
+}
+
+func (c *Change) String() string {
+	kind := "compatible"
+	if c.Breaking {
+		kind = "BREAKING"
+	}
+
+	return fmt.Sprintf("%-10s %s: %s", kind, c.Path, c.Message)
+}
+
+// Diff returns the changes from old to new. Requests and responses are
+// judged from the client side: a client must still be able to send what it
+// sent before, and to understand what it receives.
+func Diff(old, new *Description) []*Change {
+	d := &differ{}
+	if old.TargetNamespace != new.TargetNamespace {
+		d.add(true, "", "target namespace changed from %s to %s", old.TargetNamespace, new.TargetNamespace)
+	}
+
+	for _, oldService := range old.Services {
+		newService := findService(new.Services, oldService.Name)
+		if newService == nil {
+			d.add(true, oldService.Name, "service removed")
+			continue
+		}
+		for _, oldPort := range oldService.Ports {
+			path := oldService.Name + "." + oldPort.Name
+			newPort := findPort(newService.Ports, oldPort.Name)
+			if newPort == nil {
+				d.add(true, path, "port removed")
+				continue
+			}
+			d.port(path, oldPort, newPort)
+		}
+		for _, newPort := range newService.Ports {
+			if findPort(oldService.Ports, newPort.Name) == nil {
+				d.add(false, oldService.Name+"."+newPort.Name, "port added")
+			}
+		}
+	}
+	for _, newService := range new.Services {
+		if findService(old.Services, newService.Name) == nil {
+			d.add(false, newService.Name, "service added")
+		}
+	}
+
+	return d.changes
+}
+
+// Breaking reports whether any of changes is breaking.
+func Breaking(changes []*Change) bool {
+	for _, change := range changes {
+		if change.Breaking {
+			return true
+		}
+	}
+
+	return false
+}
+
+// WriteChanges writes one line per change.
+func WriteChanges(w io.Writer, changes []*Change) error {
+	for _, change := range changes {
+		if _, err := fmt.Fprintln(w, change); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type differ struct {
+	changes []*Change
+}
+
+func (d *differ) add(breaking bool, path, format string, args ...interface{}) {
+	d.changes = append(d.changes, &Change{Path: path, Message: fmt.Sprintf(format, args...), Breaking: breaking})
+}
+
+func (d *differ) port(path string, old, new *Port) {
+	if old.Address != new.Address {
+		d.add(true, path, "address changed from %s to %s", old.Address, new.Address)
+	}
+	if old.SOAPVersion != new.SOAPVersion {
+		d.add(true, path, "SOAP version changed from %q to %q", old.SOAPVersion, new.SOAPVersion)
+	}
+	if old.PortType != new.PortType {
+		d.add(true, path, "port type changed from %s to %s", old.PortType, new.PortType)
+	}
+
+	for _, oldOp := range old.Operations {
+		opPath := path + "." + oldOp.Name
+		newOp := findOperation(new.Operations, oldOp.Name)
+		if newOp == nil {
+			d.add(true, opPath, "operation removed")
+			continue
+		}
+		if oldOp.SOAPAction != newOp.SOAPAction {
+			d.add(true, opPath, "soapAction changed from %q to %q", oldOp.SOAPAction, newOp.SOAPAction)
+		}
+		if oldOp.Style != newOp.Style {
+			d.add(true, opPath, "style changed from %s to %s", oldOp.Style, newOp.Style)
+		}
+		d.message(opPath+".input", oldOp.Input, newOp.Input, true)
+		d.message(opPath+".output", oldOp.Output, newOp.Output, false)
+	}
+	for _, newOp := range new.Operations {
+		if findOperation(old.Operations, newOp.Name) == nil {
+			d.add(false, path+"."+newOp.Name, "operation added")
+		}
+	}
+}
+
+func (d *differ) message(path string, old, new *Element, input bool) {
+	switch {
+	case old == nil && new == nil:
+		return
+	case old == nil:
+		d.add(true, path, "message added")
+		return
+	case new == nil:
+		d.add(true, path, "message removed")
+		return
+	}
+	if old.Name != new.Name {
+		d.add(true, path, "element changed from %s to %s", old.Name, new.Name)
+		return
+	}
+	d.element(path+"."+old.Name, old, new, input)
+}
+
+// element compares two versions of an element. Requests are input: the
+// client sends them, so the new version must accept what the old allowed.
+// Responses are the other way round.
+func (d *differ) element(path string, old, new *Element, input bool) {
+	if old.Type != new.Type {
+		d.add(true, path, "type changed from %s to %s", describeType(old), describeType(new))
+	}
+
+	if old.MinOccurs != new.MinOccurs {
+		// A request element turning required, or a response element turning
+		// optional, leaves clients short.
+		tighter := new.MinOccurs > old.MinOccurs
+		d.add(tighter == input, path, "minOccurs changed from %d to %d", old.MinOccurs, new.MinOccurs)
+	}
+	if old.MaxOccurs != new.MaxOccurs {
+		d.add(maxOccursBreaks(old.MaxOccurs, new.MaxOccurs, input), path, "maxOccurs changed from %s to %s",
+			maxOccurs(old.MaxOccurs), maxOccurs(new.MaxOccurs))
+	}
+	if old.Nillable != new.Nillable {
+		// Clients may send nil only where it stays allowed, and expect it
+		// only where it was allowed.
+		d.add(old.Nillable == input, path, "nillable changed from %t to %t", old.Nillable, new.Nillable)
+	}
+
+	for _, value := range missing(old.Enumeration, new.Enumeration) {
+		d.add(input, path, "enumeration value %q removed", value)
+	}
+	for _, value := range missing(new.Enumeration, old.Enumeration) {
+		d.add(!input, path, "enumeration value %q added", value)
+	}
+	for _, derived := range missing(old.Derived, new.Derived) {
+		d.add(true, path, "derived type %s removed", derived)
+	}
+	for _, derived := range missing(new.Derived, old.Derived) {
+		d.add(!input, path, "derived type %s added", derived)
+	}
+
+	for _, oldAttr := range old.Attributes {
+		newAttr := findAttribute(new.Attributes, oldAttr.Name)
+		attrPath := path + "@" + oldAttr.Name
+		switch {
+		case newAttr == nil:
+			d.add(true, attrPath, "attribute removed")
+		case oldAttr.Type != newAttr.Type:
+			d.add(true, attrPath, "type changed from %s to %s", oldAttr.Type, newAttr.Type)
+		case oldAttr.Required != newAttr.Required:
+			d.add(newAttr.Required == input, attrPath, "required changed from %t to %t", oldAttr.Required, newAttr.Required)
+		}
+	}
+	for _, newAttr := range new.Attributes {
+		if findAttribute(old.Attributes, newAttr.Name) == nil {
+			d.add(input && newAttr.Required, path+"@"+newAttr.Name, "attribute added")
+		}
+	}
+
+	if old.Recursive || new.Recursive {
+		return
+	}
+	d.children(path, old, new, input)
+}
+
+// children compares the children of old and new. Children are matched by
+// name and occurrence, so that sequences repeating a name compare each
+// repetition, and the order is compared over the matched children only.
+func (d *differ) children(path string, old, new *Element, input bool) {
+	oldKeys, newKeys := occurrenceKeys(old.Children), occurrenceKeys(new.Children)
+	newByKey := make(map[string]*Element, len(newKeys))
+	for i, key := range newKeys {
+		newByKey[key] = new.Children[i]
+	}
+	inOld := make(map[string]bool, len(oldKeys))
+	for _, key := range oldKeys {
+		inOld[key] = true
+	}
+
+	var oldOrder, newOrder []string
+	for i, oldChild := range old.Children {
+		key := oldKeys[i]
+		childPath := path + "." + key
+		newChild := newByKey[key]
+		if newChild == nil {
+			// Servers reject request elements they do not know; responses
+			// stop carrying data clients read.
+			d.add(true, childPath, "element removed")
+			continue
+		}
+		oldOrder = append(oldOrder, key)
+		d.element(childPath, oldChild, newChild, input)
+	}
+	for i, newChild := range new.Children {
+		key := newKeys[i]
+		if !inOld[key] {
+			// Clients do not send new request elements, which is fine while
+			// they are optional. New response elements are ignored.
+			d.add(input && newChild.MinOccurs > 0, path+"."+key, "element added")
+			continue
+		}
+		newOrder = append(newOrder, key)
+	}
+
+	for i := range oldOrder {
+		if oldOrder[i] != newOrder[i] {
+			// Sequences are ordered; servers may reject requests in the old
+			// order, while decoding responses does not depend on it.
+			d.add(input, path, "order of elements changed")
+			break
+		}
+	}
+}
+
+// occurrenceKeys returns a key per element: its name, followed from the
+// second occurrence of the name on by the position of the occurrence, as in
+// "A[2]".
+func occurrenceKeys(elements []*Element) []string {
+	seen := make(map[string]int)
+	keys := make([]string, len(elements))
+	for i, e := range elements {
+		seen[e.Name]++
+		keys[i] = e.Name
+		if n := seen[e.Name]; n > 1 {
+			keys[i] = fmt.Sprintf("%s[%d]", e.Name, n)
+		}
+	}
+
+	return keys
+}
+
+// maxOccursBreaks reports whether changing maxOccurs breaks clients. Going
+// from one to many or back changes the generated field between a value and
+// a slice. Otherwise requests may not get fewer repetitions, and responses
+// may not get more.
+func maxOccursBreaks(old, new int, input bool) bool {
+	if (old == 1) != (new == 1) {
+		return true
+	}
+	if new == old {
+		return false
+	}
+	if input {
+		return new != -1 && (old == -1 || new < old)
+	}
+
+	return new == -1 || old != -1 && new > old
+}
+
+func maxOccurs(n int) string {
+	if n == -1 {
+		return "unbounded"
+	}
+
+	return fmt.Sprint(n)
+}
+
+func describeType(e *Element) string {
+	if e.Type == "" {
+		return "an anonymous type"
+	}
+
+	return e.Type
+}
+
+// missing returns the values of a not in b.
+func missing(a, b []string) []string {
+	in := make(map[string]bool, len(b))
+	for _, value := range b {
+		in[value] = true
+	}
+	var values []string
+	for _, value := range a {
+		if !in[value] {
+			values = append(values, value)
+		}
+	}
+
+	return values
+}
+
+func findService(services []*Service, name string) *Service {
+	for _, s := range services {
+		if s.Name == name {
+			return s
+		}
+	}
+
+	return nil
+}
+
+func findPort(ports []*Port, name string) *Port {
+	for _, p := range ports {
+		if p.Name == name {
+			return p
+		}
+	}
+
+	return nil
+}
+
+func findOperation(operations []*Operation, name string) *Operation {
+	for _, op := range operations {
+		if op.Name == name {
+			return op
+		}
+	}
+
+	return nil
+}
+
+func findAttribute(attributes []*Attribute, name string) *Attribute {
+	for _, a := range attributes {
+		if a.Name == name {
+			return a
+		}
+	}
+
+	return nil
+}
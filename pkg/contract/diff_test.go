@@ -0,0 +1,175 @@
+package contract
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/luhonghai/wsdl-example/pkg/wsdl"
+	"github.com/magiconair/properties/assert"
+)
+
+// describeEdited describes the calculator WSDL after applying the
+// replacements, given as old and new strings in turn.
+func describeEdited(t *testing.T, replacements ...string) *Description {
+	data, err := ioutil.ReadFile("../calculator.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defs, err := wsdl.Parse(strings.NewReader(strings.NewReplacer(replacements...).Replace(string(data))))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return Describe(defs)
+}
+
+func TestDiffUnchanged(t *testing.T) {
+	assert.Equal(t, len(Diff(describeEdited(t), describeEdited(t))), 0)
+}
+
+func TestDiffCalculator(t *testing.T) {
+	old := describeEdited(t)
+	new := describeEdited(t,
+		`<s:element minOccurs="1" maxOccurs="1" name="AddResult" type="s:int" />`,
+		`<s:element minOccurs="1" maxOccurs="1" name="AddResult" type="s:long" /><s:element minOccurs="0" maxOccurs="1" name="Overflow" type="s:boolean" />`,
+		`<soap:address location="http://www.dneonline.com/calculator.asmx" />`,
+		`<soap:address location="https://www.dneonline.com/calculator.asmx" />`,
+		`<soap:operation soapAction="http://tempuri.org/Divide" style="document" />`,
+		`<soap:operation soapAction="http://tempuri.org/Division" style="document" />`,
+	)
+
+	var got []string
+	for _, change := range Diff(old, new) {
+		got = append(got, change.String())
+	}
+	assert.Equal(t, got, []string{
+		"BREAKING   Calculator.CalculatorSoap: address changed from http://www.dneonline.com/calculator.asmx to https://www.dneonline.com/calculator.asmx",
+		"BREAKING   Calculator.CalculatorSoap.Add.output.AddResponse.AddResult: type changed from xsd:int to xsd:long",
+		"compatible Calculator.CalculatorSoap.Add.output.AddResponse.Overflow: element added",
+		"BREAKING   Calculator.CalculatorSoap.Divide: soapAction changed from \"http://tempuri.org/Divide\" to \"http://tempuri.org/Division\"",
+		"BREAKING   Calculator.CalculatorSoap12.Add.output.AddResponse.AddResult: type changed from xsd:int to xsd:long",
+		"compatible Calculator.CalculatorSoap12.Add.output.AddResponse.Overflow: element added",
+	})
+	assert.Equal(t, Breaking(Diff(old, new)), true)
+}
+
+func TestDiffOperations(t *testing.T) {
+	old := describeEdited(t)
+	new := &Description{
+		Name:            old.Name,
+		TargetNamespace: old.TargetNamespace,
+		Services: []*Service{{
+			Name: "Calculator",
+			Ports: []*Port{{
+				Name:        "CalculatorSoap",
+				Address:     old.Services[0].Ports[0].Address,
+				Binding:     "CalculatorSoap",
+				PortType:    "CalculatorSoap",
+				SOAPVersion: "1.1",
+				Operations:  append(old.Services[0].Ports[0].Operations[1:], &Operation{Name: "Modulo"}),
+			}},
+		}},
+	}
+
+	changes := Diff(old, new)
+	assert.Equal(t, len(changes), 3)
+	assert.Equal(t, *changes[0], Change{Path: "Calculator.CalculatorSoap.Add", Message: "operation removed", Breaking: true})
+	assert.Equal(t, *changes[1], Change{Path: "Calculator.CalculatorSoap.Modulo", Message: "operation added"})
+	assert.Equal(t, *changes[2], Change{Path: "Calculator.CalculatorSoap12", Message: "port removed", Breaking: true})
+}
+
+func TestDiffDirections(t *testing.T) {
+	element := func(min, max int, enumeration ...string) *Element {
+		return &Element{Name: "Value", Type: "Kind", MinOccurs: min, MaxOccurs: max, Enumeration: enumeration}
+	}
+	tests := []struct {
+		old, new *Element
+		input    bool
+		breaking bool
+	}{
+		{element(0, 1), element(1, 1), true, true},
+		{element(1, 1), element(0, 1), true, false},
+		{element(0, 1), element(1, 1), false, false},
+		{element(1, 1), element(0, 1), false, true},
+		{element(1, 1), element(1, wsdl.Unbounded), true, true},
+		{element(1, 10), element(1, wsdl.Unbounded), true, false},
+		{element(1, 10), element(1, wsdl.Unbounded), false, true},
+		{element(1, wsdl.Unbounded), element(1, 10), false, false},
+		{element(1, 1, "A", "B"), element(1, 1, "A"), true, true},
+		{element(1, 1, "A", "B"), element(1, 1, "A"), false, false},
+		{element(1, 1, "A"), element(1, 1, "A", "B"), true, false},
+		{element(1, 1, "A"), element(1, 1, "A", "B"), false, true},
+	}
+	for i, test := range tests {
+		d := &differ{}
+		d.element("Value", test.old, test.new, test.input)
+		assert.Equal(t, len(d.changes), 1)
+		assert.Equal(t, d.changes[0].Breaking, test.breaking, fmt.Sprint(i))
+	}
+}
+
+func TestDiffChildren(t *testing.T) {
+	old := &Element{Name: "Request", Children: []*Element{
+		{Name: "A", MinOccurs: 1, MaxOccurs: 1},
+		{Name: "B", MinOccurs: 1, MaxOccurs: 1},
+	}}
+	new := &Element{Name: "Request", Children: []*Element{
+		{Name: "B", MinOccurs: 1, MaxOccurs: 1},
+		{Name: "A", MinOccurs: 1, MaxOccurs: 1},
+		{Name: "C", MinOccurs: 0, MaxOccurs: 1},
+		{Name: "D", MinOccurs: 1, MaxOccurs: 1},
+	}}
+
+	d := &differ{}
+	d.element("Request", old, new, true)
+	assert.Equal(t, d.changes, []*Change{
+		{Path: "Request.C", Message: "element added"},
+		{Path: "Request.D", Message: "element added", Breaking: true},
+		{Path: "Request", Message: "order of elements changed", Breaking: true},
+	})
+
+	d = &differ{}
+	d.element("Request", new, old, false)
+	assert.Equal(t, d.changes, []*Change{
+		{Path: "Request.C", Message: "element removed", Breaking: true},
+		{Path: "Request.D", Message: "element removed", Breaking: true},
+		{Path: "Request", Message: "order of elements changed"},
+	})
+}
+
+func TestDiffRepeatedChildren(t *testing.T) {
+	old := &Element{Name: "Request", Children: []*Element{
+		{Name: "A", MinOccurs: 1, MaxOccurs: 1},
+		{Name: "B", MinOccurs: 1, MaxOccurs: 1},
+		{Name: "A", MinOccurs: 1, MaxOccurs: 1, Type: "int"},
+	}}
+	new := &Element{Name: "Request", Children: []*Element{
+		{Name: "A", MinOccurs: 1, MaxOccurs: 1},
+		{Name: "B", MinOccurs: 1, MaxOccurs: 1},
+	}}
+
+	d := &differ{}
+	d.element("Request", old, new, true)
+	assert.Equal(t, d.changes, []*Change{
+		{Path: "Request.A[2]", Message: "element removed", Breaking: true},
+	})
+
+	d = &differ{}
+	d.element("Request", new, old, true)
+	assert.Equal(t, d.changes, []*Change{
+		{Path: "Request.A[2]", Message: "element added", Breaking: true},
+	})
+
+	// The order is compared over the matched occurrences.
+	d = &differ{}
+	d.element("Request", old, &Element{Name: "Request", Children: []*Element{
+		{Name: "B", MinOccurs: 1, MaxOccurs: 1},
+		{Name: "A", MinOccurs: 1, MaxOccurs: 1},
+	}}, false)
+	assert.Equal(t, d.changes, []*Change{
+		{Path: "Request.A[2]", Message: "element removed", Breaking: true},
+		{Path: "Request", Message: "order of elements changed"},
+	})
+}
@@ -1,14 +1,16 @@
+// Code generated by wsdl-example generate; DO NOT EDIT.
+
+//go:generate go run github.com/luhonghai/wsdl-example generate --wsdl ../dilbert.xml --package dilbert --out . --import github.com/luhonghai/wsdl-example/pkg/dilbert
+
 package dilbert
 
 import (
-	"bytes"
-	"crypto/tls"
+	"context"
 	"encoding/xml"
-	"io/ioutil"
-	"log"
-	"net"
-	"net/http"
 	"time"
+
+	"github.com/luhonghai/wsdl-example/pkg/soap"
+	"github.com/luhonghai/wsdl-example/pkg/xsd"
 )
 
 // against "unused imports"
@@ -22,31 +24,47 @@ type TodaysDilbert struct {
 type TodaysDilbertResponse struct {
 	XMLName xml.Name `xml:"http://gcomputer.net/webservices/ TodaysDilbertResponse"`
 
-	TodaysDilbertResult string `xml:"TodaysDilbertResult,omitempty"`
+	TodaysDilbertResult *string `xml:"TodaysDilbertResult,omitempty"`
 }
 
 type DailyDilbert struct {
 	XMLName xml.Name `xml:"http://gcomputer.net/webservices/ DailyDilbert"`
 
-	ADate time.Time `xml:"ADate,omitempty"`
+	ADate xsd.DateTime `xml:"ADate"`
 }
 
 type DailyDilbertResponse struct {
 	XMLName xml.Name `xml:"http://gcomputer.net/webservices/ DailyDilbertResponse"`
 
-	DailyDilbertResult string `xml:"DailyDilbertResult,omitempty"`
+	DailyDilbertResult *string `xml:"DailyDilbertResult,omitempty"`
+}
+
+// DilbertSoapClient is the interface of the DilbertSoap port.
+//
+// DilbertSoap implements it, and so does the fake of the same name in
+// package dilbertfake, for tests.
+type DilbertSoapClient interface {
+	TodaysDilbert(request *TodaysDilbert) (*TodaysDilbertResponse, error)
+	DailyDilbert(request *DailyDilbert) (*DailyDilbertResponse, error)
 }
 
 type DilbertSoap struct {
-	client *SOAPClient
+	client *soap.Client
 }
 
-func NewDilbertSoap(url string, tls bool, auth *BasicAuth) *DilbertSoap {
+var _ DilbertSoapClient = (*DilbertSoap)(nil)
+
+func NewDilbertSoap(url string, tls bool, auth *soap.BasicAuth) *DilbertSoap {
 	if url == "" {
 		url = "http://www.gcomputer.net/webservices/dilbert.asmx"
 	}
-	client := NewSOAPClient(url, tls, auth)
+	client := soap.NewClient(url, tls, auth)
+
+	return NewDilbertSoapWithClient(client)
+}
 
+// NewDilbertSoapWithClient returns a DilbertSoap making its calls through client.
+func NewDilbertSoapWithClient(client *soap.Client) *DilbertSoap {
 	return &DilbertSoap{
 		client: client,
 	}
@@ -72,185 +90,34 @@ func (service *DilbertSoap) DailyDilbert(request *DailyDilbert) (*DailyDilbertRe
 	return response, nil
 }
 
-var timeout = time.Duration(30 * time.Second)
-
-func dialTimeout(network, addr string) (net.Conn, error) {
-	return net.DialTimeout(network, addr, timeout)
-}
-
-type SOAPEnvelope struct {
-	XMLName xml.Name `xml:"http://schemas.xmlsoap.org/soap/envelope/ Envelope"`
-
-	Body SOAPBody
-}
-
-type SOAPHeader struct {
-	XMLName xml.Name `xml:"http://schemas.xmlsoap.org/soap/envelope/ Header"`
-
-	Header interface{}
-}
-
-type SOAPBody struct {
-	XMLName xml.Name `xml:"http://schemas.xmlsoap.org/soap/envelope/ Body"`
-
-	Fault   *SOAPFault  `xml:",omitempty"`
-	Content interface{} `xml:",omitempty"`
-}
-
-type SOAPFault struct {
-	XMLName xml.Name `xml:"http://schemas.xmlsoap.org/soap/envelope/ Fault"`
-
-	Code   string `xml:"faultcode,omitempty"`
-	String string `xml:"faultstring,omitempty"`
-	Actor  string `xml:"faultactor,omitempty"`
-	Detail string `xml:"detail,omitempty"`
-}
-
-type BasicAuth struct {
-	Login    string
-	Password string
-}
-
-type SOAPClient struct {
-	url  string
-	tls  bool
-	auth *BasicAuth
-}
-
-func (b *SOAPBody) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
-	if b.Content == nil {
-		return xml.UnmarshalError("Content must be a pointer to a struct")
-	}
-
-	var (
-		token    xml.Token
-		err      error
-		consumed bool
-	)
-
-Loop:
-	for {
-		if token, err = d.Token(); err != nil {
-			return err
-		}
-
-		if token == nil {
-			break
-		}
-
-		switch se := token.(type) {
-		case xml.StartElement:
-			if consumed {
-				return xml.UnmarshalError("Found multiple elements inside SOAP body; not wrapped-document/literal WS-I compliant")
-			} else if se.Name.Space == "http://schemas.xmlsoap.org/soap/envelope/" && se.Name.Local == "Fault" {
-				b.Fault = &SOAPFault{}
-				b.Content = nil
-
-				err = d.DecodeElement(b.Fault, &se)
-				if err != nil {
-					return err
-				}
-
-				consumed = true
-			} else {
-				if err = d.DecodeElement(b.Content, &se); err != nil {
-					return err
-				}
-
-				consumed = true
-			}
-		case xml.EndElement:
-			break Loop
-		}
-	}
-
-	return nil
-}
-
-func (f *SOAPFault) Error() string {
-	return f.String
-}
-
-func NewSOAPClient(url string, tls bool, auth *BasicAuth) *SOAPClient {
-	return &SOAPClient{
-		url:  url,
-		tls:  tls,
-		auth: auth,
-	}
-}
-
-func (s *SOAPClient) Call(soapAction string, request, response interface{}) error {
-	envelope := SOAPEnvelope{
-		//Header:        SoapHeader{},
-	}
-
-	envelope.Body.Content = request
-	buffer := new(bytes.Buffer)
-
-	encoder := xml.NewEncoder(buffer)
-	//encoder.Indent("  ", "    ")
-
-	if err := encoder.Encode(envelope); err != nil {
-		return err
-	}
-
-	if err := encoder.Flush(); err != nil {
-		return err
-	}
-
-	log.Println(buffer.String())
-
-	req, err := http.NewRequest("POST", s.url, buffer)
-	if err != nil {
-		return err
-	}
-	if s.auth != nil {
-		req.SetBasicAuth(s.auth.Login, s.auth.Password)
-	}
-
-	req.Header.Add("Content-Type", "text/xml; charset=\"utf-8\"")
-	if soapAction != "" {
-		req.Header.Add("SOAPAction", soapAction)
-	}
-
-	req.Header.Set("User-Agent", "gowsdl/0.1")
-	req.Close = true
-
-	tr := &http.Transport{
-		TLSClientConfig: &tls.Config{
-			InsecureSkipVerify: s.tls,
+// DilbertSoapServer is implemented by services serving the DilbertSoap port.
+type DilbertSoapServer interface {
+	TodaysDilbert(ctx context.Context, request *TodaysDilbert) (*TodaysDilbertResponse, error)
+	DailyDilbert(ctx context.Context, request *DailyDilbert) (*DailyDilbertResponse, error)
+}
+
+// NewDilbertSoapHandler returns an http.Handler serving impl as the DilbertSoap
+// port. Errors impl returns are sent as faults, a *soap.Fault as it is. The
+// WSDL document wsdl, when not nil, is served at ?wsdl.
+func NewDilbertSoapHandler(impl DilbertSoapServer, wsdl []byte) *soap.Server {
+	return soap.NewServer(wsdl,
+		&soap.Operation{
+			Name:       "TodaysDilbert",
+			SOAPAction: "http://gcomputer.net/webservices/TodaysDilbert",
+			Element:    xml.Name{Space: "http://gcomputer.net/webservices/", Local: "TodaysDilbert"},
+			NewRequest: func() interface{} { return new(TodaysDilbert) },
+			Handle: func(ctx context.Context, request interface{}) (interface{}, error) {
+				return impl.TodaysDilbert(ctx, request.(*TodaysDilbert))
+			},
 		},
-		Dial: dialTimeout,
-	}
-
-	client := &http.Client{Transport: tr}
-	res, err := client.Do(req)
-	if err != nil {
-		return err
-	}
-	defer res.Body.Close()
-
-	rawbody, err := ioutil.ReadAll(res.Body)
-	if err != nil {
-		return err
-	}
-	if len(rawbody) == 0 {
-		log.Println("empty response")
-		return nil
-	}
-
-	log.Println(string(rawbody))
-	respEnvelope := new(SOAPEnvelope)
-	respEnvelope.Body = SOAPBody{Content: response}
-	err = xml.Unmarshal(rawbody, respEnvelope)
-	if err != nil {
-		return err
-	}
-
-	fault := respEnvelope.Body.Fault
-	if fault != nil {
-		return fault
-	}
-
-	return nil
+		&soap.Operation{
+			Name:       "DailyDilbert",
+			SOAPAction: "http://gcomputer.net/webservices/DailyDilbert",
+			Element:    xml.Name{Space: "http://gcomputer.net/webservices/", Local: "DailyDilbert"},
+			NewRequest: func() interface{} { return new(DailyDilbert) },
+			Handle: func(ctx context.Context, request interface{}) (interface{}, error) {
+				return impl.DailyDilbert(ctx, request.(*DailyDilbert))
+			},
+		},
+	)
 }
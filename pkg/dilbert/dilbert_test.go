@@ -1,17 +1,60 @@
 package dilbert
 
 import (
+	"errors"
+	"io/ioutil"
+	"net/http/httptest"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/luhonghai/wsdl-example/pkg/soap"
+	"github.com/luhonghai/wsdl-example/pkg/xsd"
 	"github.com/magiconair/properties/assert"
 )
 
+func newTestService(t *testing.T) *DilbertSoap {
+	dir := t.TempDir()
+	for day, strip := range map[string]string{
+		"2009-10-12": `<img src="/strips/2009-10-12.gif">`,
+		"2009-10-13": `<img src="/strips/2009-10-13.gif">`,
+	} {
+		if err := ioutil.WriteFile(filepath.Join(dir, day+".html"), []byte(strip), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	impl := &Server{
+		Provider: Dir(dir),
+		Now:      func() time.Time { return time.Date(2009, 10, 13, 8, 0, 0, 0, time.UTC) },
+	}
+	server := httptest.NewServer(NewDilbertSoapHandler(impl, nil))
+	t.Cleanup(server.Close)
+
+	return NewDilbertSoap(server.URL, false, nil)
+}
+
 func TestTodayDilbert(t *testing.T) {
-	soap := NewDilbertSoap("", false, nil)
+	soap := newTestService(t)
 	resp, err := soap.TodaysDilbert(nil)
 	if err != nil {
 		t.Error("Could not call soap method", err)
 	} else {
-		assert.Equal(t, resp.TodaysDilbertResult, "something?")
+		assert.Equal(t, xsd.StringValue(resp.TodaysDilbertResult), `<img src="/strips/2009-10-13.gif">`)
+	}
+}
+
+func TestDailyDilbert(t *testing.T) {
+	service := newTestService(t)
+	resp, err := service.DailyDilbert(&DailyDilbert{ADate: xsd.DateTime{Time: time.Date(2009, 10, 12, 0, 0, 0, 0, time.UTC)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, xsd.StringValue(resp.DailyDilbertResult), `<img src="/strips/2009-10-12.gif">`)
+
+	_, err = service.DailyDilbert(&DailyDilbert{ADate: xsd.DateTime{Time: time.Date(2009, 10, 14, 0, 0, 0, 0, time.UTC)}})
+	var fault *soap.Fault
+	if !errors.As(err, &fault) {
+		t.Fatalf("got %v, want a fault", err)
 	}
+	assert.Equal(t, fault.String, "no strip for 2009-10-14")
 }
@@ -0,0 +1,52 @@
+// Code generated by wsdl-example generate; DO NOT EDIT.
+
+// Package dilbertfake holds in-memory implementations of the ports of
+// package dilbert, for tests.
+package dilbertfake
+
+import (
+	"sync"
+
+	"github.com/luhonghai/wsdl-example/pkg/dilbert"
+)
+
+// DilbertSoap is an in-memory dilbert.DilbertSoapClient.
+//
+// It records the requests of every call and answers with the function set
+// for the operation, or with an empty response when there is none.
+type DilbertSoap struct {
+	mu sync.Mutex
+
+	TodaysDilbertFunc     func(request *dilbert.TodaysDilbert) (*dilbert.TodaysDilbertResponse, error)
+	TodaysDilbertRequests []*dilbert.TodaysDilbert
+	DailyDilbertFunc      func(request *dilbert.DailyDilbert) (*dilbert.DailyDilbertResponse, error)
+	DailyDilbertRequests  []*dilbert.DailyDilbert
+}
+
+var _ dilbert.DilbertSoapClient = (*DilbertSoap)(nil)
+
+func (fake *DilbertSoap) TodaysDilbert(request *dilbert.TodaysDilbert) (*dilbert.TodaysDilbertResponse, error) {
+	fake.mu.Lock()
+	fake.TodaysDilbertRequests = append(fake.TodaysDilbertRequests, request)
+	fn := fake.TodaysDilbertFunc
+	fake.mu.Unlock()
+
+	if fn != nil {
+		return fn(request)
+	}
+
+	return new(dilbert.TodaysDilbertResponse), nil
+}
+
+func (fake *DilbertSoap) DailyDilbert(request *dilbert.DailyDilbert) (*dilbert.DailyDilbertResponse, error) {
+	fake.mu.Lock()
+	fake.DailyDilbertRequests = append(fake.DailyDilbertRequests, request)
+	fn := fake.DailyDilbertFunc
+	fake.mu.Unlock()
+
+	if fn != nil {
+		return fn(request)
+	}
+
+	return new(dilbert.DailyDilbertResponse), nil
+}
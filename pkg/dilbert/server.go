@@ -0,0 +1,84 @@
+package dilbert
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/luhonghai/wsdl-example/pkg/soap"
+)
+
+// ErrNoStrip is returned by providers that have no strip for a day.
+var ErrNoStrip = errors.New("dilbert: no strip for that day")
+
+// Provider supplies the strip of a day, as the HTML snippet the service
+// returns.
+type Provider interface {
+	Strip(ctx context.Context, day time.Time) (string, error)
+}
+
+// Dir is a Provider reading the strip of a day from the file named after
+// it, as in 2006-01-02.html, in the directory Dir names.
+type Dir string
+
+func (d Dir) Strip(ctx context.Context, day time.Time) (string, error) {
+	data, err := ioutil.ReadFile(filepath.Join(string(d), day.Format("2006-01-02")+".html"))
+	if os.IsNotExist(err) {
+		return "", ErrNoStrip
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+// Server is a reference implementation of the DilbertSoap port, serving
+// the strips of Provider. Days without a strip are reported as client
+// faults.
+type Server struct {
+	Provider Provider
+	// Now returns the current time, that TodaysDilbert takes the day of.
+	// time.Now is used when it is nil.
+	Now func() time.Time
+}
+
+var _ DilbertSoapServer = (*Server)(nil)
+
+func (s *Server) TodaysDilbert(ctx context.Context, request *TodaysDilbert) (*TodaysDilbertResponse, error) {
+	now := time.Now
+	if s.Now != nil {
+		now = s.Now
+	}
+	strip, err := s.strip(ctx, now())
+	if err != nil {
+		return nil, err
+	}
+
+	return &TodaysDilbertResponse{TodaysDilbertResult: &strip}, nil
+}
+
+func (s *Server) DailyDilbert(ctx context.Context, request *DailyDilbert) (*DailyDilbertResponse, error) {
+	if request.ADate.IsZero() {
+		return nil, &soap.Fault{Code: "soap:Client", String: "ADate is required"}
+	}
+	strip, err := s.strip(ctx, request.ADate.Time)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DailyDilbertResponse{DailyDilbertResult: &strip}, nil
+}
+
+func (s *Server) strip(ctx context.Context, day time.Time) (string, error) {
+	strip, err := s.Provider.Strip(ctx, day)
+	if errors.Is(err, ErrNoStrip) {
+		return "", &soap.Fault{Code: "soap:Client", String: fmt.Sprintf("no strip for %s", day.Format("2006-01-02"))}
+	}
+
+	return strip, err
+}
@@ -0,0 +1,506 @@
+// Package dynamic calls SOAP operations described by a WSDL document without
+// generated code, building requests from the schema at runtime.
+package dynamic
+
+import (
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/luhonghai/wsdl-example/pkg/soap"
+	"github.com/luhonghai/wsdl-example/pkg/wsdl"
+	"github.com/luhonghai/wsdl-example/pkg/xsd"
+)
+
+// Client calls the operations of a WSDL document.
+type Client struct {
+	defs      *wsdl.Definitions
+	url       string
+	tls       bool
+	auth      *soap.BasicAuth
+	validator soap.Validator
+}
+
+// NewClient returns a client for the services of defs. An empty url selects
+// the address of the port serving each operation.
+func NewClient(defs *wsdl.Definitions, url string, tls bool, auth *soap.BasicAuth) *Client {
+	return &Client{
+		defs: defs,
+		url:  url,
+		tls:  tls,
+		auth: auth,
+	}
+}
+
+// SetValidator makes the client check requests and responses with v, as
+// soap.Client.SetValidator does.
+func (c *Client) SetValidator(v soap.Validator) {
+	c.validator = v
+}
+
+// Operation is an operation of a WSDL document along with the parts of the
+// document needed to call it.
+type Operation struct {
+	Name          string
+	Documentation string
+	PortType      *wsdl.PortType
+	Binding       *wsdl.Binding
+	SOAPAction    string
+	// Address is the endpoint of the port speaking the binding.
+	Address string
+
+	// Input and Output name the document/literal wrapper elements.
+	Input  wsdl.QName
+	Output wsdl.QName
+}
+
+// Operations returns the operations of the port types with a SOAP binding.
+func (c *Client) Operations() []*Operation {
+	var operations []*Operation
+	for _, portType := range c.defs.PortTypes {
+		for _, op := range portType.Operations {
+			if operation, err := c.operation(portType, op); err == nil {
+				operations = append(operations, operation)
+			}
+		}
+	}
+
+	return operations
+}
+
+// Operation returns the operation named name. Names may be qualified by
+// their port type as in "CalculatorSoap.Add" to tell them apart.
+func (c *Client) Operation(name string) (*Operation, error) {
+	portTypeName := ""
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		portTypeName, name = name[:i], name[i+1:]
+	}
+
+	var found []*Operation
+	for _, portType := range c.defs.PortTypes {
+		if portTypeName != "" && portType.Name != portTypeName {
+			continue
+		}
+		op := portType.Operation(name)
+		if op == nil {
+			continue
+		}
+		operation, err := c.operation(portType, op)
+		if err != nil {
+			return nil, err
+		}
+		found = append(found, operation)
+	}
+
+	switch len(found) {
+	case 0:
+		return nil, fmt.Errorf("dynamic: no operation %s", name)
+	case 1:
+		return found[0], nil
+	}
+
+	return nil, fmt.Errorf("dynamic: operation %s is offered by several port types; qualify it as %s.%s", name, found[0].PortType.Name, name)
+}
+
+func (c *Client) operation(portType *wsdl.PortType, op *wsdl.Operation) (*Operation, error) {
+	binding := c.defs.SOAPBinding(portType)
+	if binding == nil {
+		return nil, fmt.Errorf("dynamic: port type %s has no SOAP binding", portType.Name)
+	}
+	if op.Input == nil || op.Output == nil {
+		return nil, fmt.Errorf("dynamic: operation %s is not request-response", op.Name)
+	}
+
+	operation := &Operation{
+		Name:          op.Name,
+		Documentation: op.Documentation,
+		PortType:      portType,
+		Binding:       binding,
+		Address:       c.defs.Address(binding),
+	}
+	if bop := binding.Operation(op.Name); bop != nil {
+		operation.SOAPAction = bop.SOAPAction
+	}
+
+	var err error
+	if operation.Input, err = c.wrapper(op.Input.Message); err != nil {
+		return nil, err
+	}
+	if operation.Output, err = c.wrapper(op.Output.Message); err != nil {
+		return nil, err
+	}
+
+	return operation, nil
+}
+
+// wrapper returns the element carried by a document/literal message.
+func (c *Client) wrapper(name wsdl.QName) (wsdl.QName, error) {
+	message := c.defs.Message(name)
+	if message == nil {
+		return wsdl.QName{}, fmt.Errorf("dynamic: unknown message %s", name)
+	}
+	if len(message.Parts) != 1 || message.Parts[0].Element.IsZero() {
+		return wsdl.QName{}, fmt.Errorf("dynamic: message %s is not document/literal wrapped", message.Name)
+	}
+	element := message.Parts[0].Element
+	if c.defs.Element(element) == nil {
+		return wsdl.QName{}, fmt.Errorf("dynamic: message %s refers to unknown element %s", message.Name, element)
+	}
+
+	return element, nil
+}
+
+// Call invokes operation and returns the body of the response. See Request
+// for the arguments.
+func (c *Client) Call(operation string, args map[string][]string) (*Node, error) {
+	op, err := c.Operation(operation)
+	if err != nil {
+		return nil, err
+	}
+	request, err := c.request(op, args)
+	if err != nil {
+		return nil, err
+	}
+
+	url := c.url
+	if url == "" {
+		url = op.Address
+	}
+	client := soap.NewClient(url, c.tls, c.auth)
+	client.SetValidator(c.validator)
+	response := new(Node)
+	if err := client.Call(op.SOAPAction, request, response); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// Request builds the request document of operation. Arguments map the paths
+// of parameters to their values. A path names an element of the request,
+// dotted for nested ones as in "AccessControlList.Grant.Permission"; several
+// values fill a repeated element. The occurrences of a repeated complex
+// element are told apart by their position from 1, as in
+// "AccessControlList.Grant[2].Permission". The parameter "@type" below an
+// element, as in "AccessControlList.Grant.Grantee.@type", names the type
+// deriving from its declared one it holds, which abstract types require.
+// Every required parameter must be given.
+func (c *Client) Request(operation string, args map[string][]string) (*Node, error) {
+	op, err := c.Operation(operation)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.request(op, args)
+}
+
+func (c *Client) request(op *Operation, args map[string][]string) (*Node, error) {
+	b := &builder{defs: c.defs, args: args, used: make(map[string]bool), building: make(map[*wsdl.ComplexType]bool)}
+	node, err := b.build(c.defs.Element(op.Input), op.Input.Space, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var unknown []string
+	for path := range args {
+		if !b.used[path] {
+			unknown = append(unknown, path)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("dynamic: %s takes no parameter %s; it takes %s",
+			op.Name, strings.Join(unknown, ", "), strings.Join(c.parameters(op), ", "))
+	}
+	if len(b.missing) > 0 {
+		return nil, fmt.Errorf("dynamic: %s requires the parameters %s", op.Name, strings.Join(b.missing, ", "))
+	}
+
+	return node, nil
+}
+
+// Parameters returns the paths of the parameters operation takes.
+func (c *Client) Parameters(operation string) ([]string, error) {
+	op, err := c.Operation(operation)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.parameters(op), nil
+}
+
+func (c *Client) parameters(op *Operation) []string {
+	var paths []string
+	walkParameters(c.defs, c.defs.Element(op.Input), "", make(map[*wsdl.ComplexType]bool), func(path string, e *wsdl.Element) {
+		paths = append(paths, path)
+	})
+
+	return paths
+}
+
+// walkParameters calls fn for the path of every simple value below e.
+// Recursive types are followed once.
+func walkParameters(defs *wsdl.Definitions, e *wsdl.Element, prefix string, seen map[*wsdl.ComplexType]bool, fn func(string, *wsdl.Element)) {
+	ct := complexType(defs, e)
+	if ct == nil || ct.SimpleContent {
+		fn(strings.TrimPrefix(prefix, "."), e)
+		return
+	}
+	if ct.Abstract {
+		fn(strings.TrimPrefix(prefix, ".")+".@type", e)
+	}
+	if seen[ct] {
+		return
+	}
+	seen[ct] = true
+	defer delete(seen, ct)
+
+	for _, child := range content(defs, ct) {
+		child = resolve(defs, child)
+		walkParameters(defs, child, prefix+"."+child.Name, seen, fn)
+	}
+}
+
+type builder struct {
+	defs *wsdl.Definitions
+	args map[string][]string
+	used map[string]bool
+	// missing holds the paths of required parameters without a value.
+	missing []string
+	// building holds the complex types being built, so that required
+	// elements of recursive types end.
+	building map[*wsdl.ComplexType]bool
+}
+
+// build returns the element e filled from the arguments below path, named
+// in namespace ns. Required elements are built even without arguments, and
+// the required parameters below them are recorded as missing.
+func (b *builder) build(e *wsdl.Element, ns, path string) (*Node, error) {
+	node := &Node{Name: xml.Name{Space: ns, Local: e.Name}}
+	ct := complexType(b.defs, e)
+	if ct == nil || ct.SimpleContent {
+		return node, nil
+	}
+	if path != "" {
+		var err error
+		if ct, err = b.instanceType(ct, path, node); err != nil {
+			return nil, err
+		}
+	}
+	b.building[ct] = true
+	defer delete(b.building, ct)
+
+	childNS := ""
+	if schema := b.defs.Schema(ns); schema != nil && schema.ElementFormDefault == "qualified" {
+		childNS = ns
+	}
+	for _, child := range content(b.defs, ct) {
+		child = resolve(b.defs, child)
+		childPath := child.Name
+		if path != "" {
+			childPath = path + "." + child.Name
+		}
+
+		childCT := complexType(b.defs, child)
+		if childCT != nil && !childCT.SimpleContent {
+			paths := []string{childPath}
+			if child.MaxOccurs != 1 {
+				occurrences, err := b.occurrences(childPath, child.MaxOccurs)
+				if err != nil {
+					return nil, err
+				}
+				if occurrences != nil {
+					paths = occurrences
+				}
+			}
+			for _, p := range paths {
+				if !b.hasArgs(p) && (child.MinOccurs == 0 || b.building[childCT]) {
+					continue
+				}
+				n, err := b.build(child, childNS, p)
+				if err != nil {
+					return nil, err
+				}
+				node.Children = append(node.Children, n)
+			}
+			continue
+		}
+
+		values, ok := b.args[childPath]
+		if len(values) < child.MinOccurs {
+			b.missing = append(b.missing, childPath)
+		}
+		if !ok {
+			continue
+		}
+		b.used[childPath] = true
+		if len(values) > 1 && child.MaxOccurs != wsdl.Unbounded && len(values) > child.MaxOccurs {
+			return nil, fmt.Errorf("dynamic: %s takes at most %d values", childPath, child.MaxOccurs)
+		}
+		for _, value := range values {
+			if err := checkValue(b.defs, child, value); err != nil {
+				return nil, fmt.Errorf("dynamic: %s: %v", childPath, err)
+			}
+			node.Children = append(node.Children, &Node{Name: xml.Name{Space: childNS, Local: child.Name}, Text: value})
+		}
+	}
+
+	return node, nil
+}
+
+// occurrences returns the paths of the occurrences of the repeated element
+// at path the arguments give, as in "Grant[1]" and "Grant[2]", or nil if
+// they do not tell occurrences apart.
+func (b *builder) occurrences(path string, maxOccurs int) ([]string, error) {
+	given := make(map[int]bool)
+	count := 0
+	for p := range b.args {
+		rest := strings.TrimPrefix(p, path+"[")
+		end := strings.Index(rest, "].")
+		if rest == p || end < 0 {
+			continue
+		}
+		position, err := strconv.Atoi(rest[:end])
+		if err != nil || position < 1 {
+			return nil, fmt.Errorf("dynamic: %s: %q is not a position", p, rest[:end])
+		}
+		given[position] = true
+		if position > count {
+			count = position
+		}
+	}
+	if count == 0 {
+		return nil, nil
+	}
+	if b.hasArgs(path) {
+		return nil, fmt.Errorf("dynamic: %s is given both with and without positions", path)
+	}
+	if maxOccurs != wsdl.Unbounded && count > maxOccurs {
+		return nil, fmt.Errorf("dynamic: %s takes at most %d values", path, maxOccurs)
+	}
+
+	paths := make([]string, count)
+	for i := range paths {
+		if !given[i+1] {
+			return nil, fmt.Errorf("dynamic: %s[%d] is not given", path, i+1)
+		}
+		paths[i] = fmt.Sprintf("%s[%d]", path, i+1)
+	}
+
+	return paths, nil
+}
+
+// instanceType returns the type the element at path of type ct is built as:
+// the type named by the parameter path.@type, which is then written as the
+// xsi:type of node, or ct itself.
+func (b *builder) instanceType(ct *wsdl.ComplexType, path string, node *Node) (*wsdl.ComplexType, error) {
+	typePath := path + ".@type"
+	values, ok := b.args[typePath]
+	if !ok {
+		if ct.Abstract {
+			b.missing = append(b.missing, typePath)
+		}
+		return ct, nil
+	}
+	b.used[typePath] = true
+	if len(values) != 1 {
+		return nil, fmt.Errorf("dynamic: %s takes at most 1 values", typePath)
+	}
+	derived, schema := derivedType(b.defs, ct, values[0])
+	switch {
+	case derived == nil:
+		return nil, fmt.Errorf("dynamic: %s: %s does not derive from %s", typePath, values[0], ct.Name)
+	case derived.Abstract:
+		return nil, fmt.Errorf("dynamic: %s: %s is abstract", typePath, values[0])
+	}
+	node.Attrs = append(node.Attrs,
+		xml.Attr{Name: xml.Name{Local: "xmlns:xsi"}, Value: xsd.InstanceNamespace},
+		xml.Attr{Name: xml.Name{Local: "xmlns:t"}, Value: schema.TargetNamespace},
+		xml.Attr{Name: xml.Name{Local: "xsi:type"}, Value: "t:" + derived.Name})
+
+	return derived, nil
+}
+
+func (b *builder) hasArgs(path string) bool {
+	for p := range b.args {
+		if strings.HasPrefix(p, path+".") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// complexType returns the complex type of e, or nil for simple content.
+func complexType(defs *wsdl.Definitions, e *wsdl.Element) *wsdl.ComplexType {
+	if e.ComplexType != nil {
+		return e.ComplexType
+	}
+
+	return defs.ComplexType(e.Type)
+}
+
+// content returns the elements of ct, those inherited from its base first.
+func content(defs *wsdl.Definitions, ct *wsdl.ComplexType) []*wsdl.Element {
+	var elements []*wsdl.Element
+	if !ct.SimpleContent && !ct.Base.IsZero() {
+		if base := defs.ComplexType(ct.Base); base != nil {
+			elements = append(elements, content(defs, base)...)
+		}
+	}
+
+	return append(elements, ct.Elements...)
+}
+
+// derivedType returns the complex type named local that is base or derives
+// from it, along with the schema declaring it, or nil if there is none.
+func derivedType(defs *wsdl.Definitions, base *wsdl.ComplexType, local string) (*wsdl.ComplexType, *wsdl.Schema) {
+	for _, schema := range defs.Schemas {
+		for _, ct := range schema.ComplexTypes {
+			if ct.Name != local {
+				continue
+			}
+			for t, depth := ct, 0; t != nil && depth < 16; t, depth = defs.ComplexType(t.Base), depth+1 {
+				if t == base {
+					return ct, schema
+				}
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+// resolve returns the declaration e refers to, keeping its occurrences.
+func resolve(defs *wsdl.Definitions, e *wsdl.Element) *wsdl.Element {
+	if e.Ref.IsZero() {
+		return e
+	}
+	ref := defs.Element(e.Ref)
+	if ref == nil {
+		return e
+	}
+	resolved := *ref
+	resolved.MinOccurs, resolved.MaxOccurs = e.MinOccurs, e.MaxOccurs
+
+	return &resolved
+}
+
+// checkValue reports whether value is a valid lexical form of the built-in
+// type of e. Values of other types are passed on unchecked.
+func checkValue(defs *wsdl.Definitions, e *wsdl.Element, value string) error {
+	name := e.Type
+	if e.SimpleType != nil {
+		name = e.SimpleType.Base
+	}
+	if st := defs.SimpleType(name); st != nil {
+		name = st.Base
+	}
+	if !wsdl.IsBuiltin(name) {
+		return nil
+	}
+
+	return xsd.CheckValue(name.Local, value)
+}
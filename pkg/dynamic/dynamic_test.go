@@ -0,0 +1,244 @@
+package dynamic
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/luhonghai/wsdl-example/pkg/calculator"
+	"github.com/luhonghai/wsdl-example/pkg/soap"
+	"github.com/luhonghai/wsdl-example/pkg/wsdl"
+	"github.com/magiconair/properties/assert"
+)
+
+func loadCalculator(t *testing.T) *wsdl.Definitions {
+	defs, err := wsdl.ParseFile("../calculator.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return defs
+}
+
+// calculatorServer answers Add requests decoded with the generated types, so
+// the dynamic requests must match what generated clients send.
+func calculatorServer(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		if r.Header.Get("SOAPAction") != "http://tempuri.org/Add" {
+			t.Errorf("unexpected SOAPAction %q", r.Header.Get("SOAPAction"))
+		}
+
+		request := new(calculator.Add)
+		envelope := soap.Envelope{Body: soap.Body{Content: request}}
+		if err := xml.Unmarshal(body, &envelope); err != nil {
+			t.Errorf("decoding %s: %v", body, err)
+		}
+
+		reply := soap.Envelope{}
+		reply.Body.Content = &calculator.AddResponse{AddResult: request.IntA + request.IntB}
+		xml.NewEncoder(w).Encode(reply)
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestCall(t *testing.T) {
+	server := calculatorServer(t)
+	client := NewClient(loadCalculator(t), server.URL, false, nil)
+
+	response, err := client.Call("Add", map[string][]string{"intA": {"1"}, "intB": {"2"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, response.Name, xml.Name{Space: "http://tempuri.org/", Local: "AddResponse"})
+	assert.Equal(t, response.Child("AddResult").Text, "3")
+
+	data, err := json.Marshal(response)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, string(data), `{"AddResult":"3"}`)
+}
+
+func TestRequestChecksArguments(t *testing.T) {
+	client := NewClient(loadCalculator(t), "", false, nil)
+
+	params, err := client.Parameters("Divide")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, params, []string{"intA", "intB"})
+
+	_, err = client.Request("Add", map[string][]string{"intA": {"1"}, "intC": {"2"}})
+	assert.Equal(t, err.Error(), "dynamic: Add takes no parameter intC; it takes intA, intB")
+
+	_, err = client.Request("Add", map[string][]string{"intA": {"one"}})
+	assert.Equal(t, err.Error(), `dynamic: intA: "one" is not a valid int`)
+
+	_, err = client.Request("Add", map[string][]string{"intA": {"1", "2"}})
+	assert.Equal(t, err.Error(), "dynamic: intA takes at most 1 values")
+
+	_, err = client.Request("Add", map[string][]string{"intA": {"1"}})
+	assert.Equal(t, err.Error(), "dynamic: Add requires the parameters intB")
+
+	_, err = client.Request("Add", nil)
+	assert.Equal(t, err.Error(), "dynamic: Add requires the parameters intA, intB")
+
+	_, err = client.Request("Modulo", nil)
+	assert.Equal(t, err.Error(), "dynamic: no operation Modulo")
+
+	op, err := client.Operation("CalculatorSoap.Add")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, op.Binding.SOAPVersion, "1.1")
+	assert.Equal(t, op.Address, "http://www.dneonline.com/calculator.asmx")
+}
+
+func TestRequestNested(t *testing.T) {
+	catalog, err := wsdl.ReadCatalog("../schemas/catalog.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defs, err := (&wsdl.Loader{Catalog: catalog}).Load("../AmazonS3.wsdl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := NewClient(defs, "", false, nil)
+
+	request, err := client.Request("CreateBucket", map[string][]string{
+		"Bucket": {"photos"},
+		"CreateBucketConfiguration.LocationConstraint": {"EU"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := xml.Marshal(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ns := `xmlns="http://s3.amazonaws.com/doc/2006-03-01/"`
+	want := `<CreateBucket ` + ns + `><Bucket ` + ns + `>photos</Bucket>` +
+		`<CreateBucketConfiguration ` + ns + `><LocationConstraint ` + ns + `>EU</LocationConstraint></CreateBucketConfiguration></CreateBucket>`
+	assert.Equal(t, string(data), want)
+
+	request, err = client.Request("CreateBucket", map[string][]string{
+		"Bucket": {"photos"},
+		"AccessControlList.Grant[1].Grantee.@type": {"CanonicalUser"},
+		"AccessControlList.Grant[1].Grantee.ID":    {"a9a7b886"},
+		"AccessControlList.Grant[1].Permission":    {"FULL_CONTROL"},
+		"AccessControlList.Grant[2].Grantee.@type": {"Group"},
+		"AccessControlList.Grant[2].Grantee.URI":   {"http://acs.amazonaws.com/groups/global/AllUsers"},
+		"AccessControlList.Grant[2].Permission":    {"READ"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	grants := request.Child("AccessControlList").Children
+	assert.Equal(t, len(grants), 2)
+	assert.Equal(t, grants[1].Child("Permission").Text, "READ")
+	data, err = xml.Marshal(grants[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, string(data), `<Grant `+ns+`><Grantee `+ns+` xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" `+
+		`xmlns:t="http://s3.amazonaws.com/doc/2006-03-01/" xsi:type="t:CanonicalUser"><ID `+ns+`>a9a7b886</ID></Grantee>`+
+		`<Permission `+ns+`>FULL_CONTROL</Permission></Grant>`)
+
+	_, err = client.Request("CreateBucket", map[string][]string{
+		"Bucket":                                {"photos"},
+		"AccessControlList.Grant[1].Permission": {"READ"},
+	})
+	assert.Equal(t, err.Error(), "dynamic: CreateBucket requires the parameters AccessControlList.Grant[1].Grantee.@type")
+
+	_, err = client.Request("CreateBucket", map[string][]string{
+		"Bucket":                                {"photos"},
+		"AccessControlList.Grant.Grantee.@type": {"User"},
+		"AccessControlList.Grant.Permission":    {"READ"},
+	})
+	assert.Equal(t, err.Error(), "dynamic: AccessControlList.Grant.Grantee.@type: User is abstract")
+
+	_, err = client.Request("CreateBucket", map[string][]string{
+		"Bucket":                                {"photos"},
+		"AccessControlList.Grant.Grantee.@type": {"Owner"},
+		"AccessControlList.Grant.Permission":    {"READ"},
+	})
+	assert.Equal(t, err.Error(), "dynamic: AccessControlList.Grant.Grantee.@type: Owner does not derive from Grantee")
+
+	_, err = client.Request("CreateBucket", map[string][]string{
+		"Bucket":                                {"photos"},
+		"AccessControlList.Grant.Permission":    {"READ"},
+		"AccessControlList.Grant[1].Permission": {"WRITE"},
+	})
+	assert.Equal(t, err.Error(), "dynamic: AccessControlList.Grant is given both with and without positions")
+
+	_, err = client.Request("CreateBucket", map[string][]string{
+		"Bucket":                                {"photos"},
+		"AccessControlList.Grant[2].Permission": {"READ"},
+	})
+	assert.Equal(t, err.Error(), "dynamic: AccessControlList.Grant[1] is not given")
+
+	_, err = client.Request("CreateBucket", map[string][]string{
+		"Bucket": {"photos"},
+		"AccessControlList.Grant[101].Permission": {"READ"},
+	})
+	assert.Equal(t, err.Error(), "dynamic: AccessControlList.Grant takes at most 100 values")
+}
+
+func TestNodeValue(t *testing.T) {
+	node := new(Node)
+	err := xml.Unmarshal([]byte(`<List xmlns="urn:x" kind="all">
+  <Item>a</Item>
+  <Item>b</Item>
+  <Owner><ID>1</ID></Owner>
+  <Expires xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:nil="true"/>
+</List>`), node)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(node)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, string(data), `{"@kind":"all","Expires":null,"Item":["a","b"],"Owner":{"ID":"1"}}`)
+	assert.Equal(t, strings.TrimSpace(node.Child("Item").Text), "a")
+}
+
+func TestResponseValue(t *testing.T) {
+	catalog, err := wsdl.ReadCatalog("../schemas/catalog.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defs, err := (&wsdl.Loader{Catalog: catalog}).Load("../AmazonS3.wsdl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := NewClient(defs, "", false, nil)
+
+	node := new(Node)
+	err = xml.Unmarshal([]byte(`<ListBucketResponse xmlns="http://s3.amazonaws.com/doc/2006-03-01/"><ListBucketResponse>
+  <Name>photos</Name>
+  <MaxKeys>0100</MaxKeys>
+  <IsTruncated>1</IsTruncated>
+  <Contents><Key>a</Key><Size>12</Size><StorageClass>STANDARD</StorageClass><Color>red</Color></Contents>
+</ListBucketResponse></ListBucketResponse>`), node)
+	if err != nil {
+		t.Fatal(err)
+	}
+	value, err := client.ResponseValue("ListBucket", node)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, string(data), `{"ListBucketResponse":{"Contents":[{"Color":"red","Key":"a","Size":12,"StorageClass":"STANDARD"}],`+
+		`"IsTruncated":true,"MaxKeys":100,"Name":"photos"}}`)
+}
@@ -0,0 +1,123 @@
+package dynamic
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+
+	"github.com/luhonghai/wsdl-example/pkg/xsd"
+)
+
+// Node is an XML element held as a generic tree, for documents there are no
+// Go types for.
+type Node struct {
+	Name     xml.Name
+	Attrs    []xml.Attr
+	Text     string
+	Children []*Node
+}
+
+// Child returns the first child of n with the local name local, or nil.
+func (n *Node) Child(local string) *Node {
+	for _, child := range n.Children {
+		if child.Name.Local == local {
+			return child
+		}
+	}
+
+	return nil
+}
+
+func (n *Node) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start = xml.StartElement{Name: n.Name, Attr: n.Attrs}
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+	if n.Text != "" {
+		if err := e.EncodeToken(xml.CharData(n.Text)); err != nil {
+			return err
+		}
+	}
+	for _, child := range n.Children {
+		if err := e.Encode(child); err != nil {
+			return err
+		}
+	}
+
+	return e.EncodeToken(start.End())
+}
+
+func (n *Node) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	n.Name = start.Name
+	for _, attr := range start.Attr {
+		if attr.Name.Space == "xmlns" || attr.Name.Space == "" && attr.Name.Local == "xmlns" {
+			continue
+		}
+		n.Attrs = append(n.Attrs, attr)
+	}
+
+	var text strings.Builder
+	for {
+		token, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			child := new(Node)
+			if err := d.DecodeElement(child, &t); err != nil {
+				return err
+			}
+			n.Children = append(n.Children, child)
+		case xml.CharData:
+			text.Write(t)
+		case xml.EndElement:
+			n.Text = text.String()
+			if len(n.Children) > 0 {
+				// Text between child elements is indentation.
+				n.Text = strings.TrimSpace(n.Text)
+			}
+			return nil
+		}
+	}
+}
+
+// Value returns the content of n as plain Go values: the text of elements
+// holding nothing else, a map keyed by local name for the others. Repeated
+// children are gathered in a slice, attributes are keyed "@name" and text
+// mixed with elements "#text". Elements marked xsi:nil are nil.
+func (n *Node) Value() interface{} {
+	if xsd.IsNil(xml.StartElement{Name: n.Name, Attr: n.Attrs}) {
+		return nil
+	}
+	if len(n.Children) == 0 && len(n.Attrs) == 0 {
+		return n.Text
+	}
+
+	m := make(map[string]interface{})
+	for _, attr := range n.Attrs {
+		m["@"+attr.Name.Local] = attr.Value
+	}
+	for _, child := range n.Children {
+		name := child.Name.Local
+		value := child.Value()
+		switch existing := m[name].(type) {
+		case nil:
+			m[name] = value
+		case []interface{}:
+			m[name] = append(existing, value)
+		default:
+			m[name] = []interface{}{existing, value}
+		}
+	}
+	if n.Text != "" {
+		m["#text"] = n.Text
+	}
+
+	return m
+}
+
+// MarshalJSON writes the Value of n.
+func (n *Node) MarshalJSON() ([]byte, error) {
+	return json.Marshal(n.Value())
+}
@@ -0,0 +1,116 @@
+package dynamic
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/luhonghai/wsdl-example/pkg/wsdl"
+	"github.com/luhonghai/wsdl-example/pkg/xsd"
+)
+
+// ResponseValue returns the response of operation as plain Go values, like
+// Node.Value does, but typed after the schema: numbers are json.Numbers,
+// booleans are bools, and elements that may repeat are always in a slice.
+// Elements the schema does not declare are left as Node.Value has them.
+func (c *Client) ResponseValue(operation string, response *Node) (interface{}, error) {
+	op, err := c.Operation(operation)
+	if err != nil {
+		return nil, err
+	}
+
+	return typedValue(c.defs, c.defs.Element(op.Output), response), nil
+}
+
+func typedValue(defs *wsdl.Definitions, e *wsdl.Element, n *Node) interface{} {
+	if xsd.IsNil(xml.StartElement{Name: n.Name, Attr: n.Attrs}) {
+		return nil
+	}
+	ct := complexType(defs, e)
+	if ct == nil {
+		name := e.Type
+		if e.SimpleType != nil {
+			name = e.SimpleType.Base
+		}
+		return scalar(defs, name, n.Text)
+	}
+	if ct.SimpleContent && len(n.Attrs) == 0 {
+		return scalar(defs, ct.Base, n.Text)
+	}
+
+	m := make(map[string]interface{})
+	for _, attr := range n.Attrs {
+		m["@"+attr.Name.Local] = attr.Value
+	}
+	if ct.SimpleContent {
+		m["#text"] = scalar(defs, ct.Base, n.Text)
+		return m
+	}
+
+	declared := make(map[string]*wsdl.Element)
+	for _, child := range content(defs, ct) {
+		child = resolve(defs, child)
+		declared[child.Name] = child
+	}
+	for _, child := range n.Children {
+		name := child.Name.Local
+		decl := declared[name]
+		if decl == nil {
+			m[name] = child.Value()
+			continue
+		}
+		value := typedValue(defs, decl, child)
+		if decl.MaxOccurs == wsdl.Unbounded || decl.MaxOccurs > 1 {
+			values, _ := m[name].([]interface{})
+			m[name] = append(values, value)
+		} else {
+			m[name] = value
+		}
+	}
+
+	return m
+}
+
+// scalar returns text as a value of the built-in type the simple type named
+// name derives from. Text that is not a valid lexical form stays a string,
+// as do the special float values JSON has no numbers for.
+func scalar(defs *wsdl.Definitions, name wsdl.QName, text string) interface{} {
+	for i := 0; i < 16 && !wsdl.IsBuiltin(name); i++ {
+		st := defs.SimpleType(name)
+		if st == nil {
+			return text
+		}
+		name = st.Base
+	}
+	if !wsdl.IsBuiltin(name) {
+		return text
+	}
+
+	value := strings.TrimSpace(text)
+	switch name.Local {
+	case "boolean":
+		switch value {
+		case "true", "1":
+			return true
+		case "false", "0":
+			return false
+		}
+	case "byte", "short", "int", "long", "integer",
+		"nonNegativeInteger", "positiveInteger", "nonPositiveInteger", "negativeInteger":
+		if i, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return json.Number(strconv.FormatInt(i, 10))
+		}
+	case "unsignedByte", "unsignedShort", "unsignedInt", "unsignedLong":
+		if u, err := strconv.ParseUint(value, 10, 64); err == nil {
+			return json.Number(strconv.FormatUint(u, 10))
+		}
+	case "float", "double", "decimal":
+		if f, err := strconv.ParseFloat(value, 64); err == nil && !math.IsInf(f, 0) && !math.IsNaN(f) {
+			return json.Number(strconv.FormatFloat(f, 'g', -1, 64))
+		}
+	}
+
+	return text
+}
@@ -0,0 +1,186 @@
+// Package gateway exposes the operations of SOAP services as JSON over
+// HTTP, for clients that do not speak SOAP. A request such as
+//
+//	POST /calculator/Add {"intA": 1, "intB": 2}
+//
+// is sent on as the SOAP call it stands for, and the response is answered
+// as JSON typed after the schema.
+package gateway
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/luhonghai/wsdl-example/pkg/dynamic"
+	"github.com/luhonghai/wsdl-example/pkg/soap"
+)
+
+// ErrorResponse is the JSON body of error responses. Faults of the service
+// keep their code, actor and detail.
+type ErrorResponse struct {
+	Error      string `json:"error"`
+	FaultCode  string `json:"faultcode,omitempty"`
+	FaultActor string `json:"faultactor,omitempty"`
+	Detail     string `json:"detail,omitempty"`
+}
+
+// Gateway is an http.Handler serving the operations of each service under
+// a path of its own.
+type Gateway struct {
+	services map[string]*dynamic.Client
+}
+
+// New returns a gateway exposing no service yet.
+func New() *Gateway {
+	return &Gateway{services: make(map[string]*dynamic.Client)}
+}
+
+// Add exposes the operations of client at /name/<operation>. Operations
+// offered by several port types are told apart as /name/<PortType.Operation>.
+func (g *Gateway) Add(name string, client *dynamic.Client) {
+	g.services[name] = client
+}
+
+// ServeHTTP answers POST requests. The JSON object in the body holds the
+// parameters of the operation: nested objects fill nested elements, arrays
+// repeated ones and "@type" names the derived type an element holds. Client
+// faults are answered with status 400, other faults and failures of the
+// service with 502, and timeouts with 504.
+func (g *Gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) != 2 || g.services[parts[0]] == nil {
+		writeJSON(w, http.StatusNotFound, &ErrorResponse{Error: fmt.Sprintf("no operation at %s", r.URL.Path)})
+		return
+	}
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", "POST")
+		writeJSON(w, http.StatusMethodNotAllowed, &ErrorResponse{Error: "operations are called with POST"})
+		return
+	}
+	client, operation := g.services[parts[0]], parts[1]
+	if _, err := client.Operation(operation); err != nil {
+		writeJSON(w, http.StatusNotFound, &ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	args, err := decodeArgs(r.Body)
+	if err == nil {
+		_, err = client.Request(operation, args)
+	}
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, &ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	response, err := client.Call(operation, args)
+	if err != nil {
+		writeCallError(w, err)
+		return
+	}
+	value, err := client.ResponseValue(operation, response)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, &ErrorResponse{Error: err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, value)
+}
+
+// decodeArgs returns the parameters held by a JSON object, keyed by their
+// dotted paths as dynamic.Client takes them.
+func decodeArgs(r io.Reader) (map[string][]string, error) {
+	d := json.NewDecoder(r)
+	d.UseNumber()
+	var body interface{}
+	if err := d.Decode(&body); err == io.EOF {
+		body = map[string]interface{}{}
+	} else if err != nil {
+		return nil, fmt.Errorf("the body is not JSON: %v", err)
+	}
+	object, ok := body.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("the body is not a JSON object")
+	}
+
+	args := make(map[string][]string)
+	for name, value := range object {
+		if err := flatten(name, value, args); err != nil {
+			return nil, err
+		}
+	}
+
+	return args, nil
+}
+
+// flatten adds the parameters held by value at path to args. The objects
+// in an array are told apart by their position, as in "Grant[2]".
+func flatten(path string, value interface{}, args map[string][]string) error {
+	switch v := value.(type) {
+	case nil:
+	case map[string]interface{}:
+		for name, child := range v {
+			if err := flatten(path+"."+name, child, args); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for i, item := range v {
+			itemPath := path
+			switch item.(type) {
+			case []interface{}:
+				return fmt.Errorf("%s: arrays may not hold arrays", path)
+			case map[string]interface{}:
+				itemPath = fmt.Sprintf("%s[%d]", path, i+1)
+			}
+			if err := flatten(itemPath, item, args); err != nil {
+				return err
+			}
+		}
+	case string:
+		args[path] = append(args[path], v)
+	default:
+		args[path] = append(args[path], fmt.Sprint(v))
+	}
+
+	return nil
+}
+
+// writeCallError answers with a failed call to the service.
+func writeCallError(w http.ResponseWriter, err error) {
+	var fault *soap.Fault
+	if errors.As(err, &fault) {
+		status := http.StatusBadGateway
+		if code := fault.Code[strings.Index(fault.Code, ":")+1:]; code == "Client" || strings.HasPrefix(code, "Client.") {
+			status = http.StatusBadRequest
+		}
+		writeJSON(w, status, &ErrorResponse{
+			Error:      fault.String,
+			FaultCode:  fault.Code,
+			FaultActor: fault.Actor,
+			Detail:     fault.Detail,
+		})
+		return
+	}
+
+	status := http.StatusBadGateway
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		status = http.StatusGatewayTimeout
+	}
+	writeJSON(w, status, &ErrorResponse{Error: err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		status = http.StatusInternalServerError
+		data, _ = json.Marshal(&ErrorResponse{Error: err.Error()})
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(append(data, '\n'))
+}
@@ -0,0 +1,223 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/luhonghai/wsdl-example/pkg/calculator"
+	"github.com/luhonghai/wsdl-example/pkg/dynamic"
+	"github.com/luhonghai/wsdl-example/pkg/mock"
+	"github.com/luhonghai/wsdl-example/pkg/openapi"
+	"github.com/luhonghai/wsdl-example/pkg/soap"
+	"github.com/luhonghai/wsdl-example/pkg/wsdl"
+	"github.com/magiconair/properties/assert"
+)
+
+func newTestGateway(t *testing.T, handler http.Handler) *httptest.Server {
+	defs, err := wsdl.ParseFile("../calculator.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	service := httptest.NewServer(handler)
+	t.Cleanup(service.Close)
+
+	g := New()
+	g.Add("calculator", dynamic.NewClient(defs, service.URL, false, nil))
+	server := httptest.NewServer(g)
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func post(t *testing.T, url, body string) (int, string) {
+	res, err := http.Post(url, "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	data, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return res.StatusCode, strings.TrimSpace(string(data))
+}
+
+func TestGateway(t *testing.T) {
+	server := newTestGateway(t, calculator.NewCalculatorSoapHandler(calculator.Server{}, nil))
+
+	status, body := post(t, server.URL+"/calculator/Add", `{"intA": 1, "intB": 2}`)
+	assert.Equal(t, status, http.StatusOK)
+	assert.Equal(t, body, `{"AddResult":3}`)
+
+	status, body = post(t, server.URL+"/calculator/CalculatorSoap.Divide", `{"intA": 1, "intB": 0}`)
+	assert.Equal(t, status, http.StatusBadGateway)
+	assert.Equal(t, body, `{"error":"Attempted to divide by zero.","faultcode":"soap:Server"}`)
+
+	status, body = post(t, server.URL+"/calculator/Add", `{"intA": "one", "intB": 2}`)
+	assert.Equal(t, status, http.StatusBadRequest)
+	assert.Equal(t, body, `{"error":"dynamic: intA: \"one\" is not a valid int"}`)
+
+	status, body = post(t, server.URL+"/calculator/Add", `[1, 2]`)
+	assert.Equal(t, status, http.StatusBadRequest)
+	assert.Equal(t, body, `{"error":"the body is not a JSON object"}`)
+
+	status, _ = post(t, server.URL+"/calculator/Modulo", `{}`)
+	assert.Equal(t, status, http.StatusNotFound)
+	status, _ = post(t, server.URL+"/dilbert/TodaysDilbert", `{}`)
+	assert.Equal(t, status, http.StatusNotFound)
+
+	res, err := http.Get(server.URL + "/calculator/Add")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	assert.Equal(t, res.StatusCode, http.StatusMethodNotAllowed)
+}
+
+// strictCalculator rejects negative operands of Add as client faults.
+type strictCalculator struct {
+	calculator.Server
+}
+
+func (c strictCalculator) Add(ctx context.Context, request *calculator.Add) (*calculator.AddResponse, error) {
+	if request.IntA < 0 || request.IntB < 0 {
+		return nil, &soap.Fault{Code: "soap:Client", String: "negative operand"}
+	}
+
+	return c.Server.Add(ctx, request)
+}
+
+func TestGatewayClientFaults(t *testing.T) {
+	server := newTestGateway(t, calculator.NewCalculatorSoapHandler(strictCalculator{}, nil))
+
+	status, body := post(t, server.URL+"/calculator/Add", `{"intA": -1, "intB": 2}`)
+	assert.Equal(t, status, http.StatusBadRequest)
+	assert.Equal(t, body, `{"error":"negative operand","faultcode":"soap:Client"}`)
+}
+
+func TestGatewayServiceFailures(t *testing.T) {
+	server := newTestGateway(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "down for maintenance", http.StatusServiceUnavailable)
+	}))
+
+	status, body := post(t, server.URL+"/calculator/Add", `{"intA": 1, "intB": 2}`)
+	assert.Equal(t, status, http.StatusBadGateway)
+	assert.Equal(t, body, `{"error":"down for maintenance"}`)
+}
+
+// example returns a JSON value following schema, taking the first choice
+// of every anyOf and two items for every array.
+func example(doc *openapi.Document, schema *openapi.Schema) interface{} {
+	if schema.Ref != "" {
+		return example(doc, doc.Components.Schemas[strings.TrimPrefix(schema.Ref, "#/components/schemas/")])
+	}
+	if len(schema.AnyOf) > 0 {
+		return example(doc, schema.AnyOf[0])
+	}
+	if len(schema.Enum) > 0 {
+		return schema.Enum[0]
+	}
+	switch schema.Type {
+	case "object":
+		object := make(map[string]interface{})
+		for name, property := range schema.Properties {
+			object[name] = example(doc, property)
+		}
+		return object
+	case "array":
+		return []interface{}{example(doc, schema.Items), example(doc, schema.Items)}
+	case "integer":
+		return 1
+	case "number":
+		return 1.5
+	case "boolean":
+		return true
+	}
+	switch schema.Format {
+	case "date-time":
+		return "2009-10-12T17:50:30Z"
+	case "byte":
+		return "aGVsbG8="
+	}
+
+	return "x"
+}
+
+// TestGatewaySpecBodies posts a body built from the OpenAPI document of S3,
+// with arrays of objects and a grantee of a derived type, to a mock
+// checking the requests against the schema.
+func TestGatewaySpecBodies(t *testing.T) {
+	catalog, err := wsdl.ReadCatalog("../schemas/catalog.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defs, err := (&wsdl.Loader{Catalog: catalog}).Load("../AmazonS3.wsdl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := openapi.Generate(defs, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler, err := mock.NewServer(defs, nil, []*mock.Rule{{
+		Operation: "PutObject",
+		Match:     []mock.Predicate{{Path: "Metadata[2]/Name"}, {Path: "AccessControlList/Grant[2]/Grantee/EmailAddress"}},
+		Response: `<PutObjectResponse xmlns="http://s3.amazonaws.com/doc/2006-03-01/"><PutObjectResponse>` +
+			`<ETag>"1"</ETag><LastModified>2009-10-12T17:50:30.000Z</LastModified></PutObjectResponse></PutObjectResponse>`,
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	service := httptest.NewServer(handler)
+	t.Cleanup(service.Close)
+	g := New()
+	g.Add("s3", dynamic.NewClient(defs, service.URL, false, nil))
+	server := httptest.NewServer(g)
+	t.Cleanup(server.Close)
+
+	body, err := json.Marshal(example(doc, doc.Paths["/PutObject"].Post.RequestBody.Content["application/json"].Schema))
+	if err != nil {
+		t.Fatal(err)
+	}
+	status, response := post(t, server.URL+"/s3/PutObject", string(body))
+	assert.Equal(t, status, http.StatusOK, response)
+	assert.Equal(t, response, `{"PutObjectResponse":{"ETag":"\"1\"","LastModified":"2009-10-12T17:50:30.000Z"}}`)
+}
+
+func TestDecodeArgs(t *testing.T) {
+	args, err := decodeArgs(strings.NewReader(`{"Bucket": "photos", "MaxKeys": 10, "Delimiter": null,
+		"AccessControlList": {"Grant": {"Permission": ["READ", "WRITE"]}}, "Versioned": true}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, args, map[string][]string{
+		"Bucket":                             {"photos"},
+		"MaxKeys":                            {"10"},
+		"AccessControlList.Grant.Permission": {"READ", "WRITE"},
+		"Versioned":                          {"true"},
+	})
+
+	args, err = decodeArgs(strings.NewReader(`{"Grant": [{"Permission": "READ"}, {"Permission": "WRITE"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, args, map[string][]string{
+		"Grant[1].Permission": {"READ"},
+		"Grant[2].Permission": {"WRITE"},
+	})
+
+	_, err = decodeArgs(strings.NewReader(`{"Grant": [["READ"]]}`))
+	assert.Equal(t, err.Error(), "Grant: arrays may not hold arrays")
+
+	args, err = decodeArgs(strings.NewReader(``))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, len(args), 0)
+}
@@ -0,0 +1,980 @@
+// Package generator turns a WSDL document into a Go package holding the
+// schema types and a client for every port type.
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/luhonghai/wsdl-example/pkg/wsdl"
+)
+
+// Import paths of the runtime packages generated code depends on.
+const (
+	SOAPImport = "github.com/luhonghai/wsdl-example/pkg/soap"
+	XSDImport  = "github.com/luhonghai/wsdl-example/pkg/xsd"
+)
+
+// xsiType is the tag of the field carrying the xsi:type of a polymorphic
+// value.
+const xsiType = "http://www.w3.org/2001/XMLSchema-instance type,attr,omitempty"
+
+// Options control code generation.
+type Options struct {
+	// Package is the name of the generated package.
+	Package string
+	// GoGenerate, when set, is written out as a go:generate directive so the
+	// package can be refreshed with go generate.
+	GoGenerate string
+	// ImportPath is the import path of the generated package, which its
+	// fake package imports.
+	ImportPath string
+}
+
+// FakePackage returns the name of the package holding the fakes of the
+// ports of package pkg.
+func FakePackage(pkg string) string {
+	return pkg + "fake"
+}
+
+// Generate returns the gofmt'ed source of a package implementing defs.
+func Generate(defs *wsdl.Definitions, options *Options) ([]byte, error) {
+	g := newGenerator(defs)
+	data, err := g.file(options)
+	if err != nil {
+		return nil, err
+	}
+
+	var buffer bytes.Buffer
+	if err := fileTemplate.Execute(&buffer, data); err != nil {
+		return nil, err
+	}
+	source, err := format.Source(buffer.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("generator: formatting output: %v\n%s", err, buffer.Bytes())
+	}
+	if err := checkDeclarations(source); err != nil {
+		return nil, err
+	}
+
+	return source, nil
+}
+
+// GenerateFake returns the gofmt'ed source of a package holding an
+// in-memory implementation of every port of defs for tests, or nil when
+// defs has no port. Options.ImportPath must be set.
+func GenerateFake(defs *wsdl.Definitions, options *Options) ([]byte, error) {
+	if options.ImportPath == "" {
+		return nil, fmt.Errorf("generator: the fakes need the import path of package %s", options.Package)
+	}
+	g := newGenerator(defs)
+	f, err := g.file(options)
+	if err != nil || len(f.Services) == 0 {
+		return nil, err
+	}
+
+	var buffer bytes.Buffer
+	err = fakeTemplate.Execute(&buffer, &fakeFile{
+		Package:  FakePackage(options.Package),
+		Main:     options.Package,
+		Import:   options.ImportPath,
+		Services: f.Services,
+	})
+	if err != nil {
+		return nil, err
+	}
+	source, err := format.Source(buffer.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("generator: formatting output: %v\n%s", err, buffer.Bytes())
+	}
+	if err := checkDeclarations(source); err != nil {
+		return nil, err
+	}
+
+	return source, nil
+}
+
+// checkDeclarations returns an error if source declares a name twice at the
+// top level, in a struct or among the methods of a type, as schemas whose
+// names differ only in characters Go names leave out make it.
+func checkDeclarations(source []byte) error {
+	f, err := parser.ParseFile(token.NewFileSet(), "", source, 0)
+	if err != nil {
+		return err
+	}
+
+	declared := make(map[string]bool)
+	declare := func(name string) error {
+		if name == "_" || name == "init" {
+			return nil
+		}
+		if declared[name] {
+			return fmt.Errorf("generator: the schema names yield %s twice", name)
+		}
+		declared[name] = true
+		return nil
+	}
+	for _, decl := range f.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			name := d.Name.Name
+			if d.Recv != nil {
+				// Methods share the namespace of the fields of their type.
+				recv := d.Recv.List[0].Type
+				if star, ok := recv.(*ast.StarExpr); ok {
+					recv = star.X
+				}
+				name = fmt.Sprintf("%s.%s", recv, name)
+			}
+			if err := declare(name); err != nil {
+				return err
+			}
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				switch spec := spec.(type) {
+				case *ast.TypeSpec:
+					if err := declare(spec.Name.Name); err != nil {
+						return err
+					}
+					if st, ok := spec.Type.(*ast.StructType); ok {
+						for _, field := range st.Fields.List {
+							for _, n := range field.Names {
+								if err := declare(spec.Name.Name + "." + n.Name); err != nil {
+									return err
+								}
+							}
+						}
+					}
+				case *ast.ValueSpec:
+					for _, n := range spec.Names {
+						if err := declare(n.Name); err != nil {
+							return err
+						}
+					}
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+type fakeFile struct {
+	// Package is the name of the fake package, and Main the name of the
+	// package it fakes the ports of.
+	Package  string
+	Main     string
+	Import   string
+	Services []*service
+}
+
+type generator struct {
+	defs *wsdl.Definitions
+
+	// derived maps a complex type to the types extending it.
+	derived map[wsdl.QName][]wsdl.QName
+	// names holds the Go type names taken so far.
+	names map[string]bool
+
+	imports map[string]bool
+	structs []*structType
+
+	// nillables holds the wrappers of nillable elements by name, declared
+	// in nillableOrder.
+	nillables     map[string]*nillableType
+	nillableOrder []*nillableType
+
+	// arrays holds the Go names of SOAP encoded array types, declared in
+	// arrayOrder.
+	arrays     map[string]bool
+	arrayOrder []*arrayType
+	// messages maps the messages of rpc style operations to their structs.
+	messages map[string]string
+}
+
+func newGenerator(defs *wsdl.Definitions) *generator {
+	g := &generator{
+		defs:      defs,
+		derived:   make(map[wsdl.QName][]wsdl.QName),
+		names:     make(map[string]bool),
+		imports:   make(map[string]bool),
+		nillables: make(map[string]*nillableType),
+		arrays:    make(map[string]bool),
+		messages:  make(map[string]string),
+	}
+	for _, schema := range defs.Schemas {
+		for _, ct := range schema.ComplexTypes {
+			name := wsdl.QName{Space: schema.TargetNamespace, Local: ct.Name}
+			if !ct.ArrayType.IsZero() {
+				g.arrays[goName(ct.Name)] = true
+			} else if !ct.Base.IsZero() && !ct.SimpleContent {
+				g.derived[ct.Base] = append(g.derived[ct.Base], name)
+			}
+		}
+	}
+
+	return g
+}
+
+type file struct {
+	Package    string
+	GoGenerate string
+	Imports    []string
+	Simple     []*simpleType
+	Structs    []*structType
+	Nillable   []*nillableType
+	Arrays     []*arrayType
+	Services   []*service
+}
+
+type simpleType struct {
+	Name   string
+	Base   string
+	Values []*enumValue
+	// Enum is set for string enumerations, which validate their values.
+	Enum bool
+}
+
+type enumValue struct {
+	Name  string
+	Value string
+	// Literal is the Go constant of Value.
+	Literal string
+}
+
+type structType struct {
+	Name string
+	// Head holds the fields written before the element content, separated
+	// from it by a blank line: the XMLName of elements or the xsi:type of
+	// polymorphic types.
+	Head   []*field
+	Fields []*field
+}
+
+// nillableType wraps the values of nillable elements, adding whether the
+// element is nil.
+type nillableType struct {
+	Name string
+	Type string
+	// Complex is set for values of complex types, held by pointer; a nil
+	// pointer is sent as a nil element too.
+	Complex bool
+}
+
+// arrayType is a SOAP encoded array, a slice reading and writing the
+// soapenc:Array form.
+type arrayType struct {
+	Name string
+	Item string
+	// ItemType is the schema type of the items, named in soapenc:arrayType.
+	ItemType wsdl.QName
+}
+
+type field struct {
+	Name string
+	Type string
+	Tag  string
+}
+
+type service struct {
+	Name string
+	// Interface names the interface of the port, implemented by the client
+	// and by the fake of the same name as the port.
+	Interface string
+	// Server names the interface of implementations of the port, served by
+	// the generated handler.
+	Server     string
+	URL        string
+	Operations []*method
+}
+
+type method struct {
+	Service       string
+	Name          string
+	Documentation string
+	SOAPAction    string
+	Request       string
+	Response      string
+	// Element names the body element of requests, which servers dispatch
+	// on.
+	Element wsdl.QName
+
+	// Operation is the name of the operation in the WSDL document.
+	Operation string
+	// RPC is set for rpc style operations, whose wrappers are named after
+	// Operation in Namespace. Encoded is set for use="encoded", whose
+	// accessors of built-in types are sent with their xsi:type.
+	RPC           bool
+	Namespace     string
+	Encoded       bool
+	RequestTypes  []*partType
+	ResponseTypes []*partType
+}
+
+// partType names the built-in schema type of an accessor.
+type partType struct {
+	Accessor string
+	Type     string
+}
+
+func (g *generator) file(options *Options) (*file, error) {
+	f := &file{Package: options.Package, GoGenerate: options.GoGenerate}
+
+	for _, schema := range g.defs.Schemas {
+		if len(schema.Unresolved) > 0 {
+			return nil, fmt.Errorf("generator: schema %s pulls in %s, which is not loaded", schema.TargetNamespace, schema.Unresolved[0])
+		}
+	}
+
+	for _, schema := range g.defs.Schemas {
+		for _, st := range schema.SimpleTypes {
+			t, err := g.simpleType(st)
+			if err != nil {
+				return nil, err
+			}
+			f.Simple = append(f.Simple, t)
+		}
+	}
+	for _, schema := range g.defs.Schemas {
+		for _, e := range schema.Elements {
+			if err := g.elementStruct(schema.TargetNamespace, e); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, schema := range g.defs.Schemas {
+		for _, ct := range schema.ComplexTypes {
+			if err := g.complexStruct(wsdl.QName{Space: schema.TargetNamespace, Local: ct.Name}, ct); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, portType := range g.defs.PortTypes {
+		s, err := g.service(portType)
+		if err != nil {
+			return nil, err
+		}
+		f.Services = append(f.Services, s)
+	}
+	f.Structs = g.structs
+	f.Nillable = g.nillableOrder
+	f.Arrays = g.arrayOrder
+
+	f.Imports = []string{"encoding/xml", "time"}
+	if len(f.Services) > 0 {
+		f.Imports = []string{"context", "encoding/xml", "time"}
+	}
+	if len(f.Services) > 0 || len(f.Arrays) > 0 {
+		g.imports[SOAPImport] = true
+	}
+	var extra []string
+	for path := range g.imports {
+		extra = append(extra, path)
+	}
+	sort.Strings(extra)
+	if len(extra) > 0 {
+		f.Imports = append(f.Imports, "")
+		f.Imports = append(f.Imports, extra...)
+	}
+
+	return f, nil
+}
+
+func (g *generator) simpleType(st *wsdl.SimpleType) (*simpleType, error) {
+	name := goName(st.Name)
+	g.names[name] = true
+
+	t := &simpleType{Name: name, Base: "string"}
+	if base, ok := builtinType(st.Base); ok {
+		t.Base = base
+	}
+	// Enumerations of types without constants of their own, such as dates,
+	// are enumerations of their lexical forms.
+	if len(st.Enumeration) > 0 && literal(t.Base, "") == "" {
+		t.Base = "string"
+	}
+	if strings.HasPrefix(t.Base, "xsd.") {
+		g.imports[XSDImport] = true
+	}
+	for _, value := range st.Enumeration {
+		lit := literal(t.Base, value)
+		if lit == "" {
+			return nil, fmt.Errorf("generator: simple type %s: %q is not a valid %s", st.Name, value, st.Base.Local)
+		}
+		// Values that differ only in punctuation get numbered constants.
+		constName := goName(value)
+		if constName == "" {
+			constName = "Empty"
+		}
+		t.Values = append(t.Values, &enumValue{Name: g.freeName(name + constName), Value: value, Literal: lit})
+	}
+	if len(t.Values) > 0 && t.Base == "string" {
+		t.Enum = true
+		g.imports[XSDImport] = true
+	}
+
+	return t, nil
+}
+
+// literal returns the Go constant for the enumeration value of a simple
+// type based on goType, or an empty string if value is not a valid lexical
+// form of goType or goType has no constants. Called with an empty value, it
+// tells whether goType has constants at all.
+func literal(goType, value string) string {
+	value = strings.TrimSpace(value)
+	switch goType {
+	case "string":
+		return strconv.Quote(value)
+	case "bool":
+		switch value {
+		case "", "true", "1":
+			return "true"
+		case "false", "0":
+			return "false"
+		}
+	case "int8", "int16", "int32", "int64":
+		if value == "" {
+			return "0"
+		}
+		if i, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return strconv.FormatInt(i, 10)
+		}
+	case "uint8", "uint16", "uint32", "uint64":
+		if value == "" {
+			return "0"
+		}
+		if u, err := strconv.ParseUint(value, 10, 64); err == nil {
+			return strconv.FormatUint(u, 10)
+		}
+	case "float32", "float64":
+		if value == "" {
+			return "0"
+		}
+		// Go has no constants for INF and NaN.
+		if f, err := strconv.ParseFloat(value, 64); err == nil && !math.IsInf(f, 0) && !math.IsNaN(f) {
+			return strconv.FormatFloat(f, 'g', -1, 64)
+		}
+	}
+
+	return ""
+}
+
+// elementStruct declares the struct of a top-level element, which carries
+// the element name along with the content of its type.
+func (g *generator) elementStruct(ns string, e *wsdl.Element) error {
+	name := goName(e.Name)
+	s := &structType{
+		Name: name,
+		Head: []*field{{Name: "XMLName", Type: "xml.Name", Tag: ns + " " + e.Name}},
+	}
+
+	switch {
+	case e.ComplexType != nil:
+		fields, err := g.fields(name, e.ComplexType, false)
+		if err != nil {
+			return err
+		}
+		s.Fields = fields
+	case g.defs.ComplexType(e.Type) != nil:
+		if e.Type.Local == e.Name {
+			// The type struct serves for the element as well.
+			return nil
+		}
+		fields, err := g.fields(name, g.defs.ComplexType(e.Type), false)
+		if err != nil {
+			return err
+		}
+		s.Fields = fields
+	default:
+		goType, err := g.valueType(e.Type, e.SimpleType)
+		if err != nil {
+			return fmt.Errorf("generator: element %s: %v", e.Name, err)
+		}
+		s.Fields = []*field{{Name: "Value", Type: goType, Tag: ",chardata"}}
+	}
+
+	g.names[name] = true
+	g.structs = append(g.structs, s)
+
+	return nil
+}
+
+func (g *generator) complexStruct(qname wsdl.QName, ct *wsdl.ComplexType) error {
+	name := goName(ct.Name)
+	g.names[name] = true
+
+	if !ct.ArrayType.IsZero() {
+		item, err := g.elementField(name, &wsdl.Element{Name: "item", Type: ct.ArrayType, MinOccurs: 1, MaxOccurs: 1})
+		if err != nil {
+			return err
+		}
+		g.arrayOrder = append(g.arrayOrder, &arrayType{Name: name, Item: item.Type, ItemType: ct.ArrayType})
+		return nil
+	}
+
+	s := &structType{Name: name}
+	fields, err := g.fields(name, ct, false)
+	if err != nil {
+		return err
+	}
+	s.Fields = fields
+
+	// Values of an abstract type are sent as one of the types deriving from
+	// it, named by xsi:type. The struct carries the fields of all of them.
+	if ct.Abstract && len(g.derived[qname]) > 0 {
+		s.Head = []*field{{Name: "Type", Type: "string", Tag: xsiType}}
+		seen := make(map[string]bool)
+		for _, f := range s.Fields {
+			seen[f.Name] = true
+		}
+		for _, d := range g.allDerived(qname) {
+			fields, err := g.fields(name, g.defs.ComplexType(d), true)
+			if err != nil {
+				return err
+			}
+			for _, f := range fields {
+				if !seen[f.Name] {
+					seen[f.Name] = true
+					s.Fields = append(s.Fields, f)
+				}
+			}
+		}
+	}
+
+	g.structs = append(g.structs, s)
+
+	return nil
+}
+
+// allDerived returns the types deriving from base, directly or not.
+func (g *generator) allDerived(base wsdl.QName) []wsdl.QName {
+	var all []wsdl.QName
+	for _, d := range g.derived[base] {
+		all = append(all, d)
+		all = append(all, g.allDerived(d)...)
+	}
+
+	return all
+}
+
+// fields returns the fields of a struct holding the content of ct, the
+// content inherited from its base type first. When optional is set every
+// element is, as for the members of a polymorphic struct, which carries the
+// content of one derived type at a time.
+func (g *generator) fields(owner string, ct *wsdl.ComplexType, optional bool) ([]*field, error) {
+	var fields []*field
+	if ct.SimpleContent {
+		goType, err := g.valueType(ct.Base, nil)
+		if err != nil {
+			return nil, fmt.Errorf("generator: type %s: %v", owner, err)
+		}
+		fields = append(fields, &field{Name: "Value", Type: goType, Tag: ",chardata"})
+	} else if !ct.Base.IsZero() {
+		base := g.defs.ComplexType(ct.Base)
+		if base == nil {
+			return nil, fmt.Errorf("generator: type %s extends unknown type %s", owner, ct.Base)
+		}
+		inherited, err := g.fields(owner, base, optional)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, inherited...)
+	}
+
+	for _, e := range ct.Elements {
+		if optional && e.MinOccurs > 0 {
+			relaxed := *e
+			relaxed.MinOccurs = 0
+			e = &relaxed
+		}
+		f, err := g.elementField(owner, e)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, f)
+	}
+	for _, a := range ct.Attributes {
+		name := a.Name
+		if name == "" {
+			name = a.Ref.Local
+		}
+		goType := "string"
+		if !a.Type.IsZero() {
+			t, err := g.valueType(a.Type, nil)
+			if err != nil {
+				return nil, fmt.Errorf("generator: attribute %s of %s: %v", name, owner, err)
+			}
+			goType = t
+		}
+		tag := name + ",attr"
+		if a.Use != "required" {
+			tag += ",omitempty"
+			if isDate(goType) {
+				goType = "*" + goType
+			}
+		}
+		fields = append(fields, &field{Name: goName(name), Type: goType, Tag: tag})
+	}
+
+	return fields, nil
+}
+
+func (g *generator) elementField(owner string, e *wsdl.Element) (*field, error) {
+	if !e.Ref.IsZero() {
+		ref := g.defs.Element(e.Ref)
+		if ref == nil {
+			return nil, fmt.Errorf("generator: %s refers to unknown element %s", owner, e.Ref)
+		}
+		resolved := *ref
+		resolved.MinOccurs, resolved.MaxOccurs = e.MinOccurs, e.MaxOccurs
+		e = &resolved
+		if e.ComplexType != nil {
+			return g.field(e, goName(e.Name), true), nil
+		}
+	}
+
+	if e.ComplexType != nil {
+		// Anonymous types of local elements get a struct of their own.
+		name := goName(e.Name)
+		if g.names[name] {
+			name = owner + name
+		}
+		g.names[name] = true
+		fields, err := g.fields(name, e.ComplexType, false)
+		if err != nil {
+			return nil, err
+		}
+		g.structs = append(g.structs, &structType{Name: name, Fields: fields})
+		return g.field(e, name, true), nil
+	}
+
+	if ct := g.defs.ComplexType(e.Type); ct != nil {
+		// Arrays are slices, nil when absent.
+		return g.field(e, goName(e.Type.Local), ct.ArrayType.IsZero()), nil
+	}
+	if g.defs.SimpleType(e.Type) != nil {
+		return g.field(e, goName(e.Type.Local), false), nil
+	}
+
+	goType, err := g.valueType(e.Type, e.SimpleType)
+	if err != nil {
+		return nil, fmt.Errorf("generator: element %s of %s: %v", e.Name, owner, err)
+	}
+
+	return g.field(e, goType, false), nil
+}
+
+// field declares the field for element e holding values of goType. Values
+// of complex types are referenced by pointer, nil when absent. Other values
+// of required elements are held directly and always written, those of
+// optional elements by pointer so zero values can still be sent. Types
+// leaving out their zero value need no pointer. Nillable elements hold their
+// value in a wrapper telling xsi:nil apart.
+func (g *generator) field(e *wsdl.Element, goType string, complex bool) *field {
+	repeated := e.MaxOccurs > 1 || e.MaxOccurs == wsdl.Unbounded
+	optional := e.MinOccurs == 0
+	if e.Nillable {
+		goType = g.nillable(goType, complex)
+		complex = false
+	}
+	if complex || optional && !repeated && !omitsZero(goType) && !g.arrays[goType] {
+		goType = "*" + goType
+	}
+	if repeated {
+		goType = "[]" + goType
+	}
+
+	tag := e.Name
+	if optional {
+		tag += ",omitempty"
+	}
+
+	return &field{Name: goName(e.Name), Type: goType, Tag: tag}
+}
+
+// nillable returns the wrapper type for nillable elements holding values of
+// goType, declaring it on first use.
+func (g *generator) nillable(goType string, complex bool) string {
+	base := goType[strings.LastIndex(goType, ".")+1:]
+	if goType == "[]byte" {
+		base = "Bytes"
+	}
+	name := "Nillable" + strings.ToUpper(base[:1]) + base[1:]
+	if g.nillables[name] != nil {
+		return name
+	}
+
+	n := &nillableType{Name: name, Type: goType, Complex: complex}
+	if complex {
+		n.Type = "*" + goType
+	}
+	g.nillables[name] = n
+	g.nillableOrder = append(g.nillableOrder, n)
+	g.names[name] = true
+	g.imports[XSDImport] = true
+
+	return name
+}
+
+// omitsZero reports whether values of goType are left out when zero: byte
+// slices, nil when absent.
+func omitsZero(goType string) bool {
+	switch goType {
+	case "[]byte", "xsd.Base64Binary", "xsd.HexBinary":
+		return true
+	}
+
+	return false
+}
+
+// isDate reports whether goType is one of the xsd date and time types,
+// which are written even when zero and so need a pointer to be left out.
+func isDate(goType string) bool {
+	switch goType {
+	case "xsd.DateTime", "xsd.Date", "xsd.Time":
+		return true
+	}
+
+	return false
+}
+
+// valueType returns the Go type of text content typed name, or of the
+// anonymous simple type inline.
+func (g *generator) valueType(name wsdl.QName, inline *wsdl.SimpleType) (string, error) {
+	if inline != nil {
+		name = inline.Base
+	}
+	if name.IsZero() {
+		return "string", nil
+	}
+	if goType, ok := builtinType(name); ok {
+		if strings.HasPrefix(goType, "xsd.") {
+			g.imports[XSDImport] = true
+		}
+		return goType, nil
+	}
+	if g.defs.SimpleType(name) != nil {
+		return goName(name.Local), nil
+	}
+
+	return "", fmt.Errorf("unknown type %s", name)
+}
+
+func (g *generator) service(portType *wsdl.PortType) (*service, error) {
+	s := &service{Name: goName(portType.Name)}
+	s.Interface = g.freeName(s.Name + "Client")
+	s.Server = g.freeName(s.Name + "Server")
+
+	binding := g.defs.SOAPBinding(portType)
+	if binding != nil {
+		s.URL = g.defs.Address(binding)
+	}
+
+	for _, op := range portType.Operations {
+		if op.Input == nil || op.Output == nil {
+			return nil, fmt.Errorf("generator: operation %s of %s is not request-response", op.Name, portType.Name)
+		}
+		m := &method{
+			Service:       s.Name,
+			Name:          goName(op.Name),
+			Operation:     op.Name,
+			Documentation: strings.Join(strings.Fields(op.Documentation), " "),
+		}
+		var bop *wsdl.BindingOperation
+		if binding != nil {
+			bop = binding.Operation(op.Name)
+		}
+		if bop != nil {
+			m.SOAPAction = bop.SOAPAction
+		}
+
+		messageType := g.messageType
+		if bop != nil && bop.Style == "rpc" {
+			messageType = g.rpcMessage
+			m.RPC = true
+			if bop.Input != nil {
+				m.Namespace = bop.Input.Namespace
+				m.Encoded = bop.Input.Use == "encoded"
+			}
+		}
+		var err error
+		if m.Request, err = messageType(op.Input.Message); err != nil {
+			return nil, err
+		}
+		if m.Response, err = messageType(op.Output.Message); err != nil {
+			return nil, err
+		}
+		if m.RPC {
+			m.Element = wsdl.QName{Space: m.Namespace, Local: op.Name}
+			if m.Encoded {
+				m.RequestTypes = g.partTypes(op.Input.Message)
+				m.ResponseTypes = g.partTypes(op.Output.Message)
+			}
+		} else {
+			m.Element = g.defs.Message(op.Input.Message).Parts[0].Element
+		}
+		s.Operations = append(s.Operations, m)
+	}
+
+	return s, nil
+}
+
+// messageType returns the Go type of the document carried by a message.
+func (g *generator) messageType(name wsdl.QName) (string, error) {
+	message := g.defs.Message(name)
+	if message == nil {
+		return "", fmt.Errorf("generator: unknown message %s", name)
+	}
+	if len(message.Parts) != 1 || message.Parts[0].Element.IsZero() {
+		return "", fmt.Errorf("generator: message %s is not document/literal wrapped", message.Name)
+	}
+	element := message.Parts[0].Element
+	if g.defs.Element(element) == nil {
+		return "", fmt.Errorf("generator: message %s refers to unknown element %s", message.Name, element)
+	}
+
+	return goName(element.Local), nil
+}
+
+// freeName returns name, or name suffixed with a number if the schema
+// types take it already, and reserves it.
+func (g *generator) freeName(name string) string {
+	free := name
+	for i := 2; g.names[free]; i++ {
+		free = fmt.Sprintf("%s%d", name, i)
+	}
+	g.names[free] = true
+
+	return free
+}
+
+// partTypes returns the types of the parts of a message typed after XML
+// schema built-in types.
+func (g *generator) partTypes(name wsdl.QName) []*partType {
+	var types []*partType
+	for _, part := range g.defs.Message(name).Parts {
+		if part.Type.Space == wsdl.XSDNamespace {
+			types = append(types, &partType{Accessor: part.Name, Type: part.Type.Local})
+		}
+	}
+
+	return types
+}
+
+// rpcMessage declares the struct of a message of an rpc style operation,
+// holding a field per part, and returns its name. Parts are required, as
+// the accessors of an rpc call always are.
+func (g *generator) rpcMessage(name wsdl.QName) (string, error) {
+	message := g.defs.Message(name)
+	if message == nil {
+		return "", fmt.Errorf("generator: unknown message %s", name)
+	}
+	if goType, ok := g.messages[message.Name]; ok {
+		return goType, nil
+	}
+
+	goType := goName(message.Name)
+	if g.names[goType] {
+		goType += "Message"
+	}
+	g.names[goType] = true
+	g.messages[message.Name] = goType
+
+	s := &structType{Name: goType}
+	for _, part := range message.Parts {
+		e := &wsdl.Element{Name: part.Name, Type: part.Type, MinOccurs: 1, MaxOccurs: 1}
+		if !part.Element.IsZero() {
+			e = &wsdl.Element{Ref: part.Element, MinOccurs: 1, MaxOccurs: 1}
+		}
+		f, err := g.elementField(goType, e)
+		if err != nil {
+			return "", err
+		}
+		s.Fields = append(s.Fields, f)
+	}
+	g.structs = append(g.structs, s)
+
+	return goType, nil
+}
+
+// builtinType maps XML schema built-in types to Go. The SOAP encoding
+// declares types of the same names, which are mapped alike.
+func builtinType(name wsdl.QName) (string, bool) {
+	if name.Space != wsdl.XSDNamespace && name.Space != wsdl.SOAPEncodingNamespace {
+		return "", false
+	}
+
+	switch name.Local {
+	case "string", "normalizedString", "token", "language", "Name", "NCName",
+		"NMTOKEN", "ID", "IDREF", "ENTITY", "anyURI", "QName", "anySimpleType",
+		"anyType", "gYear", "gYearMonth", "gMonth", "gMonthDay", "gDay":
+		return "string", true
+	case "boolean":
+		return "bool", true
+	case "byte":
+		return "int8", true
+	case "short":
+		return "int16", true
+	case "int":
+		return "int32", true
+	case "long", "integer", "negativeInteger", "nonPositiveInteger":
+		return "int64", true
+	case "unsignedByte":
+		return "uint8", true
+	case "unsignedShort":
+		return "uint16", true
+	case "unsignedInt":
+		return "uint32", true
+	case "unsignedLong", "positiveInteger", "nonNegativeInteger":
+		return "uint64", true
+	case "float":
+		return "float32", true
+	case "double", "decimal":
+		return "float64", true
+	case "dateTime":
+		return "xsd.DateTime", true
+	case "date":
+		return "xsd.Date", true
+	case "time":
+		return "xsd.Time", true
+	case "duration":
+		return "xsd.Duration", true
+	case "base64Binary":
+		return "xsd.Base64Binary", true
+	case "hexBinary":
+		return "xsd.HexBinary", true
+	}
+
+	return "", false
+}
+
+// goName turns an XML name into an exported Go identifier, dropping the
+// characters Go does not allow.
+func goName(name string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	if b.Len() > 0 && unicode.IsDigit([]rune(b.String())[0]) {
+		return "X" + b.String()
+	}
+
+	return b.String()
+}
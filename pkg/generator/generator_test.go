@@ -0,0 +1,529 @@
+package generator
+
+import (
+	"bufio"
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/luhonghai/wsdl-example/pkg/wsdl"
+	"github.com/magiconair/properties/assert"
+)
+
+// The checked-in client packages are generated; regenerating them must give
+// the same source.
+func TestGenerateCheckedInPackages(t *testing.T) {
+	catalog, err := wsdl.ReadCatalog("../schemas/catalog.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		wsdl, pkg, file, generate string
+	}{
+		{"calculator.xml", "calculator", "calculator.go", ""},
+		{"dilbert.xml", "dilbert", "dilbert.go", ""},
+		{"AmazonS3.wsdl", "aws", "aws_s3.go", " --file aws_s3.go --catalog ../schemas/catalog.txt"},
+	} {
+		defs, err := (&wsdl.Loader{Catalog: catalog}).Load("../" + test.wsdl)
+		if err != nil {
+			t.Fatal(err)
+		}
+		importPath := "github.com/luhonghai/wsdl-example/pkg/" + test.pkg
+		options := &Options{
+			Package:    test.pkg,
+			GoGenerate: "go run github.com/luhonghai/wsdl-example generate --wsdl ../" + test.wsdl + " --package " + test.pkg + " --out ." + test.generate + " --import " + importPath,
+			ImportPath: importPath,
+		}
+		source, err := Generate(defs, options)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, err := ioutil.ReadFile("../" + test.pkg + "/" + test.file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, string(source), string(want))
+
+		fake, err := GenerateFake(defs, options)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, err = ioutil.ReadFile("../" + test.pkg + "/" + test.pkg + "fake/" + test.pkg + "fake.go")
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, string(fake), string(want))
+	}
+}
+
+func TestGenerateRequiresLoadedSchemas(t *testing.T) {
+	defs, err := wsdl.ParseFile("../AmazonS3.wsdl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = Generate(defs, &Options{Package: "aws"})
+	if err == nil {
+		t.Fatal("expected an error for the unloaded include")
+	}
+}
+
+func TestGenerateOccurrences(t *testing.T) {
+	defs, err := wsdl.Parse(strings.NewReader(`<definitions xmlns="http://schemas.xmlsoap.org/wsdl/">
+  <types>
+    <xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" xmlns:tns="urn:items" targetNamespace="urn:items">
+      <xs:simpleType name="Color">
+        <xs:restriction base="xs:string"/>
+      </xs:simpleType>
+      <xs:complexType name="Item">
+        <xs:sequence>
+          <xs:element name="Count" type="xs:int"/>
+          <xs:element name="Limit" type="xs:int" minOccurs="0"/>
+          <xs:element name="Color" type="tns:Color"/>
+          <xs:element name="Shade" type="tns:Color" minOccurs="0"/>
+          <xs:element name="Data" type="xs:base64Binary" minOccurs="0"/>
+          <xs:element name="Tag" type="xs:string" minOccurs="0" maxOccurs="unbounded"/>
+          <xs:element name="Seen" type="xs:dateTime" minOccurs="0"/>
+          <xs:element name="Lasts" type="xs:duration" minOccurs="0"/>
+          <xs:element name="Child" type="tns:Item" minOccurs="0"/>
+        </xs:sequence>
+      </xs:complexType>
+    </xs:schema>
+  </types>
+</definitions>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	source, err := Generate(defs, &Options{Package: "items"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Compare with the alignment of struct fields squeezed out.
+	fields := strings.Join(strings.Fields(string(source)), " ")
+	for _, want := range []string{
+		"Count int32 `xml:\"Count\"`",
+		"Limit *int32 `xml:\"Limit,omitempty\"`",
+		"Color Color `xml:\"Color\"`",
+		"Shade *Color `xml:\"Shade,omitempty\"`",
+		"Data xsd.Base64Binary `xml:\"Data,omitempty\"`",
+		"Tag []string `xml:\"Tag,omitempty\"`",
+		"Seen *xsd.DateTime `xml:\"Seen,omitempty\"`",
+		"Lasts *xsd.Duration `xml:\"Lasts,omitempty\"`",
+		"Child *Item `xml:\"Child,omitempty\"`",
+	} {
+		if !strings.Contains(fields, want) {
+			t.Errorf("generated source lacks %s\n%s", want, source)
+		}
+	}
+}
+
+func TestGenerateNonStringEnumerations(t *testing.T) {
+	defs, err := wsdl.Parse(strings.NewReader(`<definitions xmlns="http://schemas.xmlsoap.org/wsdl/">
+  <types>
+    <xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" targetNamespace="urn:levels">
+      <xs:simpleType name="Level">
+        <xs:restriction base="xs:int">
+          <xs:enumeration value="1"/>
+          <xs:enumeration value="+02"/>
+          <xs:enumeration value="-3"/>
+        </xs:restriction>
+      </xs:simpleType>
+      <xs:simpleType name="Ratio">
+        <xs:restriction base="xs:double">
+          <xs:enumeration value="0.5"/>
+          <xs:enumeration value="1E3"/>
+        </xs:restriction>
+      </xs:simpleType>
+      <xs:simpleType name="Flag">
+        <xs:restriction base="xs:boolean">
+          <xs:enumeration value="1"/>
+        </xs:restriction>
+      </xs:simpleType>
+    </xs:schema>
+  </types>
+</definitions>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	source, err := Generate(defs, &Options{Package: "levels"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fields := strings.Join(strings.Fields(string(source)), " ")
+	for _, want := range []string{
+		"type Level int32",
+		"LevelX1 Level = 1",
+		"LevelX02 Level = 2",
+		"LevelX3 Level = -3",
+		"type Ratio float64",
+		"RatioX05 Ratio = 0.5",
+		"RatioX1E3 Ratio = 1000",
+		"FlagX1 Flag = true",
+	} {
+		if !strings.Contains(fields, want) {
+			t.Errorf("generated source lacks %s\n%s", want, source)
+		}
+	}
+
+	// The constants must compile, not only parse.
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "levels.go", source, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := &types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	if _, err := config.Check("levels", fset, []*ast.File{f}, nil); err != nil {
+		t.Errorf("generated source does not compile: %v\n%s", err, source)
+	}
+}
+
+func TestGenerateInvalidEnumeration(t *testing.T) {
+	defs, err := wsdl.Parse(strings.NewReader(`<definitions xmlns="http://schemas.xmlsoap.org/wsdl/">
+  <types>
+    <xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" targetNamespace="urn:levels">
+      <xs:simpleType name="Level">
+        <xs:restriction base="xs:int">
+          <xs:enumeration value="high"/>
+        </xs:restriction>
+      </xs:simpleType>
+    </xs:schema>
+  </types>
+</definitions>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Generate(defs, &Options{Package: "levels"}); err == nil {
+		t.Error("expected an error for an enumeration value that is not an int")
+	}
+}
+
+func TestGenerateEnumerationNames(t *testing.T) {
+	defs, err := wsdl.Parse(strings.NewReader(`<definitions xmlns="http://schemas.xmlsoap.org/wsdl/">
+  <types>
+    <xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" targetNamespace="urn:modes">
+      <xs:simpleType name="Mode">
+        <xs:restriction base="xs:string">
+          <xs:enumeration value="read-only"/>
+          <xs:enumeration value="readonly"/>
+          <xs:enumeration value=""/>
+          <xs:enumeration value="-"/>
+        </xs:restriction>
+      </xs:simpleType>
+    </xs:schema>
+  </types>
+</definitions>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	source, err := Generate(defs, &Options{Package: "modes"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fields := strings.Join(strings.Fields(string(source)), " ")
+	for _, want := range []string{
+		`ModeReadonly Mode = "read-only"`,
+		`ModeReadonly2 Mode = "readonly"`,
+		`ModeEmpty Mode = ""`,
+		`ModeEmpty2 Mode = "-"`,
+	} {
+		if !strings.Contains(fields, want) {
+			t.Errorf("generated source lacks %s\n%s", want, source)
+		}
+	}
+
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "modes.go", source, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := &types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	if _, err := config.Check("modes", fset, []*ast.File{f}, nil); err != nil {
+		t.Errorf("generated source does not compile: %v\n%s", err, source)
+	}
+
+	// A type named like a constant cannot be renamed after the fact.
+	defs, err = wsdl.Parse(strings.NewReader(`<definitions xmlns="http://schemas.xmlsoap.org/wsdl/">
+  <types>
+    <xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" targetNamespace="urn:modes">
+      <xs:simpleType name="Mode">
+        <xs:restriction base="xs:string">
+          <xs:enumeration value="open"/>
+        </xs:restriction>
+      </xs:simpleType>
+      <xs:complexType name="ModeOpen">
+        <xs:sequence><xs:element name="since" type="xs:string"/></xs:sequence>
+      </xs:complexType>
+    </xs:schema>
+  </types>
+</definitions>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = Generate(defs, &Options{Package: "modes"})
+	assert.Equal(t, fmt.Sprint(err), "generator: the schema names yield ModeOpen twice")
+}
+
+// Every go:generate directive of the repository must run, and regenerate
+// the file it sits in as it is checked in. The directives write to a
+// temporary directory instead of the package.
+func TestGoGenerateDirectives(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs the directives with go run")
+	}
+
+	var directives int
+	err := filepath.Walk("../..", func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() && (info.Name() == ".git" || info.Name() == "testdata") {
+			return filepath.SkipDir
+		}
+		if info.IsDir() || !strings.HasSuffix(path, ".go") {
+			return nil
+		}
+
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			// go generate takes every line starting so for a directive.
+			if !strings.HasPrefix(scanner.Text(), "//go:generate ") {
+				continue
+			}
+			directives++
+			runDirective(t, filepath.Dir(path), strings.Fields(strings.TrimPrefix(scanner.Text(), "//go:generate ")))
+		}
+
+		return scanner.Err()
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if directives == 0 {
+		t.Error("found no go:generate directive")
+	}
+}
+
+func runDirective(t *testing.T, dir string, args []string) {
+	out, err := ioutil.TempDir("", "generate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(out)
+
+	for i := range args {
+		if i > 0 && args[i-1] == "--out" {
+			args[i] = out
+		}
+	}
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Dir = dir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Errorf("%s: %s: %v\n%s", dir, strings.Join(args, " "), err, output)
+		return
+	}
+
+	err = filepath.Walk(out, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(out, path)
+		if err != nil {
+			return err
+		}
+		got, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		want, err := ioutil.ReadFile(filepath.Join(dir, rel))
+		if err != nil {
+			return err
+		}
+		// The directive written out names the input relative to out.
+		if withoutDirective(got) != withoutDirective(want) {
+			t.Errorf("%s: regenerating %s gives a different source", dir, rel)
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func withoutDirective(source []byte) string {
+	var lines []string
+	for _, line := range strings.Split(string(source), "\n") {
+		if !strings.HasPrefix(line, "//go:generate ") {
+			lines = append(lines, line)
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func TestGenerateNillable(t *testing.T) {
+	defs, err := wsdl.Parse(strings.NewReader(`<definitions xmlns="http://schemas.xmlsoap.org/wsdl/">
+  <types>
+    <xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" xmlns:tns="urn:items" targetNamespace="urn:items">
+      <xs:complexType name="Item">
+        <xs:sequence>
+          <xs:element name="Name" type="xs:string" nillable="true"/>
+          <xs:element name="Count" type="xs:int" nillable="true" minOccurs="0"/>
+          <xs:element name="Due" type="xs:dateTime" nillable="true" maxOccurs="unbounded"/>
+          <xs:element name="Parent" type="tns:Item" nillable="true"/>
+          <xs:element name="Alias" type="xs:string" nillable="true"/>
+        </xs:sequence>
+      </xs:complexType>
+    </xs:schema>
+  </types>
+</definitions>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	source, err := Generate(defs, &Options{Package: "items"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fields := strings.Join(strings.Fields(string(source)), " ")
+	for _, want := range []string{
+		"Name NillableString `xml:\"Name\"`",
+		"Count *NillableInt32 `xml:\"Count,omitempty\"`",
+		"Due []NillableDateTime `xml:\"Due\"`",
+		"Parent NillableItem `xml:\"Parent\"`",
+		"type NillableItem struct { Value *Item Nil bool }",
+		"xsd.MarshalNillable(e, start, n.Nil || n.Value == nil, n.Value)",
+		"type NillableDateTime struct { Value xsd.DateTime Nil bool }",
+	} {
+		if !strings.Contains(fields, want) {
+			t.Errorf("generated source lacks %s\n%s", want, source)
+		}
+	}
+	assert.Equal(t, strings.Count(fields, "type NillableString struct"), 1)
+}
+
+func TestGenerateRPC(t *testing.T) {
+	defs, err := wsdl.Parse(strings.NewReader(`<definitions xmlns="http://schemas.xmlsoap.org/wsdl/"
+    xmlns:soap="http://schemas.xmlsoap.org/wsdl/soap/" xmlns:wsdl="http://schemas.xmlsoap.org/wsdl/"
+    xmlns:xs="http://www.w3.org/2001/XMLSchema" xmlns:tns="urn:people" targetNamespace="urn:people">
+  <types>
+    <xs:schema targetNamespace="urn:people" xmlns:soapenc="http://schemas.xmlsoap.org/soap/encoding/">
+      <xs:complexType name="Person">
+        <xs:sequence>
+          <xs:element name="name" type="xs:string"/>
+          <xs:element name="friends" type="tns:ArrayOfString"/>
+        </xs:sequence>
+      </xs:complexType>
+      <xs:complexType name="ArrayOfString">
+        <xs:complexContent>
+          <xs:restriction base="soapenc:Array">
+            <xs:attribute ref="soapenc:arrayType" wsdl:arrayType="xs:string[]"/>
+          </xs:restriction>
+        </xs:complexContent>
+      </xs:complexType>
+      <xs:complexType name="ArrayOfPerson">
+        <xs:complexContent>
+          <xs:restriction base="soapenc:Array">
+            <xs:sequence>
+              <xs:element name="item" type="tns:Person" maxOccurs="unbounded"/>
+            </xs:sequence>
+          </xs:restriction>
+        </xs:complexContent>
+      </xs:complexType>
+    </xs:schema>
+  </types>
+  <message name="LookupRequest">
+    <part name="names" type="tns:ArrayOfString"/>
+    <part name="limit" type="xs:int"/>
+  </message>
+  <message name="LookupResponse">
+    <part name="people" type="tns:ArrayOfPerson"/>
+  </message>
+  <portType name="People">
+    <operation name="Lookup">
+      <input message="tns:LookupRequest"/>
+      <output message="tns:LookupResponse"/>
+    </operation>
+  </portType>
+  <binding name="PeopleBinding" type="tns:People">
+    <soap:binding style="rpc" transport="http://schemas.xmlsoap.org/soap/http"/>
+    <operation name="Lookup">
+      <soap:operation soapAction="urn:people#Lookup"/>
+      <input><soap:body use="encoded" namespace="urn:people" encodingStyle="http://schemas.xmlsoap.org/soap/encoding/"/></input>
+      <output><soap:body use="encoded" namespace="urn:people" encodingStyle="http://schemas.xmlsoap.org/soap/encoding/"/></output>
+    </operation>
+  </binding>
+</definitions>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	source, err := Generate(defs, &Options{Package: "people"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fields := strings.Join(strings.Fields(string(source)), " ")
+	for _, want := range []string{
+		"Friends ArrayOfString `xml:\"friends\"`",
+		"type ArrayOfString []string",
+		`soap.MarshalArray(e, start, xml.Name{Space: "http://www.w3.org/2001/XMLSchema", Local: "string"}, items)`,
+		"type ArrayOfPerson []*Person",
+		"type LookupRequest struct { Names ArrayOfString `xml:\"names\"` Limit int32 `xml:\"limit\"` }",
+		"type LookupResponse struct { People ArrayOfPerson `xml:\"people\"` }",
+		`rpc := &soap.RPC{Operation: "Lookup", Namespace: "urn:people", Encoded: true, RequestTypes: map[string]string{"limit": "int"}}`,
+		`err := service.client.CallRPC("urn:people#Lookup", rpc, request, response)`,
+		"type PeopleClient interface { Lookup(request *LookupRequest) (*LookupResponse, error) }",
+		"var _ PeopleClient = (*People)(nil)",
+		"type PeopleServer interface { Lookup(ctx context.Context, request *LookupRequest) (*LookupResponse, error) }",
+		`Element: xml.Name{Space: "urn:people", Local: "Lookup"}, RPC: &soap.RPC{Operation: "Lookup", Namespace: "urn:people", Encoded: true, RequestTypes: map[string]string{"limit": "int"}},`,
+		"return impl.Lookup(ctx, request.(*LookupRequest))",
+	} {
+		if !strings.Contains(fields, want) {
+			t.Errorf("generated source lacks %s\n%s", want, source)
+		}
+	}
+	if strings.Contains(string(source), `"sync"`) {
+		t.Error("the fakes are generated into the package")
+	}
+
+	fake, err := GenerateFake(defs, &Options{Package: "people", ImportPath: "example.com/people"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fields = strings.Join(strings.Fields(string(fake)), " ")
+	for _, want := range []string{
+		"package peoplefake",
+		`"example.com/people"`,
+		"type People struct { mu sync.Mutex LookupFunc func(request *people.LookupRequest) (*people.LookupResponse, error) LookupRequests []*people.LookupRequest }",
+		"var _ people.PeopleClient = (*People)(nil)",
+		"return new(people.LookupResponse), nil",
+	} {
+		if !strings.Contains(fields, want) {
+			t.Errorf("generated fake lacks %s\n%s", want, fake)
+		}
+	}
+}
+
+func TestGoName(t *testing.T) {
+	assert.Equal(t, goName("intA"), "IntA")
+	assert.Equal(t, goName("READ_ACP"), "READACP")
+	assert.Equal(t, goName("x-amz-meta"), "Xamzmeta")
+	assert.Equal(t, goName("2nd"), "X2nd")
+}
@@ -0,0 +1,240 @@
+package generator
+
+import "text/template"
+
+var fileTemplate = template.Must(template.New("file").Parse(`// Code generated by wsdl-example generate; DO NOT EDIT.
+{{if .GoGenerate}}
+{{/* Spelled so that go generate does not take this line for a directive. */}}
+{{- "//go:"}}generate {{.GoGenerate}}
+{{end}}
+package {{.Package}}
+
+import (
+{{range .Imports}}{{if .}}	"{{.}}"
+{{else}}
+{{end}}{{end}})
+
+// against "unused imports"
+var _ time.Time
+var _ xml.Name
+{{range .Simple}}{{$type := .Name}}
+type {{.Name}} {{.Base}}
+{{if .Values}}
+const (
+{{range $i, $v := .Values}}{{if $i}}
+{{end}}	{{$v.Name}} {{$type}} = {{$v.Literal}}
+{{end}})
+{{end}}{{if .Enum}}
+// Values returns the values {{.Name}} is restricted to.
+func ({{.Name}}) Values() []{{.Name}} {
+	return []{{.Name}}{
+{{- range .Values}}
+		{{.Name}},
+{{- end}}
+	}
+}
+
+// Validate returns an *xsd.EnumError unless v is one of Values.
+func (v {{.Name}}) Validate() error {
+	return xsd.ValidateEnum({{printf "%q" .Name}}, string(v){{range .Values}}, {{printf "%q" .Value}}{{end}})
+}
+
+// MarshalText refuses values outside the enumeration unless
+// xsd.LenientEnums reports true.
+func (v {{.Name}}) MarshalText() ([]byte, error) {
+	if err := v.Validate(); err != nil && !xsd.LenientEnums() {
+		return nil, err
+	}
+
+	return []byte(v), nil
+}
+
+// UnmarshalText refuses values outside the enumeration unless
+// xsd.LenientEnums reports true.
+func (v *{{.Name}}) UnmarshalText(text []byte) error {
+	value := {{.Name}}(text)
+	if err := value.Validate(); err != nil && !xsd.LenientEnums() {
+		return err
+	}
+	*v = value
+
+	return nil
+}
+{{end}}{{end}}
+{{- range .Structs}}
+type {{.Name}} struct {
+{{- range .Head}}
+	{{.Name}} {{.Type}} ` + "`" + `xml:"{{.Tag}}"` + "`" + `
+{{- end}}
+{{- if and .Head .Fields}}
+{{end}}
+{{- range .Fields}}
+	{{.Name}} {{.Type}} ` + "`" + `xml:"{{.Tag}}"` + "`" + `
+{{- end}}
+}
+{{end}}
+{{- range .Nillable}}
+// {{.Name}} is the value of a nillable element; Nil stands for xsi:nil.
+type {{.Name}} struct {
+	Value {{.Type}}
+	Nil   bool
+}
+
+func (n {{.Name}}) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return xsd.MarshalNillable(e, start, n.Nil{{if .Complex}} || n.Value == nil{{end}}, n.Value)
+}
+
+func (n *{{.Name}}) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var err error
+	n.Nil, err = xsd.UnmarshalNillable(d, start, &n.Value)
+
+	return err
+}
+{{end}}
+{{- range .Arrays}}
+// {{.Name}} is a SOAP encoded array.
+type {{.Name}} []{{.Item}}
+
+func (a {{.Name}}) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	items := make([]interface{}, len(a))
+	for i := range a {
+		items[i] = a[i]
+	}
+
+	return soap.MarshalArray(e, start, xml.Name{Space: {{printf "%q" .ItemType.Space}}, Local: {{printf "%q" .ItemType.Local}}}, items)
+}
+
+func (a *{{.Name}}) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	return soap.UnmarshalArray(d, func(item xml.StartElement) error {
+		var value {{.Item}}
+		if err := d.DecodeElement(&value, &item); err != nil {
+			return err
+		}
+		*a = append(*a, value)
+
+		return nil
+	})
+}
+{{end}}
+{{- range .Services}}
+// {{.Interface}} is the interface of the {{.Name}} port.
+//
+// {{.Name}} implements it, and so does the fake of the same name in
+// package {{$.Package}}fake, for tests.
+type {{.Interface}} interface {
+{{- range .Operations}}
+	{{.Name}}(request *{{.Request}}) (*{{.Response}}, error)
+{{- end}}
+}
+
+type {{.Name}} struct {
+	client *soap.Client
+}
+
+var _ {{.Interface}} = (*{{.Name}})(nil)
+
+func New{{.Name}}(url string, tls bool, auth *soap.BasicAuth) *{{.Name}} {
+	if url == "" {
+		url = {{printf "%q" .URL}}
+	}
+	client := soap.NewClient(url, tls, auth)
+
+	return New{{.Name}}WithClient(client)
+}
+
+// New{{.Name}}WithClient returns a {{.Name}} making its calls through client.
+func New{{.Name}}WithClient(client *soap.Client) *{{.Name}} {
+	return &{{.Name}}{
+		client: client,
+	}
+}
+{{range .Operations}}
+{{if .Documentation}}/* {{.Documentation}} */
+{{end}}func (service *{{.Service}}) {{.Name}}(request *{{.Request}}) (*{{.Response}}, error) {
+	response := new({{.Response}})
+{{- if .RPC}}
+	rpc := {{template "rpc" .}}
+	err := service.client.CallRPC({{printf "%q" .SOAPAction}}, rpc, request, response)
+{{- else}}
+	err := service.client.Call({{printf "%q" .SOAPAction}}, request, response)
+{{- end}}
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+{{end}}
+// {{.Server}} is implemented by services serving the {{.Name}} port.
+type {{.Server}} interface {
+{{- range .Operations}}
+	{{.Name}}(ctx context.Context, request *{{.Request}}) (*{{.Response}}, error)
+{{- end}}
+}
+
+// New{{.Name}}Handler returns an http.Handler serving impl as the {{.Name}}
+// port. Errors impl returns are sent as faults, a *soap.Fault as it is. The
+// WSDL document wsdl, when not nil, is served at ?wsdl.
+func New{{.Name}}Handler(impl {{.Server}}, wsdl []byte) *soap.Server {
+	return soap.NewServer(wsdl,
+{{- range .Operations}}
+		&soap.Operation{
+			Name:       {{printf "%q" .Operation}},
+			SOAPAction: {{printf "%q" .SOAPAction}},
+			Element:    xml.Name{Space: {{printf "%q" .Element.Space}}, Local: {{printf "%q" .Element.Local}}},
+{{- if .RPC}}
+			RPC:        {{template "rpc" .}},
+{{- end}}
+			NewRequest: func() interface{} { return new({{.Request}}) },
+			Handle: func(ctx context.Context, request interface{}) (interface{}, error) {
+				return impl.{{.Name}}(ctx, request.(*{{.Request}}))
+			},
+		},
+{{- end}}
+	)
+}
+{{end}}
+{{- define "rpc"}}&soap.RPC{Operation: {{printf "%q" .Operation}}, Namespace: {{printf "%q" .Namespace}}{{if .Encoded}}, Encoded: true{{end}}
+{{- if .RequestTypes}}, RequestTypes: {{template "types" .RequestTypes}}{{end}}
+{{- if .ResponseTypes}}, ResponseTypes: {{template "types" .ResponseTypes}}{{end}}}{{end}}
+{{- define "types"}}map[string]string{ {{- range $i, $t := .}}{{if $i}}, {{end}}{{printf "%q" $t.Accessor}}: {{printf "%q" $t.Type}}{{end}}}{{end}}`))
+
+var fakeTemplate = template.Must(template.New("fake").Parse(`// Code generated by wsdl-example generate; DO NOT EDIT.
+
+// Package {{.Package}} holds in-memory implementations of the ports of
+// package {{.Main}}, for tests.
+package {{.Package}}
+
+import (
+	"sync"
+
+	"{{.Import}}"
+)
+{{range .Services}}{{$service := .Name}}
+// {{.Name}} is an in-memory {{$.Main}}.{{.Interface}}.
+//
+// It records the requests of every call and answers with the function set
+// for the operation, or with an empty response when there is none.
+type {{.Name}} struct {
+	mu sync.Mutex
+{{range .Operations}}
+	{{.Name}}Func     func(request *{{$.Main}}.{{.Request}}) (*{{$.Main}}.{{.Response}}, error)
+	{{.Name}}Requests []*{{$.Main}}.{{.Request}}
+{{- end}}
+}
+
+var _ {{$.Main}}.{{.Interface}} = (*{{.Name}})(nil)
+{{range .Operations}}
+func (fake *{{$service}}) {{.Name}}(request *{{$.Main}}.{{.Request}}) (*{{$.Main}}.{{.Response}}, error) {
+	fake.mu.Lock()
+	fake.{{.Name}}Requests = append(fake.{{.Name}}Requests, request)
+	fn := fake.{{.Name}}Func
+	fake.mu.Unlock()
+
+	if fn != nil {
+		return fn(request)
+	}
+
+	return new({{$.Main}}.{{.Response}}), nil
+}
+{{end}}{{end}}`))
@@ -0,0 +1,248 @@
+// Package mock serves any WSDL document from a set of scripted rules, to
+// test clients against services one does not control. Requests are checked
+// against the schema and answered by the first rule they match, with a
+// templated response, a fault, an HTTP error or after a delay.
+package mock
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/luhonghai/wsdl-example/pkg/dynamic"
+	"github.com/luhonghai/wsdl-example/pkg/soap"
+	"github.com/luhonghai/wsdl-example/pkg/validate"
+	"github.com/luhonghai/wsdl-example/pkg/wsdl"
+	"github.com/luhonghai/wsdl-example/pkg/xsd"
+)
+
+// Rule answers the requests for an operation that match all its
+// predicates. A rule answers with an HTTP error when Status is set, with
+// Fault when it is set, and with Response otherwise.
+type Rule struct {
+	// Operation names the operation, qualified by its port type as in
+	// "CalculatorSoap.Add" when several port types offer it.
+	Operation string      `json:"operation"`
+	Match     []Predicate `json:"match,omitempty"`
+	// Response is a text/template of the element sent back in the body.
+	// It is executed with a *Request, as in {{.Value "intA"}}, and may call
+	// now for the current time in the lexical form of xsd:dateTime. The
+	// element it yields must be the output of the operation and conform to
+	// the schema.
+	Response string      `json:"response,omitempty"`
+	Fault    *soap.Fault `json:"fault,omitempty"`
+	Status   int         `json:"status,omitempty"`
+	// Delay holds the answer back for a duration such as "1.5s".
+	Delay string `json:"delay,omitempty"`
+
+	operation *dynamic.Operation
+	response  *template.Template
+	delay     time.Duration
+}
+
+// Predicate tests the value at Path in a request, a path of local names
+// below the request element such as "Contents[2]/Key" or "Grantee/@type".
+// Positions count from 1. Only the tests that are set apply; a predicate
+// with none matches when the path is there.
+type Predicate struct {
+	Path   string  `json:"path"`
+	Equals *string `json:"equals,omitempty"`
+	Regexp string  `json:"regexp,omitempty"`
+	Exists *bool   `json:"exists,omitempty"`
+
+	regexp *regexp.Regexp
+}
+
+// Request is a request handed to the templates of responses.
+type Request struct {
+	Operation string
+	node      *dynamic.Node
+}
+
+// Value returns the trimmed text at path in the request escaped for XML, so
+// that it can be spliced into a response as it is, or an empty string if
+// there is nothing there.
+func (r *Request) Value(path string) string {
+	value, _ := lookup(r.node, path)
+	var buffer bytes.Buffer
+	xml.EscapeText(&buffer, []byte(value))
+
+	return buffer.String()
+}
+
+// ReadRules reads the rules in the JSON file at path, an array of rules.
+func ReadRules(path string) ([]*Rule, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var rules []*Rule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("mock: reading %s: %v", path, err)
+	}
+
+	return rules, nil
+}
+
+// NewServer returns a server for the document/literal operations of defs
+// answering from rules, which are tried in order. Requests departing from
+// the schema get a client fault, and requests no rule matches, or whose
+// response departs from it, a server fault. The WSDL document wsdlDoc, when
+// not nil, is served at ?wsdl.
+func NewServer(defs *wsdl.Definitions, wsdlDoc []byte, rules []*Rule) (*soap.Server, error) {
+	client := dynamic.NewClient(defs, "", false, nil)
+	for i, rule := range rules {
+		if err := rule.compile(client); err != nil {
+			return nil, fmt.Errorf("mock: rule %d: %v", i+1, err)
+		}
+	}
+
+	validator := validate.New(defs)
+	var operations []*soap.Operation
+	for _, op := range client.Operations() {
+		op := op
+		operations = append(operations, &soap.Operation{
+			Name:       op.Name,
+			SOAPAction: op.SOAPAction,
+			Element:    xml.Name{Space: op.Input.Space, Local: op.Input.Local},
+			NewRequest: func() interface{} { return new(dynamic.Node) },
+			Handle: func(ctx context.Context, request interface{}) (interface{}, error) {
+				return answer(ctx, validator, op, rules, request.(*dynamic.Node))
+			},
+		})
+	}
+
+	return soap.NewServer(wsdlDoc, operations...), nil
+}
+
+func (r *Rule) compile(client *dynamic.Client) error {
+	var err error
+	if r.operation, err = client.Operation(r.Operation); err != nil {
+		return err
+	}
+	if r.response, err = template.New(r.Operation).Funcs(template.FuncMap{"now": now}).Parse(r.Response); err != nil {
+		return err
+	}
+	if r.Delay != "" {
+		if r.delay, err = time.ParseDuration(r.Delay); err != nil {
+			return err
+		}
+	}
+	for i := range r.Match {
+		if p := &r.Match[i]; p.Regexp != "" {
+			if p.regexp, err = regexp.Compile(p.Regexp); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func now() string {
+	return xsd.DateTime{Time: time.Now().UTC()}.String()
+}
+
+// answer serves request with the first rule matching it.
+func answer(ctx context.Context, validator *validate.Validator, op *dynamic.Operation, rules []*Rule, request *dynamic.Node) (interface{}, error) {
+	if request.Name.Local == "" {
+		return nil, &soap.Fault{Code: "soap:Client", String: fmt.Sprintf("the %s request is missing", op.Name)}
+	}
+	data, err := xml.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	if err := validator.ValidateElement(bytes.NewReader(data)); err != nil {
+		return nil, &soap.Fault{Code: "soap:Client", String: err.Error()}
+	}
+
+	for _, rule := range rules {
+		if rule.operation.Name != op.Name || rule.operation.PortType != op.PortType || !rule.matches(request) {
+			continue
+		}
+
+		if rule.delay > 0 {
+			timer := time.NewTimer(rule.delay)
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+				return nil, ctx.Err()
+			}
+		}
+		switch {
+		case rule.Status != 0:
+			return nil, &soap.HTTPError{StatusCode: rule.Status, Message: strings.TrimSpace(rule.Response)}
+		case rule.Fault != nil:
+			return nil, rule.Fault
+		}
+
+		var buffer bytes.Buffer
+		if err := rule.response.Execute(&buffer, &Request{Operation: op.Name, node: request}); err != nil {
+			return nil, err
+		}
+
+		response := bytes.TrimSpace(buffer.Bytes())
+		if err := checkResponse(validator, op, response); err != nil {
+			return nil, fmt.Errorf("mock: the %s response: %v", op.Name, err)
+		}
+
+		return soap.RawXML(response), nil
+	}
+
+	return nil, fmt.Errorf("mock: no rule matches the %s request", op.Name)
+}
+
+// checkResponse checks that response is the output element of op, alone,
+// and conforms to the schema.
+func checkResponse(validator *validate.Validator, op *dynamic.Operation, response []byte) error {
+	d := xml.NewDecoder(bytes.NewReader(response))
+	node := new(dynamic.Node)
+	if err := d.Decode(node); err != nil {
+		return err
+	}
+	for {
+		token, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if text, ok := token.(xml.CharData); !ok || len(bytes.TrimSpace(text)) > 0 {
+			return errors.New("content follows the response element")
+		}
+	}
+	if node.Name.Space != op.Output.Space || node.Name.Local != op.Output.Local {
+		return fmt.Errorf("{%s}%s is not the output {%s}%s", node.Name.Space, node.Name.Local, op.Output.Space, op.Output.Local)
+	}
+
+	return validator.ValidateElement(bytes.NewReader(response))
+}
+
+func (r *Rule) matches(request *dynamic.Node) bool {
+	for _, p := range r.Match {
+		value, found := lookup(request, p.Path)
+		switch {
+		case p.Exists != nil && found != *p.Exists:
+			return false
+		case !found && (p.Exists == nil || p.Equals != nil || p.regexp != nil):
+			return false
+		case p.Equals != nil && value != *p.Equals:
+			return false
+		case p.regexp != nil && !p.regexp.MatchString(value):
+			return false
+		}
+	}
+
+	return true
+}
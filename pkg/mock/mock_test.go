@@ -0,0 +1,196 @@
+package mock
+
+import (
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/luhonghai/wsdl-example/pkg/calculator"
+	"github.com/luhonghai/wsdl-example/pkg/dynamic"
+	"github.com/luhonghai/wsdl-example/pkg/soap"
+	"github.com/luhonghai/wsdl-example/pkg/wsdl"
+	"github.com/magiconair/properties/assert"
+)
+
+func newTestServer(t *testing.T) *httptest.Server {
+	defs, err := wsdl.ParseFile("../calculator.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rules, err := ReadRules("testdata/calculator.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler, err := NewServer(defs, nil, rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestMock(t *testing.T) {
+	service := calculator.NewCalculatorSoap(newTestServer(t).URL, false, nil)
+
+	resp, err := service.Add(&calculator.Add{IntA: 1, IntB: 41})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, resp.AddResult, int32(41))
+
+	_, err = service.Divide(&calculator.Divide{IntA: 1, IntB: 0})
+	assert.Equal(t, err.Error(), "Attempted to divide by zero.")
+
+	start := time.Now()
+	_, err = service.Add(&calculator.Add{IntA: 999, IntB: 1})
+	var httpErr *soap.HTTPError
+	if !errors.As(err, &httpErr) {
+		t.Fatalf("got %v, want an HTTP error", err)
+	}
+	assert.Equal(t, httpErr.StatusCode, http.StatusServiceUnavailable)
+	assert.Equal(t, httpErr.Message, "too many nines")
+	if time.Since(start) < 10*time.Millisecond {
+		t.Error("the rule delay was not applied")
+	}
+
+	_, err = service.Divide(&calculator.Divide{IntA: 1, IntB: 2})
+	assert.Equal(t, err.(*soap.Fault).Code, "soap:Server")
+	assert.Equal(t, err.Error(), "mock: no rule matches the Divide request")
+}
+
+func TestMockValidatesRequests(t *testing.T) {
+	client := soap.NewClient(newTestServer(t).URL, false, nil)
+
+	err := client.Call("http://tempuri.org/Subtract", &struct {
+		XMLName xml.Name `xml:"http://tempuri.org/ Subtract"`
+		IntA    string   `xml:"intA"`
+	}{IntA: "one"}, new(calculator.SubtractResponse))
+	assert.Equal(t, err.(*soap.Fault).Code, "soap:Client")
+	assert.Equal(t, err.Error(), `validate: /Subtract/intA: "one" is not a valid int; /Subtract/intB: required element is missing`)
+
+	err = client.Call("http://tempuri.org/Subtract", nil, new(calculator.SubtractResponse))
+	assert.Equal(t, err.Error(), "the Subtract request is missing")
+}
+
+func TestNewServerErrors(t *testing.T) {
+	defs, err := wsdl.ParseFile("../calculator.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = NewServer(defs, nil, []*Rule{{Operation: "Modulo"}})
+	assert.Equal(t, err.Error(), "mock: rule 1: dynamic: no operation Modulo")
+
+	_, err = NewServer(defs, nil, []*Rule{{Operation: "Add", Match: []Predicate{{Path: "intA", Regexp: "("}}}})
+	if err == nil {
+		t.Error("invalid regexp accepted")
+	}
+}
+
+func TestLookup(t *testing.T) {
+	n := new(dynamic.Node)
+	err := xml.Unmarshal([]byte(`<List><Contents><Key>a</Key></Contents><Contents><Key> b </Key>`+
+		`<Owner type="Group"/></Contents></List>`), n)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		path  string
+		value string
+		found bool
+	}{
+		{"Contents/Key", "a", true},
+		{"Contents[2]/Key", "b", true},
+		{"/Contents[2]/Owner/@type", "Group", true},
+		{"Contents[3]/Key", "", false},
+		{"Contents[0]", "", false},
+		{"Contents/@type", "", false},
+	} {
+		value, found := lookup(n, test.path)
+		assert.Equal(t, value, test.value, test.path)
+		assert.Equal(t, found, test.found, test.path)
+	}
+}
+
+const echoWSDL = `<definitions xmlns="http://schemas.xmlsoap.org/wsdl/" xmlns:soap="http://schemas.xmlsoap.org/wsdl/soap/"
+    xmlns:xs="http://www.w3.org/2001/XMLSchema" xmlns:t="urn:echo" targetNamespace="urn:echo">
+  <types>
+    <xs:schema targetNamespace="urn:echo" elementFormDefault="qualified">
+      <xs:element name="Echo">
+        <xs:complexType><xs:sequence><xs:element name="text" type="xs:string"/></xs:sequence></xs:complexType>
+      </xs:element>
+      <xs:element name="EchoResponse">
+        <xs:complexType><xs:sequence><xs:element name="text" type="xs:string"/></xs:sequence></xs:complexType>
+      </xs:element>
+    </xs:schema>
+  </types>
+  <message name="EchoIn"><part name="parameters" element="t:Echo"/></message>
+  <message name="EchoOut"><part name="parameters" element="t:EchoResponse"/></message>
+  <portType name="EchoPort">
+    <operation name="Echo"><input message="t:EchoIn"/><output message="t:EchoOut"/></operation>
+  </portType>
+  <binding name="EchoBinding" type="t:EchoPort">
+    <soap:binding transport="http://schemas.xmlsoap.org/soap/http"/>
+    <operation name="Echo">
+      <soap:operation soapAction="urn:echo/Echo"/>
+      <input><soap:body use="literal"/></input>
+      <output><soap:body use="literal"/></output>
+    </operation>
+  </binding>
+  <service name="EchoService">
+    <port name="EchoPort" binding="t:EchoBinding"><soap:address location="http://localhost/echo"/></port>
+  </service>
+</definitions>`
+
+type echo struct {
+	XMLName xml.Name `xml:"urn:echo Echo"`
+	Text    string   `xml:"text"`
+}
+
+type echoResponse struct {
+	XMLName xml.Name `xml:"urn:echo EchoResponse"`
+	Text    string   `xml:"text"`
+}
+
+func TestMockResponses(t *testing.T) {
+	defs, err := wsdl.Parse(strings.NewReader(echoWSDL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler, err := NewServer(defs, nil, []*Rule{
+		{Operation: "Echo", Match: []Predicate{{Path: "text", Regexp: "^wrong$"}},
+			Response: `<EchoResponse xmlns="urn:echo"><txt>{{.Value "text"}}</txt></EchoResponse>`},
+		{Operation: "Echo", Match: []Predicate{{Path: "text", Regexp: "^twice$"}},
+			Response: `<EchoResponse xmlns="urn:echo"><text>1</text></EchoResponse><EchoResponse xmlns="urn:echo"><text>2</text></EchoResponse>`},
+		{Operation: "Echo", Response: `<EchoResponse xmlns="urn:echo"><text>{{.Value "text"}}</text></EchoResponse>`},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	client := soap.NewClient(server.URL, false, nil)
+
+	// Values are escaped, so markup in requests cannot reach the response.
+	for _, text := range []string{"a < b & c", `</text><injected/><text>`} {
+		response := new(echoResponse)
+		if err := client.Call("urn:echo/Echo", &echo{Text: text}, response); err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, response.Text, text)
+	}
+
+	err = client.Call("urn:echo/Echo", &echo{Text: "wrong"}, new(echoResponse))
+	assert.Equal(t, err.(*soap.Fault).Code, "soap:Server")
+	assert.Equal(t, err.Error(), "mock: the Echo response: validate: /EchoResponse/txt: element is not declared in the content of its parent; /EchoResponse/text: required element is missing")
+
+	err = client.Call("urn:echo/Echo", &echo{Text: "twice"}, new(echoResponse))
+	assert.Equal(t, err.Error(), "mock: the Echo response: content follows the response element")
+}
@@ -0,0 +1,52 @@
+package mock
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/luhonghai/wsdl-example/pkg/dynamic"
+)
+
+// lookup returns the trimmed text at path below n, and whether there is
+// anything there. See Predicate for the form of paths.
+func lookup(n *dynamic.Node, path string) (string, bool) {
+	steps := strings.Split(strings.Trim(path, "/"), "/")
+	for i, step := range steps {
+		if strings.HasPrefix(step, "@") && i == len(steps)-1 {
+			for _, attr := range n.Attrs {
+				if attr.Name.Local == step[1:] {
+					return strings.TrimSpace(attr.Value), true
+				}
+			}
+			return "", false
+		}
+
+		name, position := step, 1
+		if open := strings.Index(step, "["); open > 0 && strings.HasSuffix(step, "]") {
+			p, err := strconv.Atoi(step[open+1 : len(step)-1])
+			if err != nil || p < 1 {
+				return "", false
+			}
+			name, position = step[:open], p
+		}
+		n = child(n, name, position)
+		if n == nil {
+			return "", false
+		}
+	}
+
+	return strings.TrimSpace(n.Text), true
+}
+
+// child returns the position-th child of n named local, counting from 1.
+func child(n *dynamic.Node, local string, position int) *dynamic.Node {
+	for _, c := range n.Children {
+		if c.Name.Local == local {
+			if position--; position == 0 {
+				return c
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,517 @@
+// Package openapi describes the operations of a WSDL document as an OpenAPI
+// 3 document, for the JSON endpoints the gateway package serves. Schema
+// types become schemas with their required elements, enumerations, patterns
+// and formats.
+package openapi
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/luhonghai/wsdl-example/pkg/dynamic"
+	"github.com/luhonghai/wsdl-example/pkg/wsdl"
+)
+
+// Version is the version of the OpenAPI specification documents follow.
+const Version = "3.0.3"
+
+// Document is an OpenAPI document, as far as this package writes them.
+type Document struct {
+	OpenAPI    string               `json:"openapi"`
+	Info       *Info                `json:"info"`
+	Servers    []*Server            `json:"servers,omitempty"`
+	Paths      map[string]*PathItem `json:"paths"`
+	Components *Components          `json:"components,omitempty"`
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+type Server struct {
+	URL string `json:"url"`
+}
+
+type PathItem struct {
+	Post *Operation `json:"post,omitempty"`
+}
+
+type Operation struct {
+	OperationID string               `json:"operationId"`
+	Summary     string               `json:"summary,omitempty"`
+	Description string               `json:"description,omitempty"`
+	Tags        []string             `json:"tags,omitempty"`
+	RequestBody *RequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*Response `json:"responses"`
+}
+
+type RequestBody struct {
+	Required bool                  `json:"required,omitempty"`
+	Content  map[string]*MediaType `json:"content"`
+}
+
+type Response struct {
+	Ref         string                `json:"$ref,omitempty"`
+	Description string                `json:"description,omitempty"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+type Components struct {
+	Schemas   map[string]*Schema   `json:"schemas,omitempty"`
+	Responses map[string]*Response `json:"responses,omitempty"`
+}
+
+// Schema is a schema object. Properties named "@name" stand for attributes
+// and "#text" for the text of elements with attributes, as the gateway
+// writes them. The property "@type" of types deriving from an abstract one
+// names the type, as the gateway takes it.
+type Schema struct {
+	Ref         string             `json:"$ref,omitempty"`
+	Type        string             `json:"type,omitempty"`
+	Format      string             `json:"format,omitempty"`
+	Description string             `json:"description,omitempty"`
+	Properties  map[string]*Schema `json:"properties,omitempty"`
+	Required    []string           `json:"required,omitempty"`
+	Items       *Schema            `json:"items,omitempty"`
+	MinItems    int                `json:"minItems,omitempty"`
+	MaxItems    int                `json:"maxItems,omitempty"`
+	Minimum     *int               `json:"minimum,omitempty"`
+	Maximum     *int               `json:"maximum,omitempty"`
+	Enum        []string           `json:"enum,omitempty"`
+	Pattern     string             `json:"pattern,omitempty"`
+	Nullable    bool               `json:"nullable,omitempty"`
+	AnyOf       []*Schema          `json:"anyOf,omitempty"`
+}
+
+// Options tune the document Generate writes.
+type Options struct {
+	// Title defaults to the name of the WSDL document, or of its first
+	// service.
+	Title string
+	// Version is the version of the API, "1.0.0" by default.
+	Version string
+	// ServerURL is where the gateway serves the operations, as in
+	// "/calculator".
+	ServerURL string
+}
+
+// errorResponse describes the bodies of the error responses of the
+// gateway.
+var errorResponse = &Schema{
+	Type:     "object",
+	Required: []string{"error"},
+	Properties: map[string]*Schema{
+		"error":      {Type: "string"},
+		"faultcode":  {Type: "string"},
+		"faultactor": {Type: "string"},
+		"detail":     {Type: "string"},
+	},
+}
+
+// Generate describes the document/literal operations of defs, each as a
+// POST of its request document to a path named after it.
+func Generate(defs *wsdl.Definitions, options *Options) (*Document, error) {
+	if options == nil {
+		options = &Options{}
+	}
+	g := &generator{defs: defs, names: make(map[wsdl.QName]string), schemas: make(map[string]*Schema)}
+
+	doc := &Document{
+		OpenAPI: Version,
+		Info: &Info{
+			Title:       options.Title,
+			Description: defs.Documentation,
+			Version:     options.Version,
+		},
+		Paths: make(map[string]*PathItem),
+		Components: &Components{
+			Schemas: g.schemas,
+			Responses: map[string]*Response{
+				"Error": {
+					Description: "The call failed: status 400 for client faults and invalid parameters, 502 for other faults and failures of the service, 504 for timeouts.",
+					Content:     jsonContent(&Schema{Ref: "#/components/schemas/ErrorResponse"}),
+				},
+			},
+		},
+	}
+	if doc.Info.Title == "" {
+		doc.Info.Title = defs.Name
+	}
+	if doc.Info.Title == "" && len(defs.Services) > 0 {
+		doc.Info.Title = defs.Services[0].Name
+	}
+	if doc.Info.Version == "" {
+		doc.Info.Version = "1.0.0"
+	}
+	if doc.Info.Description == "" && len(defs.Services) > 0 {
+		doc.Info.Description = defs.Services[0].Documentation
+	}
+	if options.ServerURL != "" {
+		doc.Servers = []*Server{{URL: options.ServerURL}}
+	}
+
+	g.nameTypes()
+	operations := dynamic.NewClient(defs, "", false, nil).Operations()
+	offered := make(map[string]int)
+	for _, op := range operations {
+		offered[op.Name]++
+	}
+	for _, op := range operations {
+		name := op.Name
+		if offered[name] > 1 {
+			name = op.PortType.Name + "." + op.Name
+		}
+		input, output := defs.Element(op.Input), defs.Element(op.Output)
+		if input == nil || output == nil {
+			return nil, fmt.Errorf("openapi: operation %s refers to undeclared elements", name)
+		}
+
+		doc.Paths["/"+name] = &PathItem{Post: &Operation{
+			OperationID: strings.Replace(name, ".", "_", -1),
+			Summary:     firstSentence(op.Documentation),
+			Description: op.Documentation,
+			Tags:        []string{op.PortType.Name},
+			RequestBody: &RequestBody{Required: true, Content: jsonContent(g.elementType(input))},
+			Responses: map[string]*Response{
+				"200": {Description: "The " + output.Name + " response.", Content: jsonContent(g.elementType(output))},
+				"400": {Ref: "#/components/responses/Error"},
+				"502": {Ref: "#/components/responses/Error"},
+				"504": {Ref: "#/components/responses/Error"},
+			},
+		}}
+	}
+	g.schemas["ErrorResponse"] = errorResponse
+
+	return doc, nil
+}
+
+func jsonContent(schema *Schema) map[string]*MediaType {
+	return map[string]*MediaType{"application/json": {Schema: schema}}
+}
+
+// firstSentence returns the first sentence of text, for summaries.
+func firstSentence(text string) string {
+	text = strings.TrimSpace(text)
+	if i := strings.Index(text, ". "); i >= 0 {
+		return text[:i+1]
+	}
+
+	return text
+}
+
+type generator struct {
+	defs *wsdl.Definitions
+	// names holds the component names of the named types, which lose
+	// their namespace.
+	names   map[wsdl.QName]string
+	schemas map[string]*Schema
+}
+
+// nameTypes names the components of the named types of every schema and
+// describes them. Names clashing across namespaces, or with ErrorResponse,
+// are numbered.
+func (g *generator) nameTypes() {
+	taken := map[string]bool{"ErrorResponse": true}
+	name := func(q wsdl.QName) {
+		n := q.Local
+		for i := 2; taken[n]; i++ {
+			n = fmt.Sprintf("%s%d", q.Local, i)
+		}
+		taken[n] = true
+		g.names[q] = n
+	}
+
+	var simple, complex []wsdl.QName
+	for _, schema := range g.defs.Schemas {
+		for _, st := range schema.SimpleTypes {
+			q := wsdl.QName{Space: schema.TargetNamespace, Local: st.Name}
+			name(q)
+			simple = append(simple, q)
+		}
+		for _, ct := range schema.ComplexTypes {
+			q := wsdl.QName{Space: schema.TargetNamespace, Local: ct.Name}
+			name(q)
+			complex = append(complex, q)
+		}
+	}
+	for _, q := range simple {
+		g.schemas[g.names[q]] = g.simpleType(g.defs.SimpleType(q))
+	}
+	for _, q := range complex {
+		g.schemas[g.names[q]] = g.complexType(g.defs.ComplexType(q))
+	}
+}
+
+// typeRef returns the schema of the type named name: a reference to its
+// component, or the schema of a built-in type.
+func (g *generator) typeRef(name wsdl.QName) *Schema {
+	if wsdl.IsBuiltin(name) || name.IsZero() {
+		return builtin(name.Local)
+	}
+	if component, ok := g.names[name]; ok {
+		return &Schema{Ref: "#/components/schemas/" + component}
+	}
+
+	return &Schema{}
+}
+
+// elementType returns the schema of the content of e.
+func (g *generator) elementType(e *wsdl.Element) *Schema {
+	var schema *Schema
+	switch {
+	case e.ComplexType != nil:
+		schema = g.complexType(e.ComplexType)
+	case e.SimpleType != nil:
+		schema = g.simpleType(e.SimpleType)
+	default:
+		schema = g.typeRef(e.Type)
+	}
+	if e.Documentation != "" && schema.Ref == "" {
+		schema.Description = e.Documentation
+	}
+
+	return schema
+}
+
+func (g *generator) simpleType(st *wsdl.SimpleType) *Schema {
+	// The restrictions of simple types deriving from others are merged,
+	// since references have no siblings in OpenAPI 3.0.
+	schema := &Schema{Description: st.Documentation}
+	var enum, patterns []string
+	for current, depth := st, 0; depth < 16; depth++ {
+		if enum == nil {
+			enum = current.Enumeration
+		}
+		if patterns == nil {
+			patterns = current.Patterns
+		}
+		if wsdl.IsBuiltin(current.Base) || g.defs.SimpleType(current.Base) == nil {
+			base := builtin(current.Base.Local)
+			schema.Type, schema.Format = base.Type, base.Format
+			schema.Minimum, schema.Maximum, schema.Pattern = base.Minimum, base.Maximum, base.Pattern
+			break
+		}
+		current = g.defs.SimpleType(current.Base)
+	}
+	schema.Enum = enum
+	if len(patterns) == 1 {
+		// XML schema patterns match whole values.
+		schema.Pattern = "^(?:" + patterns[0] + ")$"
+	}
+
+	return schema
+}
+
+func (g *generator) complexType(ct *wsdl.ComplexType) *Schema {
+	if ct.SimpleContent {
+		text := g.typeRef(ct.Base)
+		if len(ct.Attributes) == 0 {
+			if text.Ref == "" {
+				text.Description = ct.Documentation
+			}
+			return text
+		}
+		object := &Schema{Type: "object", Properties: map[string]*Schema{"#text": text}}
+		g.attributes(object, ct)
+		return &Schema{Description: ct.Documentation, AnyOf: []*Schema{g.typeRef(ct.Base), object}}
+	}
+
+	if ct.Abstract {
+		return &Schema{Description: ct.Documentation, AnyOf: g.concreteTypes(ct)}
+	}
+
+	schema := &Schema{Type: "object", Description: ct.Documentation, Properties: make(map[string]*Schema)}
+	if g.derivesFromAbstract(ct) {
+		schema.Properties["@type"] = &Schema{Type: "string", Enum: []string{ct.Name}}
+		schema.Required = append(schema.Required, "@type")
+	}
+	for _, e := range g.content(ct) {
+		e = g.resolve(e)
+		property := g.elementType(e)
+		if e.Nillable {
+			property = nullable(property)
+		}
+		if e.MaxOccurs == wsdl.Unbounded || e.MaxOccurs > 1 {
+			property = &Schema{Type: "array", Items: property}
+			if e.MinOccurs > 1 {
+				property.MinItems = e.MinOccurs
+			}
+			if e.MaxOccurs > 1 {
+				property.MaxItems = e.MaxOccurs
+			}
+		}
+		schema.Properties[e.Name] = property
+		if e.MinOccurs > 0 && !containsString(schema.Required, e.Name) {
+			schema.Required = append(schema.Required, e.Name)
+		}
+	}
+	g.attributes(schema, ct)
+	if len(schema.Properties) == 0 {
+		schema.Properties = nil
+	}
+
+	return schema
+}
+
+// concreteTypes returns references to the types deriving from the abstract
+// type ct that are not abstract themselves.
+func (g *generator) concreteTypes(ct *wsdl.ComplexType) []*Schema {
+	var refs []*Schema
+	for _, schema := range g.defs.Schemas {
+		for _, derived := range schema.ComplexTypes {
+			if derived.Abstract || !g.derives(derived, ct) {
+				continue
+			}
+			refs = append(refs, g.typeRef(wsdl.QName{Space: schema.TargetNamespace, Local: derived.Name}))
+		}
+	}
+
+	return refs
+}
+
+// derives reports whether ct derives from base, directly or not.
+func (g *generator) derives(ct, base *wsdl.ComplexType) bool {
+	for t, depth := g.defs.ComplexType(ct.Base), 0; t != nil && depth < 16; t, depth = g.defs.ComplexType(t.Base), depth+1 {
+		if t == base {
+			return true
+		}
+	}
+
+	return false
+}
+
+// derivesFromAbstract reports whether ct derives from an abstract type, in
+// place of which it is named by an xsi:type.
+func (g *generator) derivesFromAbstract(ct *wsdl.ComplexType) bool {
+	for t, depth := g.defs.ComplexType(ct.Base), 0; t != nil && depth < 16; t, depth = g.defs.ComplexType(t.Base), depth+1 {
+		if t.Abstract {
+			return true
+		}
+	}
+
+	return false
+}
+
+// attributes adds the attributes of ct to the properties of schema.
+func (g *generator) attributes(schema *Schema, ct *wsdl.ComplexType) {
+	for _, a := range ct.Attributes {
+		name := a.Name
+		if name == "" {
+			name = a.Ref.Local
+		}
+		schema.Properties["@"+name] = g.typeRef(a.Type)
+		if a.Use == "required" {
+			schema.Required = append(schema.Required, "@"+name)
+		}
+	}
+	sort.Strings(schema.Required)
+}
+
+// content returns the elements of ct, those inherited from its base first.
+func (g *generator) content(ct *wsdl.ComplexType) []*wsdl.Element {
+	var elements []*wsdl.Element
+	if !ct.Base.IsZero() {
+		if base := g.defs.ComplexType(ct.Base); base != nil && base != ct {
+			elements = append(elements, g.content(base)...)
+		}
+	}
+
+	return append(elements, ct.Elements...)
+}
+
+// resolve returns the declaration e refers to, keeping its occurrences.
+func (g *generator) resolve(e *wsdl.Element) *wsdl.Element {
+	if e.Ref.IsZero() {
+		return e
+	}
+	ref := g.defs.Element(e.Ref)
+	if ref == nil {
+		return e
+	}
+	resolved := *ref
+	resolved.MinOccurs, resolved.MaxOccurs = e.MinOccurs, e.MaxOccurs
+
+	return &resolved
+}
+
+// nullable marks schema as nullable. References have no siblings in
+// OpenAPI 3.0, so they are wrapped.
+func nullable(schema *Schema) *Schema {
+	if schema.Ref != "" {
+		return &Schema{Nullable: true, AnyOf: []*Schema{schema}}
+	}
+	schema.Nullable = true
+
+	return schema
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// builtin returns the schema of the built-in type named local, as the
+// gateway writes its values.
+func builtin(local string) *Schema {
+	zero, one := 0, 1
+	switch local {
+	case "boolean":
+		return &Schema{Type: "boolean"}
+	case "byte", "short", "int", "unsignedByte", "unsignedShort":
+		schema := &Schema{Type: "integer", Format: "int32"}
+		if strings.HasPrefix(local, "unsigned") {
+			schema.Minimum = &zero
+		}
+		return schema
+	case "long", "unsignedInt":
+		schema := &Schema{Type: "integer", Format: "int64"}
+		if local == "unsignedInt" {
+			schema.Minimum = &zero
+		}
+		return schema
+	case "integer", "unsignedLong", "nonNegativeInteger", "nonPositiveInteger":
+		schema := &Schema{Type: "integer"}
+		switch local {
+		case "nonPositiveInteger":
+			schema.Maximum = &zero
+		case "unsignedLong", "nonNegativeInteger":
+			schema.Minimum = &zero
+		}
+		return schema
+	case "positiveInteger":
+		return &Schema{Type: "integer", Minimum: &one}
+	case "negativeInteger":
+		minusOne := -1
+		return &Schema{Type: "integer", Maximum: &minusOne}
+	case "float", "double":
+		return &Schema{Type: "number", Format: local}
+	case "decimal":
+		return &Schema{Type: "number"}
+	case "dateTime":
+		return &Schema{Type: "string", Format: "date-time"}
+	case "date":
+		return &Schema{Type: "string", Format: "date"}
+	case "time", "duration":
+		return &Schema{Type: "string", Format: local}
+	case "base64Binary":
+		return &Schema{Type: "string", Format: "byte"}
+	case "hexBinary":
+		return &Schema{Type: "string", Pattern: "^(?:[0-9a-fA-F]{2})*$"}
+	case "anyType", "":
+		return &Schema{}
+	}
+
+	return &Schema{Type: "string"}
+}
@@ -0,0 +1,116 @@
+package openapi
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/luhonghai/wsdl-example/pkg/wsdl"
+	"github.com/magiconair/properties/assert"
+)
+
+func load(t *testing.T, path string) *wsdl.Definitions {
+	catalog, err := wsdl.ReadCatalog("../schemas/catalog.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defs, err := (&wsdl.Loader{Catalog: catalog}).Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return defs
+}
+
+func marshal(t *testing.T, v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(data)
+}
+
+func TestGenerateCalculator(t *testing.T) {
+	doc, err := Generate(load(t, "../calculator.xml"), &Options{ServerURL: "/calculator"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, doc.Info.Title, "Calculator")
+	assert.Equal(t, len(doc.Paths), 4)
+	add := doc.Paths["/Add"].Post
+	assert.Equal(t, add.Summary, "Adds two integers.")
+	assert.Equal(t, marshal(t, add.RequestBody.Content["application/json"].Schema),
+		`{"type":"object","properties":{"intA":{"type":"integer","format":"int32"},"intB":{"type":"integer","format":"int32"}},"required":["intA","intB"]}`)
+	assert.Equal(t, marshal(t, add.Responses["200"].Content["application/json"].Schema),
+		`{"type":"object","properties":{"AddResult":{"type":"integer","format":"int32"}},"required":["AddResult"]}`)
+	assert.Equal(t, add.Responses["400"].Ref, "#/components/responses/Error")
+}
+
+func TestGenerateS3Types(t *testing.T) {
+	doc, err := Generate(load(t, "../AmazonS3.wsdl"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	schemas := doc.Components.Schemas
+
+	assert.Equal(t, marshal(t, schemas["StorageClass"]), `{"type":"string","enum":["STANDARD","REDUCED_REDUNDANCY","GLACIER","UNKNOWN"]}`)
+	assert.Equal(t, marshal(t, schemas["ListEntry"].Properties["LastModified"]), `{"type":"string","format":"date-time"}`)
+	assert.Equal(t, marshal(t, schemas["ListBucketResult"].Properties["Contents"]), `{"type":"array","items":{"$ref":"#/components/schemas/ListEntry"}}`)
+	assert.Equal(t, schemas["ListBucketResult"].Required, []string{"IsTruncated", "Marker", "MaxKeys", "Name", "Prefix"})
+	// Derived types carry the elements of their base.
+	if schemas["CanonicalUser"].Properties["ID"] == nil {
+		t.Error("CanonicalUser lacks ID")
+	}
+	// Abstract types stand for the concrete types deriving from them,
+	// which are named by @type.
+	assert.Equal(t, marshal(t, schemas["Grantee"]), `{"anyOf":[{"$ref":"#/components/schemas/AmazonCustomerByEmail"},`+
+		`{"$ref":"#/components/schemas/CanonicalUser"},{"$ref":"#/components/schemas/Group"}]}`)
+	assert.Equal(t, marshal(t, schemas["Group"].Properties["@type"]), `{"type":"string","enum":["Group"]}`)
+	assert.Equal(t, schemas["Group"].Required, []string{"@type", "URI"})
+}
+
+// TestGenerateReferences checks that every reference of the documents
+// generated for the bundled contracts resolves.
+func TestGenerateReferences(t *testing.T) {
+	for _, path := range []string{"../calculator.xml", "../dilbert.xml", "../AmazonS3.wsdl"} {
+		doc, err := Generate(load(t, path), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var tree interface{}
+		if err := json.Unmarshal([]byte(marshal(t, doc)), &tree); err != nil {
+			t.Fatal(err)
+		}
+
+		var refs []string
+		var walk func(v interface{})
+		walk = func(v interface{}) {
+			switch v := v.(type) {
+			case map[string]interface{}:
+				for key, child := range v {
+					if ref, ok := child.(string); ok && key == "$ref" {
+						refs = append(refs, ref)
+					}
+					walk(child)
+				}
+			case []interface{}:
+				for _, child := range v {
+					walk(child)
+				}
+			}
+		}
+		walk(tree)
+
+		for _, ref := range refs {
+			name := ref[strings.LastIndex(ref, "/")+1:]
+			switch {
+			case strings.HasPrefix(ref, "#/components/schemas/") && doc.Components.Schemas[name] != nil:
+			case strings.HasPrefix(ref, "#/components/responses/") && doc.Components.Responses[name] != nil:
+			default:
+				t.Errorf("%s: unresolved reference %s", path, ref)
+			}
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package soap
+
+import (
+	"encoding/xml"
+	"fmt"
+)
+
+// MarshalArray writes items as a SOAP encoded array of itemType, each in an
+// element named item. Generated array types implement xml.Marshaler with it.
+func MarshalArray(e *xml.Encoder, start xml.StartElement, itemType xml.Name, items []interface{}) error {
+	prefix := "ns"
+	if itemType.Space == "http://www.w3.org/2001/XMLSchema" {
+		prefix = "xsd"
+	}
+	start.Attr = append(start.Attr,
+		xml.Attr{Name: xml.Name{Local: "xmlns:soapenc"}, Value: EncodingNamespace},
+		xml.Attr{Name: xml.Name{Local: "xmlns:xsi"}, Value: instanceNamespace},
+		xml.Attr{Name: xml.Name{Local: "xmlns:" + prefix}, Value: itemType.Space},
+		xml.Attr{Name: xml.Name{Local: "xsi:type"}, Value: "soapenc:Array"},
+		xml.Attr{Name: xml.Name{Local: "soapenc:arrayType"}, Value: fmt.Sprintf("%s:%s[%d]", prefix, itemType.Local, len(items))},
+	)
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+	for _, item := range items {
+		if err := e.EncodeElement(item, xml.StartElement{Name: xml.Name{Local: "item"}}); err != nil {
+			return err
+		}
+	}
+
+	return e.EncodeToken(start.End())
+}
+
+// UnmarshalArray reads the items of a SOAP encoded array, whatever their
+// element names, calling item with the start of each. item decodes it with
+// d. Generated array types implement xml.Unmarshaler with it.
+func UnmarshalArray(d *xml.Decoder, item func(start xml.StartElement) error) error {
+	for {
+		token, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			if err := item(t); err != nil {
+				return err
+			}
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
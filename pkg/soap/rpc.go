@@ -0,0 +1,327 @@
+package soap
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"log"
+	"reflect"
+	"strings"
+)
+
+// Namespaces of the SOAP 1.1 encoding and of the schema instance
+// attributes it relies on.
+const (
+	EncodingNamespace = "http://schemas.xmlsoap.org/soap/encoding/"
+	instanceNamespace = "http://www.w3.org/2001/XMLSchema-instance"
+	schemaNamespace   = "http://www.w3.org/2001/XMLSchema"
+)
+
+// RPC tells CallRPC how an rpc style operation is carried. The request and
+// the response are wrapped in elements named after the operation, holding
+// one accessor element per message part.
+type RPC struct {
+	// Operation names the request wrapper. The response wrapper is matched
+	// whatever its name, which is only conventionally the operation name
+	// followed by "Response".
+	Operation string
+	// Namespace qualifies the wrappers; the accessors are unqualified.
+	Namespace string
+	// Encoded is set for use="encoded" bindings. Their responses may hold
+	// multi-reference values, which are resolved before decoding.
+	Encoded bool
+	// RequestTypes and ResponseTypes map the accessors of encoded messages
+	// holding values of XML schema built-in types to the local names of
+	// those types, as in "int". The accessors are sent with an xsi:type,
+	// which many encoded services require.
+	RequestTypes  map[string]string
+	ResponseTypes map[string]string
+}
+
+// CallRPC calls an rpc style operation. request and response are structs
+// with a field per message part. A SOAP fault in the reply is returned as a
+// *Fault.
+func (s *Client) CallRPC(soapAction string, rpc *RPC, request, response interface{}) error {
+	buffer := new(bytes.Buffer)
+	if err := writeRPC(buffer, EnvelopeNamespace, rpc, rpc.Operation, request, rpc.RequestTypes); err != nil {
+		return err
+	}
+
+	rawbody, err := s.post(soapAction, buffer)
+	if err != nil {
+		return err
+	}
+	if len(rawbody) == 0 {
+		log.Println("empty response")
+		return nil
+	}
+
+	return readRPC(rawbody, response)
+}
+
+// writeRPC writes the envelope in namespace of an rpc style message, value
+// wrapped in an element called name. The envelope and the wrapper use prefixes so that no
+// default namespace applies to the unqualified accessors. Encoded accessors
+// named in types carry their xsi:type.
+func writeRPC(buffer *bytes.Buffer, namespace string, rpc *RPC, name string, value interface{}, types map[string]string) error {
+	buffer.WriteString(`<soap:Envelope xmlns:soap="` + namespace + `"`)
+	if rpc.Encoded {
+		buffer.WriteString(` xmlns:soapenc="` + EncodingNamespace + `" xmlns:xsi="` + instanceNamespace + `"`)
+		if len(types) > 0 {
+			buffer.WriteString(` xmlns:xsd="` + schemaNamespace + `"`)
+			value = &typedAccessors{value: value, types: types}
+		}
+	}
+	buffer.WriteString(`><soap:Body>`)
+
+	start := xml.StartElement{Name: xml.Name{Local: name}}
+	if rpc.Namespace != "" {
+		start.Name.Local = "rpc:" + name
+		start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: "xmlns:rpc"}, Value: rpc.Namespace})
+	}
+	if rpc.Encoded {
+		start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: "soap:encodingStyle"}, Value: EncodingNamespace})
+	}
+	encoder := xml.NewEncoder(buffer)
+	if err := encoder.EncodeElement(value, start); err != nil {
+		return err
+	}
+	if err := encoder.Flush(); err != nil {
+		return err
+	}
+
+	buffer.WriteString(`</soap:Body></soap:Envelope>`)
+
+	return nil
+}
+
+// typedAccessors encodes a struct holding a field per accessor, adding
+// xsi:type="xsd:<type>" to the accessors named in types. Values of other
+// kinds, and structs with fields that are not plain elements, are encoded as
+// they are.
+type typedAccessors struct {
+	value interface{}
+	types map[string]string
+}
+
+func (a *typedAccessors) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	v := reflect.Indirect(reflect.ValueOf(a.value))
+	if v.Kind() != reflect.Struct {
+		return e.EncodeElement(a.value, start)
+	}
+
+	type accessor struct {
+		start xml.StartElement
+		value reflect.Value
+	}
+	var accessors []accessor
+	for i := 0; i < v.NumField(); i++ {
+		f := v.Type().Field(i)
+		if f.PkgPath != "" || f.Name == "XMLName" {
+			continue
+		}
+		options := strings.Split(f.Tag.Get("xml"), ",")
+		name := options[0]
+		if name == "-" {
+			continue
+		}
+		if i := strings.LastIndex(name, " "); i >= 0 {
+			name = name[i+1:]
+		}
+		if name == "" {
+			name = f.Name
+		}
+		omitEmpty := false
+		for _, option := range options[1:] {
+			if option != "omitempty" {
+				return e.EncodeElement(a.value, start)
+			}
+			omitEmpty = true
+		}
+		if omitEmpty && v.Field(i).IsZero() {
+			continue
+		}
+
+		el := xml.StartElement{Name: xml.Name{Local: name}}
+		if t := a.types[name]; t != "" {
+			el.Attr = append(el.Attr, xml.Attr{Name: xml.Name{Local: "xsi:type"}, Value: "xsd:" + t})
+		}
+		accessors = append(accessors, accessor{el, v.Field(i)})
+	}
+
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+	for _, accessor := range accessors {
+		if err := e.EncodeElement(accessor.value.Interface(), accessor.start); err != nil {
+			return err
+		}
+	}
+
+	return e.EncodeToken(start.End())
+}
+
+// readRPC decodes the rpc style message in a SOAP 1.1 or SOAP 1.2 envelope
+// into value. A SOAP 1.1 fault in place of the message is returned as a
+// *Fault.
+func readRPC(rawbody []byte, value interface{}) error {
+	envelope := new(element)
+	if err := xml.Unmarshal(rawbody, envelope); err != nil {
+		return err
+	}
+	namespace := EnvelopeNamespace
+	if envelope.XMLName.Space == Envelope12Namespace {
+		namespace = Envelope12Namespace
+	}
+	body := envelope.child(namespace, "Body")
+	if body == nil || len(body.Children) == 0 {
+		return xml.UnmarshalError("SOAP body is missing or empty")
+	}
+
+	wrapper := body.Children[0]
+	if wrapper.XMLName.Space == EnvelopeNamespace && wrapper.XMLName.Local == "Fault" {
+		fault := new(Fault)
+		if err := decodeElement(wrapper, fault); err != nil {
+			return err
+		}
+		return fault
+	}
+
+	// Multi-reference values are siblings of the wrapper, or nested
+	// anywhere in it, and referred to by href="#id".
+	ids := make(map[string]*element)
+	for _, child := range body.Children {
+		child.collectIDs(ids)
+	}
+	if len(ids) > 0 {
+		if err := wrapper.resolve(ids, make(map[string]bool)); err != nil {
+			return err
+		}
+	}
+
+	return decodeElement(wrapper, value)
+}
+
+// element is an element of a reply kept as is, so that multi-reference
+// values can be inlined before decoding.
+type element struct {
+	XMLName  xml.Name
+	Attrs    []xml.Attr `xml:",any,attr"`
+	Text     string     `xml:",chardata"`
+	Children []*element `xml:",any"`
+}
+
+func (e *element) attr(space, local string) string {
+	for _, attr := range e.Attrs {
+		if attr.Name.Space == space && attr.Name.Local == local {
+			return attr.Value
+		}
+	}
+
+	return ""
+}
+
+func (e *element) child(space, local string) *element {
+	for _, child := range e.Children {
+		if child.XMLName.Space == space && child.XMLName.Local == local {
+			return child
+		}
+	}
+
+	return nil
+}
+
+func (e *element) collectIDs(ids map[string]*element) {
+	if id := e.attr("", "id"); id != "" {
+		ids[id] = e
+	}
+	for _, child := range e.Children {
+		child.collectIDs(ids)
+	}
+}
+
+// resolve replaces the content of the elements below e referring to a
+// multi-reference value by href with the content of that value. resolving
+// holds the ids being resolved, to reject cyclic graphs.
+func (e *element) resolve(ids map[string]*element, resolving map[string]bool) error {
+	for _, child := range e.Children {
+		href := child.attr("", "href")
+		if !strings.HasPrefix(href, "#") {
+			if err := child.resolve(ids, resolving); err != nil {
+				return err
+			}
+			continue
+		}
+
+		id := href[1:]
+		target := ids[id]
+		if target == nil {
+			return fmt.Errorf("soap: no multi-reference value with id %q", id)
+		}
+		if resolving[id] {
+			return fmt.Errorf("soap: multi-reference value %q refers to itself", id)
+		}
+		resolving[id] = true
+		if err := target.resolve(ids, resolving); err != nil {
+			return err
+		}
+		delete(resolving, id)
+
+		attrs := child.Attrs[:0:0]
+		for _, attr := range child.Attrs {
+			if attr.Name.Space != "" || attr.Name.Local != "href" {
+				attrs = append(attrs, attr)
+			}
+		}
+		for _, attr := range target.Attrs {
+			if attr.Name.Space != "" || attr.Name.Local != "id" && attr.Name.Local != "root" {
+				attrs = append(attrs, attr)
+			}
+		}
+		child.Attrs = attrs
+		child.Text = target.Text
+		child.Children = target.Children
+	}
+
+	return nil
+}
+
+// decodeElement decodes e into v, as xml.Unmarshal would decode the
+// element as it was received. Namespace declarations are dropped, since
+// the names of e are resolved already.
+func decodeElement(e *element, v interface{}) error {
+	var buffer bytes.Buffer
+	encoder := xml.NewEncoder(&buffer)
+	if err := e.encode(encoder); err != nil {
+		return err
+	}
+	if err := encoder.Flush(); err != nil {
+		return err
+	}
+
+	return xml.Unmarshal(buffer.Bytes(), v)
+}
+
+func (e *element) encode(encoder *xml.Encoder) error {
+	start := xml.StartElement{Name: e.XMLName}
+	for _, attr := range e.Attrs {
+		if attr.Name.Space != "xmlns" && !(attr.Name.Space == "" && attr.Name.Local == "xmlns") {
+			start.Attr = append(start.Attr, attr)
+		}
+	}
+	if err := encoder.EncodeToken(start); err != nil {
+		return err
+	}
+	if len(e.Children) == 0 {
+		if err := encoder.EncodeToken(xml.CharData(e.Text)); err != nil {
+			return err
+		}
+	}
+	for _, child := range e.Children {
+		if err := child.encode(encoder); err != nil {
+			return err
+		}
+	}
+
+	return encoder.EncodeToken(start.End())
+}
@@ -0,0 +1,334 @@
+package soap
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// Operation is an operation served by a Server. Generated handlers declare
+// one per operation of their port.
+type Operation struct {
+	Name       string
+	SOAPAction string
+	// Element names the element in the body of requests: the request
+	// document, or the wrapper of rpc style operations.
+	Element xml.Name
+	// RPC is set for rpc style operations. Responses are wrapped in an
+	// element named after the operation followed by "Response".
+	RPC *RPC
+	// NewRequest returns the value a request is decoded into.
+	NewRequest func() interface{}
+	// Handle serves a request. An error is sent as a fault: a *Fault as it
+	// is, others as server faults, except for an *HTTPError which is sent
+	// as a plain HTTP error. A RawXML response is written as it is.
+	Handle func(ctx context.Context, request interface{}) (interface{}, error)
+}
+
+// RawXML is an element written into the body of a response as it is.
+type RawXML []byte
+
+// HTTPError is an error a handler returns to answer with an HTTP error
+// rather than with a fault.
+type HTTPError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *HTTPError) Error() string {
+	if e.Message == "" {
+		return http.StatusText(e.StatusCode)
+	}
+
+	return e.Message
+}
+
+// Server is an http.Handler serving the operations of a port over SOAP 1.1
+// and SOAP 1.2, answering each request in the version it came in. Requests
+// are dispatched on their action, taken from the SOAPAction header or from
+// the action parameter of a SOAP 1.2 Content-Type, or on the element in
+// their body when the action is missing or shared.
+type Server struct {
+	wsdl       []byte
+	operations []*Operation
+}
+
+// NewServer returns a server for operations. The WSDL document wsdl, when
+// not nil, is served to GET requests for ?wsdl.
+func NewServer(wsdl []byte, operations ...*Operation) *Server {
+	return &Server{
+		wsdl:       wsdl,
+		operations: operations,
+	}
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+	case http.MethodGet:
+		if _, ok := r.URL.Query()["wsdl"]; ok && s.wsdl != nil {
+			w.Header().Set("Content-Type", "text/xml; charset=\"utf-8\"")
+			w.Write(s.wsdl)
+			return
+		}
+		http.NotFound(w, r)
+		return
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		writeFault(w, EnvelopeNamespace, &Fault{Code: "soap:Client", String: err.Error()})
+		return
+	}
+	namespace, element, err := bodyElement(body)
+	if err != nil {
+		writeFault(w, namespace, &Fault{Code: "soap:Client", String: err.Error()})
+		return
+	}
+	soapAction := strings.Trim(r.Header.Get("SOAPAction"), `"`)
+	if namespace == Envelope12Namespace {
+		_, params, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		soapAction = params["action"]
+	}
+	op, err := s.operation(soapAction, element)
+	if err != nil {
+		writeFault(w, namespace, &Fault{Code: "soap:Client", String: err.Error()})
+		return
+	}
+
+	request := op.NewRequest()
+	if op.RPC != nil {
+		err = readRPC(body, request)
+	} else {
+		err = decodeBody(body, request)
+	}
+	if err != nil {
+		writeFault(w, namespace, &Fault{Code: "soap:Client", String: fmt.Sprintf("decoding %s request: %v", op.Name, err)})
+		return
+	}
+
+	response, err := op.Handle(r.Context(), request)
+	if err != nil {
+		var httpErr *HTTPError
+		if errors.As(err, &httpErr) {
+			http.Error(w, httpErr.Error(), httpErr.StatusCode)
+			return
+		}
+		var fault *Fault
+		if !errors.As(err, &fault) {
+			fault = &Fault{Code: "soap:Server", String: err.Error()}
+		}
+		writeFault(w, namespace, fault)
+		return
+	}
+
+	buffer := new(bytes.Buffer)
+	if op.RPC != nil {
+		err = writeRPC(buffer, namespace, op.RPC, op.Name+"Response", response, op.RPC.ResponseTypes)
+	} else {
+		err = writeEnvelope(buffer, namespace, response, xml.StartElement{})
+	}
+	if err != nil {
+		writeFault(w, namespace, &Fault{Code: "soap:Server", String: fmt.Sprintf("encoding %s response: %v", op.Name, err)})
+		return
+	}
+	w.Header().Set("Content-Type", contentType(namespace))
+	w.Write(buffer.Bytes())
+}
+
+// operation returns the operation of a request with soapAction and the body
+// element name, which is empty for an empty body.
+func (s *Server) operation(soapAction string, name xml.Name) (*Operation, error) {
+	if soapAction != "" {
+		var found []*Operation
+		for _, op := range s.operations {
+			if op.SOAPAction == soapAction {
+				found = append(found, op)
+			}
+		}
+		if len(found) == 1 {
+			return found[0], nil
+		}
+	}
+
+	if name.Local == "" {
+		return nil, errors.New("no element in the SOAP body")
+	}
+	for _, op := range s.operations {
+		if op.Element == name {
+			return op, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no operation for SOAPAction %q and body element {%s}%s", soapAction, name.Space, name.Local)
+}
+
+// bodyElement returns the namespace of an envelope, SOAP 1.1 when it is
+// not one, and the name of the first element in its body, if any.
+func bodyElement(envelope []byte) (string, xml.Name, error) {
+	namespace, start, err := bodyStart(xml.NewDecoder(bytes.NewReader(envelope)))
+	if start == nil {
+		return namespace, xml.Name{}, err
+	}
+
+	return namespace, start.Name, err
+}
+
+// decodeBody decodes the first element in the body of an envelope into
+// value. An empty body leaves value as it is.
+func decodeBody(envelope []byte, value interface{}) error {
+	d := xml.NewDecoder(bytes.NewReader(envelope))
+	_, start, err := bodyStart(d)
+	if start == nil {
+		return err
+	}
+
+	return d.DecodeElement(value, start)
+}
+
+// bodyStart advances d to the first element in the body of a SOAP 1.1 or
+// SOAP 1.2 envelope, returning the namespace of the envelope and the
+// element, or nil if the body is empty.
+func bodyStart(d *xml.Decoder) (string, *xml.StartElement, error) {
+	namespace := ""
+	inBody := false
+	for {
+		token, err := d.Token()
+		if err == io.EOF && inBody {
+			return namespace, nil, nil
+		}
+		if err == io.EOF {
+			err = errors.New("no SOAP body")
+		}
+		if err != nil {
+			if namespace == "" {
+				namespace = EnvelopeNamespace
+			}
+			return namespace, nil, err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch {
+			case namespace == "":
+				if t.Name.Local != "Envelope" || t.Name.Space != EnvelopeNamespace && t.Name.Space != Envelope12Namespace {
+					return EnvelopeNamespace, nil, fmt.Errorf("{%s}%s is not a SOAP envelope", t.Name.Space, t.Name.Local)
+				}
+				namespace = t.Name.Space
+			case inBody:
+				return namespace, &t, nil
+			case t.Name.Space == namespace && t.Name.Local == "Body":
+				inBody = true
+			}
+		case xml.EndElement:
+			if inBody {
+				return namespace, nil, nil
+			}
+		}
+	}
+}
+
+// contentType returns the media type of envelopes in namespace.
+func contentType(namespace string) string {
+	if namespace == Envelope12Namespace {
+		return "application/soap+xml; charset=\"utf-8\""
+	}
+
+	return "text/xml; charset=\"utf-8\""
+}
+
+// writeEnvelope writes value in the body of an envelope in namespace, in an
+// element called as start when it has a name. RawXML values are copied as
+// they are.
+func writeEnvelope(buffer *bytes.Buffer, namespace string, value interface{}, start xml.StartElement) error {
+	buffer.WriteString(`<soap:Envelope xmlns:soap="` + namespace + `"><soap:Body>`)
+	if raw, ok := value.(RawXML); ok {
+		buffer.Write(raw)
+		buffer.WriteString(`</soap:Body></soap:Envelope>`)
+		return nil
+	}
+	encoder := xml.NewEncoder(buffer)
+	var err error
+	if start.Name.Local != "" {
+		err = encoder.EncodeElement(value, start)
+	} else {
+		err = encoder.Encode(value)
+	}
+	if err != nil {
+		return err
+	}
+	if err := encoder.Flush(); err != nil {
+		return err
+	}
+	buffer.WriteString(`</soap:Body></soap:Envelope>`)
+
+	return nil
+}
+
+// writeFault answers with fault in an envelope in namespace. SOAP 1.1 fault
+// fields are written unqualified, as SOAP 1.1 wants them, so codes like
+// "soap:Server" use the prefix of the envelope. SOAP 1.2 faults are
+// translated, their client faults answered with status 400.
+func writeFault(w http.ResponseWriter, namespace string, fault *Fault) {
+	var value interface{} = fault
+	status := http.StatusInternalServerError
+	if namespace == Envelope12Namespace {
+		f := newFault12(fault)
+		if f.Code == "soap:Sender" {
+			status = http.StatusBadRequest
+		}
+		value = f
+	}
+
+	buffer := new(bytes.Buffer)
+	if err := writeEnvelope(buffer, namespace, value, xml.StartElement{Name: xml.Name{Local: "soap:Fault"}}); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", contentType(namespace))
+	w.WriteHeader(status)
+	w.Write(buffer.Bytes())
+}
+
+// fault12 is a Fault as SOAP 1.2 words it.
+type fault12 struct {
+	Code   string   `xml:"soap:Code>soap:Value"`
+	Reason reason12 `xml:"soap:Reason>soap:Text"`
+	Role   string   `xml:"soap:Role,omitempty"`
+	Detail string   `xml:"soap:Detail,omitempty"`
+}
+
+type reason12 struct {
+	Lang string `xml:"xml:lang,attr"`
+	Text string `xml:",chardata"`
+}
+
+// newFault12 translates fault to SOAP 1.2. Client faults become Sender
+// faults, and those of codes SOAP 1.2 does not know Receiver faults.
+func newFault12(fault *Fault) *fault12 {
+	code := fault.Code[strings.Index(fault.Code, ":")+1:]
+	switch {
+	case code == "Client" || strings.HasPrefix(code, "Client."):
+		code = "Sender"
+	case code == "VersionMismatch" || code == "MustUnderstand":
+	default:
+		code = "Receiver"
+	}
+
+	return &fault12{
+		Code:   "soap:" + code,
+		Reason: reason12{Lang: "en", Text: fault.String},
+		Role:   fault.Actor,
+		Detail: fault.Detail,
+	}
+}
@@ -0,0 +1,170 @@
+package soap
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/magiconair/properties/assert"
+)
+
+func newTestServer(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(NewServer([]byte("<definitions/>"),
+		&Operation{
+			Name:       "Echo",
+			SOAPAction: "urn:test/Echo",
+			Element:    xml.Name{Space: "urn:test", Local: "Echo"},
+			NewRequest: func() interface{} { return new(echo) },
+			Handle: func(ctx context.Context, request interface{}) (interface{}, error) {
+				text := request.(*echo).Text
+				switch text {
+				case "fault":
+					return nil, &Fault{Code: "soap:Client", String: "no faults please"}
+				case "error":
+					return nil, errors.New("boom")
+				case "busy":
+					return nil, &HTTPError{StatusCode: http.StatusServiceUnavailable}
+				case "raw":
+					return RawXML(`<EchoResponse xmlns="urn:test"><Text>as is</Text></EchoResponse>`), nil
+				}
+				return &echoResponse{Text: text}, nil
+			},
+		},
+		&Operation{
+			Name:       "Sum",
+			Element:    xml.Name{Space: "urn:math", Local: "Sum"},
+			RPC:        &RPC{Operation: "Sum", Namespace: "urn:math"},
+			NewRequest: func() interface{} { return new(sum) },
+			Handle: func(ctx context.Context, request interface{}) (interface{}, error) {
+				return &sumResponse{Result: request.(*sum).A + request.(*sum).B}, nil
+			},
+		},
+	))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestServer(t *testing.T) {
+	server := newTestServer(t)
+	client := NewClient(server.URL, false, nil)
+
+	response := new(echoResponse)
+	if err := client.Call("urn:test/Echo", &echo{Text: "hello"}, response); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, response.Text, "hello")
+
+	// Without an action the body element selects the operation.
+	response = new(echoResponse)
+	if err := client.Call("", &echo{Text: "again"}, response); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, response.Text, "again")
+
+	response = new(echoResponse)
+	if err := client.Call("urn:test/Echo", &echo{Text: "raw"}, response); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, response.Text, "as is")
+
+	sumResult := new(sumResponse)
+	if err := client.CallRPC("", &RPC{Operation: "Sum", Namespace: "urn:math"}, &sum{A: 2, B: 3}, sumResult); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, sumResult.Result, 5)
+}
+
+func TestServerFaults(t *testing.T) {
+	server := newTestServer(t)
+	client := NewClient(server.URL, false, nil)
+
+	err := client.Call("urn:test/Echo", &echo{Text: "fault"}, new(echoResponse))
+	assert.Equal(t, err, error(&Fault{XMLName: xml.Name{Space: EnvelopeNamespace, Local: "Fault"}, Code: "soap:Client", String: "no faults please"}))
+
+	err = client.Call("urn:test/Echo", &echo{Text: "error"}, new(echoResponse))
+	assert.Equal(t, err.(*Fault).Code, "soap:Server")
+	assert.Equal(t, err.Error(), "boom")
+
+	err = client.Call("urn:test/Echo", &echo{Text: "busy"}, new(echoResponse))
+	assert.Equal(t, err, error(&HTTPError{StatusCode: http.StatusServiceUnavailable, Message: "Service Unavailable"}))
+
+	err = client.Call("urn:test/Other", &echoResponse{}, new(echoResponse))
+	assert.Equal(t, err.Error(), `no operation for SOAPAction "urn:test/Other" and body element {urn:test}EchoResponse`)
+}
+
+func TestServerWSDL(t *testing.T) {
+	server := newTestServer(t)
+
+	res, err := http.Get(server.URL + "?wsdl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	assert.Equal(t, res.StatusCode, http.StatusOK)
+	assert.Equal(t, string(body), "<definitions/>")
+
+	res, err = http.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	assert.Equal(t, res.StatusCode, http.StatusNotFound)
+
+	res, err = http.Post(server.URL, "text/xml", strings.NewReader("<Envelope"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ = ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	assert.Equal(t, res.StatusCode, http.StatusInternalServerError)
+	if !strings.Contains(string(body), "<faultcode>soap:Client</faultcode>") {
+		t.Error("Unexpected reply", string(body))
+	}
+}
+
+func TestServerSOAP12(t *testing.T) {
+	server := newTestServer(t)
+
+	post := func(action, body string) (*http.Response, string) {
+		res, err := http.Post(server.URL, `application/soap+xml; charset=utf-8; action="`+action+`"`,
+			strings.NewReader(`<env:Envelope xmlns:env="`+Envelope12Namespace+`"><env:Body>`+body+`</env:Body></env:Envelope>`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := ioutil.ReadAll(res.Body)
+		res.Body.Close()
+		return res, string(data)
+	}
+
+	res, body := post("urn:test/Echo", `<Echo xmlns="urn:test"><Text>hello</Text></Echo>`)
+	assert.Equal(t, res.StatusCode, http.StatusOK)
+	assert.Equal(t, res.Header.Get("Content-Type"), `application/soap+xml; charset="utf-8"`)
+	assert.Equal(t, body, `<soap:Envelope xmlns:soap="`+Envelope12Namespace+`"><soap:Body>`+
+		`<EchoResponse xmlns="urn:test"><Text>hello</Text></EchoResponse></soap:Body></soap:Envelope>`)
+
+	res, body = post("", `<m:Sum xmlns:m="urn:math"><a>2</a><b>3</b></m:Sum>`)
+	assert.Equal(t, res.StatusCode, http.StatusOK)
+	if !strings.Contains(body, `<soap:Envelope xmlns:soap="`+Envelope12Namespace+`">`) || !strings.Contains(body, "<result>5</result>") {
+		t.Error("Unexpected reply", body)
+	}
+
+	res, body = post("urn:test/Echo", `<Echo xmlns="urn:test"><Text>fault</Text></Echo>`)
+	assert.Equal(t, res.StatusCode, http.StatusBadRequest)
+	assert.Equal(t, body, `<soap:Envelope xmlns:soap="`+Envelope12Namespace+`"><soap:Body><soap:Fault>`+
+		`<soap:Code><soap:Value>soap:Sender</soap:Value></soap:Code>`+
+		`<soap:Reason><soap:Text xml:lang="en">no faults please</soap:Text></soap:Reason>`+
+		`</soap:Fault></soap:Body></soap:Envelope>`)
+
+	res, body = post("urn:test/Echo", `<Echo xmlns="urn:test"><Text>error</Text></Echo>`)
+	assert.Equal(t, res.StatusCode, http.StatusInternalServerError)
+	if !strings.Contains(body, "<soap:Value>soap:Receiver</soap:Value>") {
+		t.Error("Unexpected reply", body)
+	}
+}
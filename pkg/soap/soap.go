@@ -0,0 +1,259 @@
+// Package soap is the SOAP runtime shared by the packages generated from the
+// WSDL contracts in this repository. Clients speak SOAP 1.1; servers answer
+// SOAP 1.1 and SOAP 1.2.
+package soap
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/xml"
+	"io/ioutil"
+	"log"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Namespaces of the SOAP 1.1 and SOAP 1.2 envelopes.
+const (
+	EnvelopeNamespace   = "http://schemas.xmlsoap.org/soap/envelope/"
+	Envelope12Namespace = "http://www.w3.org/2003/05/soap-envelope"
+)
+
+var timeout = time.Duration(30 * time.Second)
+
+func dialTimeout(network, addr string) (net.Conn, error) {
+	return net.DialTimeout(network, addr, timeout)
+}
+
+type Envelope struct {
+	XMLName xml.Name `xml:"http://schemas.xmlsoap.org/soap/envelope/ Envelope"`
+
+	Header *Header `xml:",omitempty"`
+	Body   Body
+}
+
+type Header struct {
+	XMLName xml.Name `xml:"http://schemas.xmlsoap.org/soap/envelope/ Header"`
+
+	Header interface{}
+}
+
+type Body struct {
+	XMLName xml.Name `xml:"http://schemas.xmlsoap.org/soap/envelope/ Body"`
+
+	Fault   *Fault      `xml:",omitempty"`
+	Content interface{} `xml:",omitempty"`
+}
+
+type Fault struct {
+	XMLName xml.Name `xml:"http://schemas.xmlsoap.org/soap/envelope/ Fault"`
+
+	Code   string `xml:"faultcode,omitempty"`
+	String string `xml:"faultstring,omitempty"`
+	Actor  string `xml:"faultactor,omitempty"`
+	Detail string `xml:"detail,omitempty"`
+}
+
+type BasicAuth struct {
+	Login    string
+	Password string
+}
+
+type Client struct {
+	url       string
+	tls       bool
+	auth      *BasicAuth
+	validator Validator
+	transport http.RoundTripper
+}
+
+// Validator checks the envelopes a Client sends and receives, typically
+// against the schema of the contract.
+type Validator interface {
+	Validate(envelope []byte) error
+}
+
+func (b *Body) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	if b.Content == nil {
+		return xml.UnmarshalError("Content must be a pointer to a struct")
+	}
+
+	var (
+		token    xml.Token
+		err      error
+		consumed bool
+	)
+
+Loop:
+	for {
+		if token, err = d.Token(); err != nil {
+			return err
+		}
+
+		if token == nil {
+			break
+		}
+
+		switch se := token.(type) {
+		case xml.StartElement:
+			if consumed {
+				return xml.UnmarshalError("Found multiple elements inside SOAP body; not wrapped-document/literal WS-I compliant")
+			} else if se.Name.Space == EnvelopeNamespace && se.Name.Local == "Fault" {
+				b.Fault = &Fault{}
+				b.Content = nil
+
+				err = d.DecodeElement(b.Fault, &se)
+				if err != nil {
+					return err
+				}
+
+				consumed = true
+			} else {
+				if err = d.DecodeElement(b.Content, &se); err != nil {
+					return err
+				}
+
+				consumed = true
+			}
+		case xml.EndElement:
+			break Loop
+		}
+	}
+
+	return nil
+}
+
+func (f *Fault) Error() string {
+	return f.String
+}
+
+// NewClient returns a client posting to url. When tls is true certificate
+// verification is skipped.
+func NewClient(url string, tls bool, auth *BasicAuth) *Client {
+	return &Client{
+		url:  url,
+		tls:  tls,
+		auth: auth,
+	}
+}
+
+// SetValidator makes the client check every document style request with v
+// before sending it, and every response before decoding it. Errors of v are returned by
+// Call as they are. A nil v turns validation off.
+func (s *Client) SetValidator(v Validator) {
+	s.validator = v
+}
+
+// SetTransport makes the client send its requests through transport, such
+// as a recorder of the traffic. The tls setting of the client no longer
+// applies then. A nil transport restores the default one.
+func (s *Client) SetTransport(transport http.RoundTripper) {
+	s.transport = transport
+}
+
+// Call sends request wrapped in a SOAP envelope and decodes the body of the
+// reply into response. A SOAP fault in the reply is returned as a *Fault.
+func (s *Client) Call(soapAction string, request, response interface{}) error {
+	envelope := Envelope{}
+
+	envelope.Body.Content = request
+	buffer := new(bytes.Buffer)
+
+	encoder := xml.NewEncoder(buffer)
+	//encoder.Indent("  ", "    ")
+
+	if err := encoder.Encode(envelope); err != nil {
+		return err
+	}
+
+	if err := encoder.Flush(); err != nil {
+		return err
+	}
+
+	//log.Println(buffer.String())
+	if s.validator != nil {
+		if err := s.validator.Validate(buffer.Bytes()); err != nil {
+			return err
+		}
+	}
+
+	rawbody, err := s.post(soapAction, buffer)
+	if err != nil {
+		return err
+	}
+	if len(rawbody) == 0 {
+		log.Println("empty response")
+		return nil
+	}
+
+	//log.Println(string(rawbody))
+	if s.validator != nil {
+		if err := s.validator.Validate(rawbody); err != nil {
+			return err
+		}
+	}
+	respEnvelope := new(Envelope)
+	respEnvelope.Body = Body{Content: response}
+	err = xml.Unmarshal(rawbody, respEnvelope)
+	if err != nil {
+		return err
+	}
+
+	fault := respEnvelope.Body.Fault
+	if fault != nil {
+		return fault
+	}
+
+	return nil
+}
+
+// post sends a SOAP envelope and returns the body of the reply. Replies
+// with an HTTP error status other than that of faults are returned as an
+// *HTTPError.
+func (s *Client) post(soapAction string, envelope *bytes.Buffer) ([]byte, error) {
+	req, err := http.NewRequest("POST", s.url, envelope)
+	if err != nil {
+		return nil, err
+	}
+	if s.auth != nil {
+		req.SetBasicAuth(s.auth.Login, s.auth.Password)
+	}
+
+	req.Header.Add("Content-Type", "text/xml; charset=\"utf-8\"")
+	if soapAction != "" {
+		req.Header.Add("SOAPAction", soapAction)
+	}
+
+	req.Header.Set("User-Agent", "gowsdl/0.1")
+	req.Close = true
+
+	tr := s.transport
+	if tr == nil {
+		tr = &http.Transport{
+			TLSClientConfig: &tls.Config{
+				InsecureSkipVerify: s.tls,
+			},
+			Dial: dialTimeout,
+		}
+	}
+
+	client := &http.Client{Transport: tr}
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	// Faults come with status 500; other errors carry no envelope.
+	if res.StatusCode >= 400 && res.StatusCode != http.StatusInternalServerError {
+		return nil, &HTTPError{StatusCode: res.StatusCode, Message: strings.TrimSpace(string(body))}
+	}
+
+	return body, nil
+}
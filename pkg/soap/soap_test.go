@@ -0,0 +1,226 @@
+package soap
+
+import (
+	"encoding/xml"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/magiconair/properties/assert"
+)
+
+type echo struct {
+	XMLName xml.Name `xml:"urn:test Echo"`
+
+	Text string `xml:"Text,omitempty"`
+}
+
+type echoResponse struct {
+	XMLName xml.Name `xml:"urn:test EchoResponse"`
+
+	Text string `xml:"Text,omitempty"`
+}
+
+func TestCall(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, r.Header.Get("SOAPAction"), "urn:test/Echo")
+		body, _ := ioutil.ReadAll(r.Body)
+		if !strings.Contains(string(body), "<Text>hello</Text>") {
+			t.Error("Unexpected request", string(body))
+		}
+		w.Write([]byte(`<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/"><soap:Body>` +
+			`<EchoResponse xmlns="urn:test"><Text>hello</Text></EchoResponse></soap:Body></soap:Envelope>`))
+	}))
+	defer server.Close()
+
+	response := new(echoResponse)
+	err := NewClient(server.URL, false, nil).Call("urn:test/Echo", &echo{Text: "hello"}, response)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, response.Text, "hello")
+}
+
+func TestCallFault(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(`<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/"><soap:Body><soap:Fault>` +
+			`<faultcode>soap:Server</faultcode><faultstring>boom</faultstring></soap:Fault></soap:Body></soap:Envelope>`))
+	}))
+	defer server.Close()
+
+	err := NewClient(server.URL, false, nil).Call("", &echo{}, new(echoResponse))
+	fault, ok := err.(*Fault)
+	if !ok {
+		t.Fatal("Expected a *Fault, got", err)
+	}
+	assert.Equal(t, fault.Code, "soap:Server")
+	assert.Equal(t, fault.Error(), "boom")
+}
+
+// rejecter fails envelopes holding bad.
+type rejecter struct {
+	bad  string
+	seen int
+}
+
+func (r *rejecter) Validate(envelope []byte) error {
+	r.seen++
+	if strings.Contains(string(envelope), r.bad) {
+		return errors.New("invalid " + r.bad)
+	}
+
+	return nil
+}
+
+func TestCallValidator(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/"><soap:Body>` +
+			`<EchoResponse xmlns="urn:test"><Text>pong</Text></EchoResponse></soap:Body></soap:Envelope>`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, false, nil)
+	validator := &rejecter{bad: "ping"}
+	client.SetValidator(validator)
+
+	err := client.Call("", &echo{Text: "ping"}, new(echoResponse))
+	assert.Equal(t, err.Error(), "invalid ping")
+	assert.Equal(t, requests, 0)
+
+	validator.bad = "pong"
+	err = client.Call("", &echo{Text: "hello"}, new(echoResponse))
+	assert.Equal(t, err.Error(), "invalid pong")
+	assert.Equal(t, requests, 1)
+	assert.Equal(t, validator.seen, 3)
+}
+
+type sum struct {
+	A int `xml:"a"`
+	B int `xml:"b"`
+}
+
+type sumResponse struct {
+	Result int `xml:"result"`
+}
+
+func TestCallRPC(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		assert.Equal(t, string(body), `<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/"><soap:Body>`+
+			`<rpc:Sum xmlns:rpc="urn:math"><a>1</a><b>2</b></rpc:Sum></soap:Body></soap:Envelope>`)
+		w.Write([]byte(`<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/"><soap:Body>` +
+			`<m:SumResponse xmlns:m="urn:math"><result>3</result></m:SumResponse></soap:Body></soap:Envelope>`))
+	}))
+	defer server.Close()
+
+	response := new(sumResponse)
+	err := NewClient(server.URL, false, nil).CallRPC("", &RPC{Operation: "Sum", Namespace: "urn:math"}, &sum{A: 1, B: 2}, response)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, response.Result, 3)
+}
+
+type names []string
+
+func (n names) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	items := make([]interface{}, len(n))
+	for i := range n {
+		items[i] = n[i]
+	}
+
+	return MarshalArray(e, start, xml.Name{Space: "http://www.w3.org/2001/XMLSchema", Local: "string"}, items)
+}
+
+func (n *names) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	return UnmarshalArray(d, func(item xml.StartElement) error {
+		var value string
+		if err := d.DecodeElement(&value, &item); err != nil {
+			return err
+		}
+		*n = append(*n, value)
+
+		return nil
+	})
+}
+
+type person struct {
+	Name    string `xml:"name"`
+	Friends names  `xml:"friends"`
+}
+
+type lookup struct {
+	Names names  `xml:"names"`
+	Limit int32  `xml:"limit"`
+	Sort  string `xml:"sort,omitempty"`
+}
+
+type lookupResponse struct {
+	First  *person `xml:"first"`
+	Second *person `xml:"second"`
+}
+
+func TestCallRPCEncoded(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		assert.Equal(t, string(body), `<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/" `+
+			`xmlns:soapenc="http://schemas.xmlsoap.org/soap/encoding/" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" `+
+			`xmlns:xsd="http://www.w3.org/2001/XMLSchema"><soap:Body>`+
+			`<rpc:Lookup xmlns:rpc="urn:people" soap:encodingStyle="http://schemas.xmlsoap.org/soap/encoding/">`+
+			`<names xmlns:soapenc="http://schemas.xmlsoap.org/soap/encoding/" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" `+
+			`xmlns:xsd="http://www.w3.org/2001/XMLSchema" xsi:type="soapenc:Array" soapenc:arrayType="xsd:string[2]">`+
+			`<item>ann</item><item>bob</item></names><limit xsi:type="xsd:int">5</limit></rpc:Lookup></soap:Body></soap:Envelope>`)
+		w.Write([]byte(`<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/"
+    xmlns:soapenc="http://schemas.xmlsoap.org/soap/encoding/" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance">
+  <soap:Body soap:encodingStyle="http://schemas.xmlsoap.org/soap/encoding/">
+    <ns1:LookupResponse xmlns:ns1="urn:people">
+      <first href="#id0"/>
+      <second href="#id1"/>
+    </ns1:LookupResponse>
+    <multiRef id="id0" soapenc:root="0" xsi:type="ns2:Person" xmlns:ns2="urn:people">
+      <name>ann</name>
+      <friends href="#id2"/>
+    </multiRef>
+    <multiRef id="id1" soapenc:root="0"><name>bob</name><friends href="#id2"/></multiRef>
+    <multiRef id="id2" soapenc:root="0" xsi:type="soapenc:Array" soapenc:arrayType="xsd:string[2]">
+      <name>carl</name>
+      <name>dora</name>
+    </multiRef>
+  </soap:Body>
+</soap:Envelope>`))
+	}))
+	defer server.Close()
+
+	response := new(lookupResponse)
+	rpc := &RPC{Operation: "Lookup", Namespace: "urn:people", Encoded: true,
+		RequestTypes: map[string]string{"limit": "int", "sort": "string"}}
+	err := NewClient(server.URL, false, nil).CallRPC("", rpc, &lookup{Names: names{"ann", "bob"}, Limit: 5}, response)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, response.First, &person{Name: "ann", Friends: names{"carl", "dora"}})
+	assert.Equal(t, response.Second, &person{Name: "bob", Friends: names{"carl", "dora"}})
+}
+
+func TestCallRPCFault(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(`<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/"><soap:Body><soap:Fault>` +
+			`<faultcode>soap:Client</faultcode><faultstring>bad sum</faultstring></soap:Fault></soap:Body></soap:Envelope>`))
+	}))
+	defer server.Close()
+
+	err := NewClient(server.URL, false, nil).CallRPC("", &RPC{Operation: "Sum"}, &sum{}, new(sumResponse))
+	fault, ok := err.(*Fault)
+	if !ok {
+		t.Fatal("Expected a *Fault, got", err)
+	}
+	assert.Equal(t, fault.Code, "soap:Client")
+	assert.Equal(t, fault.Error(), "bad sum")
+}
@@ -0,0 +1,93 @@
+package validate
+
+import (
+	"encoding/xml"
+	"io"
+)
+
+// node is an element of a checked message, with namespaces resolved.
+type node struct {
+	name     xml.Name
+	attrs    []xml.Attr
+	children []*node
+	text     string
+}
+
+func parse(r io.Reader) (*node, error) {
+	d := xml.NewDecoder(r)
+
+	var (
+		root  *node
+		stack []*node
+	)
+	for {
+		token, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			n := &node{name: t.Name, attrs: t.Attr}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, n)
+			} else {
+				root = n
+			}
+			stack = append(stack, n)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			if len(stack) > 0 {
+				stack[len(stack)-1].text += string(t)
+			}
+		}
+	}
+	if root == nil {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	return root, nil
+}
+
+func (n *node) attrValue(space, local string) (string, bool) {
+	for _, attr := range n.attrs {
+		if attr.Name.Space == space && attr.Name.Local == local {
+			return attr.Value, true
+		}
+	}
+
+	return "", false
+}
+
+func (n *node) attr(space, local string) string {
+	value, _ := n.attrValue(space, local)
+
+	return value
+}
+
+func (n *node) child(space, local string) *node {
+	for _, child := range n.children {
+		if child.name.Space == space && child.name.Local == local {
+			return child
+		}
+	}
+
+	return nil
+}
+
+// count returns the number of children named local.
+func (n *node) count(local string) int {
+	count := 0
+	for _, child := range n.children {
+		if child.name.Local == local {
+			count++
+		}
+	}
+
+	return count
+}
@@ -0,0 +1,493 @@
+// Package validate checks SOAP messages against the schema of a WSDL
+// contract, reporting every place where they depart from it. Plug it into a
+// client with soap.Client.SetValidator to catch contract drift.
+package validate
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/luhonghai/wsdl-example/pkg/wsdl"
+	"github.com/luhonghai/wsdl-example/pkg/xsd"
+)
+
+// Violation is a departure of a message from the schema.
+type Violation struct {
+	// Path locates the offending element or attribute, as in
+	// "/ListBucketResult/Contents[2]/Key" or "/Grant/Grantee/@type".
+	// Repeated elements are numbered from 1.
+	Path    string
+	Message string
+}
+
+func (v *Violation) String() string {
+	return v.Path + ": " + v.Message
+}
+
+// Error lists the violations found in a message.
+type Error struct {
+	Violations []*Violation
+}
+
+func (e *Error) Error() string {
+	messages := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		messages[i] = v.String()
+	}
+
+	return "validate: " + strings.Join(messages, "; ")
+}
+
+// Validator checks messages against the schemas of a WSDL document. It is
+// safe for concurrent use.
+type Validator struct {
+	defs *wsdl.Definitions
+
+	mu sync.Mutex
+	// patterns caches the compiled xs:patterns.
+	patterns map[string]*regexp.Regexp
+}
+
+// New returns a validator for the messages of defs.
+func New(defs *wsdl.Definitions) *Validator {
+	return &Validator{
+		defs:     defs,
+		patterns: make(map[string]*regexp.Regexp),
+	}
+}
+
+// Validate checks the elements in the body of a document style SOAP
+// envelope, faults aside. Violations are returned as an *Error.
+func (v *Validator) Validate(envelope []byte) error {
+	root, err := parse(bytes.NewReader(envelope))
+	if err != nil {
+		return err
+	}
+	if root.name.Local != "Envelope" {
+		return fmt.Errorf("validate: %s is not a SOAP envelope", root.name.Local)
+	}
+	body := root.child(root.name.Space, "Body")
+	if body == nil {
+		return fmt.Errorf("validate: the envelope has no body")
+	}
+
+	c := &checker{v: v}
+	for _, n := range body.children {
+		if n.name.Space == root.name.Space && n.name.Local == "Fault" {
+			continue
+		}
+		c.global("/"+n.name.Local, n)
+	}
+
+	return c.err()
+}
+
+// ValidateElement checks a document holding a single element declared at
+// the top level of the schema.
+func (v *Validator) ValidateElement(r io.Reader) error {
+	root, err := parse(r)
+	if err != nil {
+		return err
+	}
+	c := &checker{v: v}
+	c.global("/"+root.name.Local, root)
+
+	return c.err()
+}
+
+// pattern compiles an xs:pattern, which always matches whole values. It
+// returns nil for the few constructs of the schema dialect Go lacks, such
+// as character class subtraction, and these patterns go unchecked.
+func (v *Validator) pattern(expr string) *regexp.Regexp {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	re, ok := v.patterns[expr]
+	if !ok {
+		re, _ = regexp.Compile("^(?:" + expr + ")$")
+		v.patterns[expr] = re
+	}
+
+	return re
+}
+
+func (v *Validator) element(name wsdl.QName) (*wsdl.Element, *wsdl.Schema) {
+	for _, schema := range v.defs.Schemas {
+		if schema.TargetNamespace != name.Space {
+			continue
+		}
+		for _, e := range schema.Elements {
+			if e.Name == name.Local {
+				return e, schema
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+func (v *Validator) complexType(name wsdl.QName) (*wsdl.ComplexType, *wsdl.Schema) {
+	for _, schema := range v.defs.Schemas {
+		if schema.TargetNamespace != name.Space {
+			continue
+		}
+		for _, ct := range schema.ComplexTypes {
+			if ct.Name == name.Local {
+				return ct, schema
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+// derived returns the type named local that is base or derives from it.
+// xsi:type values are matched by local name, as their prefixes are often
+// left undeclared.
+func (v *Validator) derived(base *wsdl.ComplexType, local string) (*wsdl.ComplexType, *wsdl.Schema) {
+	for _, schema := range v.defs.Schemas {
+		for _, ct := range schema.ComplexTypes {
+			if ct.Name != local {
+				continue
+			}
+			for t := ct; t != nil; t, _ = v.complexType(t.Base) {
+				if t == base {
+					return ct, schema
+				}
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+// level is a complex type along with the schema declaring it, which decides
+// the namespace of its local elements.
+type level struct {
+	ct     *wsdl.ComplexType
+	schema *wsdl.Schema
+}
+
+// hierarchy returns ct and the types it derives from, base first.
+func (v *Validator) hierarchy(ct *wsdl.ComplexType, schema *wsdl.Schema) []level {
+	levels := []level{{ct, schema}}
+	for {
+		base, baseSchema := v.complexType(levels[0].ct.Base)
+		if base == nil || len(levels) > 32 {
+			return levels
+		}
+		levels = append([]level{{base, baseSchema}}, levels...)
+	}
+}
+
+type checker struct {
+	v          *Validator
+	violations []*Violation
+}
+
+func (c *checker) add(path, format string, args ...interface{}) {
+	c.violations = append(c.violations, &Violation{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+func (c *checker) err() error {
+	if len(c.violations) == 0 {
+		return nil
+	}
+
+	return &Error{Violations: c.violations}
+}
+
+func (c *checker) global(path string, n *node) {
+	name := wsdl.QName{Space: n.name.Space, Local: n.name.Local}
+	e, schema := c.v.element(name)
+	if e == nil {
+		c.add(path, "element %s is not declared", name)
+		return
+	}
+	c.element(path, n, e, schema)
+}
+
+// element checks n against its declaration e, found in schema.
+func (c *checker) element(path string, n *node, e *wsdl.Element, schema *wsdl.Schema) {
+	if !e.Ref.IsZero() {
+		global, globalSchema := c.v.element(e.Ref)
+		if global == nil {
+			c.add(path, "element %s is not declared", e.Ref)
+			return
+		}
+		e, schema = global, globalSchema
+	}
+
+	switch isNil := n.attr(xsd.InstanceNamespace, "nil"); {
+	case isNil != "true" && isNil != "1":
+	case !e.Nillable:
+		c.add(path, "xsi:nil on an element that is not nillable")
+		return
+	default:
+		if len(n.children) > 0 || strings.TrimSpace(n.text) != "" {
+			c.add(path, "nil element has content")
+		}
+		return
+	}
+
+	switch {
+	case e.ComplexType != nil:
+		c.complex(path, n, e.ComplexType, schema)
+	case e.SimpleType != nil:
+		c.simpleElement(path, n)
+		c.simple(path, n.text, e.SimpleType)
+	case e.Type.IsZero():
+		// xs:anyType takes anything.
+	default:
+		if ct, ctSchema := c.v.complexType(e.Type); ct != nil {
+			c.complex(path, n, ct, ctSchema)
+			return
+		}
+		c.simpleElement(path, n)
+		c.value(path, n.text, e.Type)
+	}
+}
+
+func (c *checker) simpleElement(path string, n *node) {
+	if len(n.children) > 0 {
+		c.add(path, "element of a simple type has child elements")
+	}
+}
+
+func (c *checker) complex(path string, n *node, ct *wsdl.ComplexType, schema *wsdl.Schema) {
+	if xsiType := n.attr(xsd.InstanceNamespace, "type"); xsiType != "" {
+		local := xsiType[strings.Index(xsiType, ":")+1:]
+		derived, derivedSchema := c.v.derived(ct, local)
+		if derived == nil {
+			c.add(path+"/@type", "xsi:type %s does not derive from %s", local, ct.Name)
+			return
+		}
+		ct, schema = derived, derivedSchema
+	} else if ct.Abstract {
+		c.add(path, "type %s is abstract; an xsi:type is required", ct.Name)
+		return
+	}
+
+	levels := c.v.hierarchy(ct, schema)
+	c.attributes(path, n, levels)
+
+	for _, l := range levels {
+		if !l.ct.SimpleContent {
+			continue
+		}
+		c.simpleElement(path, n)
+		// The most basic type with simple content names the value's type.
+		c.value(path, n.text, l.ct.Base)
+		return
+	}
+
+	if strings.TrimSpace(n.text) != "" {
+		c.add(path, "unexpected text %q", strings.TrimSpace(n.text))
+	}
+	c.children(path, n, levels)
+}
+
+func (c *checker) attributes(path string, n *node, levels []level) {
+	declared := make(map[string]bool)
+	for _, l := range levels {
+		for _, a := range l.ct.Attributes {
+			name, typ := a.Name, a.Type
+			if !a.Ref.IsZero() {
+				name = a.Ref.Local
+			}
+			declared[name] = true
+
+			attr, ok := n.attrValue("", name)
+			switch {
+			case !ok && a.Use == "required":
+				c.add(path+"/@"+name, "required attribute is missing")
+			case ok && !typ.IsZero():
+				c.value(path+"/@"+name, attr, typ)
+			}
+		}
+	}
+	for _, attr := range n.attrs {
+		if attr.Name.Space == "" && attr.Name.Local != "xmlns" && !declared[attr.Name.Local] {
+			c.add(path+"/@"+attr.Name.Local, "attribute is not declared")
+		}
+	}
+}
+
+// particle is an element declaration in the content of a complex type.
+type particle struct {
+	e      *wsdl.Element
+	name   xml.Name
+	schema *wsdl.Schema
+	count  int
+}
+
+func (c *checker) children(path string, n *node, levels []level) {
+	var particles []*particle
+	ordered := true
+	for _, l := range levels {
+		if l.ct.Model == "all" {
+			ordered = false
+		}
+		for _, e := range l.ct.Elements {
+			p := &particle{e: e, schema: l.schema, name: xml.Name{Local: e.Name}}
+			switch {
+			case !e.Ref.IsZero():
+				p.name = xml.Name{Space: e.Ref.Space, Local: e.Ref.Local}
+			case l.schema != nil && l.schema.ElementFormDefault == "qualified":
+				p.name.Space = l.schema.TargetNamespace
+			}
+			particles = append(particles, p)
+		}
+	}
+
+	last := 0
+	seen := make(map[string]int)
+	for _, child := range n.children {
+		seen[child.name.Local]++
+		childPath := path + "/" + child.name.Local
+		if n.count(child.name.Local) > 1 {
+			childPath += fmt.Sprintf("[%d]", seen[child.name.Local])
+		}
+
+		i := findParticle(particles, child.name.Local)
+		if i < 0 {
+			c.add(childPath, "element is not declared in %s", describe(levels))
+			continue
+		}
+		p := particles[i]
+		if child.name.Space != p.name.Space {
+			c.add(childPath, "element is in namespace %q instead of %q", child.name.Space, p.name.Space)
+		}
+		if ordered && i < last {
+			c.add(childPath, "element is out of order; it goes before %s", particles[last].name.Local)
+		}
+		if i > last {
+			last = i
+		}
+		p.count++
+		c.element(childPath, child, p.e, p.schema)
+	}
+
+	for _, p := range particles {
+		switch {
+		case p.count < p.e.MinOccurs && p.count == 0:
+			c.add(path+"/"+p.name.Local, "required element is missing")
+		case p.count < p.e.MinOccurs:
+			c.add(path+"/"+p.name.Local, "element occurs %d times; minOccurs is %d", p.count, p.e.MinOccurs)
+		case p.e.MaxOccurs != wsdl.Unbounded && p.count > p.e.MaxOccurs:
+			c.add(path+"/"+p.name.Local, "element occurs %d times; maxOccurs is %d", p.count, p.e.MaxOccurs)
+		}
+	}
+}
+
+func findParticle(particles []*particle, local string) int {
+	for i, p := range particles {
+		if p.name.Local == local {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func describe(levels []level) string {
+	name := levels[len(levels)-1].ct.Name
+	if name == "" {
+		return "the content of its parent"
+	}
+
+	return "type " + name
+}
+
+// value checks text against the simple type named typ.
+func (c *checker) value(path, text string, typ wsdl.QName) {
+	if wsdl.IsBuiltin(typ) {
+		if !preservesWhitespace(typ.Local) {
+			text = strings.TrimSpace(text)
+		}
+		if err := xsd.CheckValue(typ.Local, text); err != nil {
+			c.add(path, "%v", err)
+		}
+		return
+	}
+	if st := c.v.defs.SimpleType(typ); st != nil {
+		c.simple(path, text, st)
+	}
+}
+
+// simple checks text against the facets of st, then against its base.
+func (c *checker) simple(path, text string, st *wsdl.SimpleType) {
+	value := text
+	if !preservesWhitespace(c.builtinBase(st).Local) {
+		value = strings.TrimSpace(text)
+	}
+
+	if len(st.Enumeration) > 0 && !contains(st.Enumeration, value) {
+		c.add(path, "%q is not a valid %s; expected one of %s", value, typeName(st), strings.Join(st.Enumeration, ", "))
+	}
+	if len(st.Patterns) > 0 && !c.matches(st.Patterns, value) {
+		c.add(path, "%q does not match the pattern of %s", value, typeName(st))
+	}
+
+	c.value(path, text, st.Base)
+}
+
+// builtinBase returns the built-in type st ultimately restricts.
+func (c *checker) builtinBase(st *wsdl.SimpleType) wsdl.QName {
+	base := st.Base
+	for i := 0; i < 32; i++ {
+		next := c.v.defs.SimpleType(base)
+		if next == nil {
+			break
+		}
+		base = next.Base
+	}
+
+	return base
+}
+
+// matches reports whether value matches any of patterns; patterns of the
+// same restriction are alternatives. Patterns Go cannot compile match
+// anything.
+func (c *checker) matches(patterns []string, value string) bool {
+	for _, expr := range patterns {
+		re := c.v.pattern(expr)
+		if re == nil || re.MatchString(value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func preservesWhitespace(builtin string) bool {
+	switch builtin {
+	case "string", "normalizedString", "":
+		return true
+	}
+
+	return false
+}
+
+func typeName(st *wsdl.SimpleType) string {
+	if st.Name == "" {
+		return "value"
+	}
+
+	return st.Name
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
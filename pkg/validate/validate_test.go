@@ -0,0 +1,219 @@
+package validate
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/luhonghai/wsdl-example/pkg/aws"
+	"github.com/luhonghai/wsdl-example/pkg/calculator"
+	"github.com/luhonghai/wsdl-example/pkg/soap"
+	"github.com/luhonghai/wsdl-example/pkg/wsdl"
+	"github.com/luhonghai/wsdl-example/pkg/xsd"
+	"github.com/magiconair/properties/assert"
+)
+
+func loadS3(t *testing.T) *Validator {
+	catalog, err := wsdl.ReadCatalog("../schemas/catalog.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defs, err := (&wsdl.Loader{Catalog: catalog}).Load("../AmazonS3.wsdl")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return New(defs)
+}
+
+func envelope(t *testing.T, content interface{}) []byte {
+	data, err := xml.Marshal(soap.Envelope{Body: soap.Body{Content: content}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return data
+}
+
+func violations(err error) []string {
+	if err == nil {
+		return nil
+	}
+	var lines []string
+	for _, v := range err.(*Error).Violations {
+		lines = append(lines, v.String())
+	}
+
+	return lines
+}
+
+func TestValidateGenerated(t *testing.T) {
+	defs, err := wsdl.ParseFile("../calculator.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	v := New(defs)
+
+	if err := v.Validate(envelope(t, &calculator.Add{IntA: 1, IntB: 2})); err != nil {
+		t.Error(err)
+	}
+	if err := v.Validate(envelope(t, &calculator.AddResponse{AddResult: 3})); err != nil {
+		t.Error(err)
+	}
+
+	s3 := loadS3(t)
+	policy := &aws.GetBucketAccessControlPolicyResponse{
+		GetBucketAccessControlPolicyResponse: &aws.AccessControlPolicy{
+			Owner: &aws.CanonicalUser{ID: "1"},
+			AccessControlList: &aws.AccessControlList{Grant: []*aws.Grant{{
+				Grantee:    &aws.Grantee{Type: aws.GranteeCanonicalUser, ID: xsd.String("1")},
+				Permission: aws.PermissionREAD,
+			}}},
+		},
+	}
+	if err := s3.Validate(envelope(t, policy)); err != nil {
+		t.Error(err)
+	}
+	list := &aws.ListBucketResponse{ListBucketResponse: &aws.ListBucketResult{
+		Name:     "photos",
+		Contents: []*aws.ListEntry{{Key: "a", LastModified: xsd.DateTime{Time: time.Now()}, StorageClass: aws.StorageClassSTANDARD}},
+	}}
+	if err := s3.Validate(envelope(t, list)); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestValidateViolations(t *testing.T) {
+	err := loadS3(t).Validate([]byte(`<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/"
+    xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance"><soap:Body>
+  <ListBucketResponse xmlns="http://s3.amazonaws.com/doc/2006-03-01/"><ListBucketResponse>
+    <Name>photos</Name>
+    <Prefix/>
+    <MaxKeys>many</MaxKeys>
+    <IsTruncated>false</IsTruncated>
+    <Marker/>
+    <Contents>
+      <Key>a</Key><LastModified>2009-10-12T17:50:30.000Z</LastModified><ETag/><Size>1</Size>
+      <StorageClass>STANDARD</StorageClass>
+    </Contents>
+    <Contents>
+      <Key>b</Key><LastModified>yesterday</LastModified><ETag/><Size>2</Size>
+      <Owner xsi:type="Group"><URI/></Owner>
+      <StorageClass>COLD</StorageClass>
+      <Color>red</Color>
+    </Contents>
+    <NextMarker xsi:nil="true"/>
+  </ListBucketResponse></ListBucketResponse>
+</soap:Body></soap:Envelope>`))
+
+	assert.Equal(t, violations(err), []string{
+		`/ListBucketResponse/ListBucketResponse/MaxKeys: "many" is not a valid int`,
+		`/ListBucketResponse/ListBucketResponse/Marker: element is out of order; it goes before IsTruncated`,
+		`/ListBucketResponse/ListBucketResponse/Contents[2]/LastModified: xsd: "yesterday" is not a valid dateTime`,
+		`/ListBucketResponse/ListBucketResponse/Contents[2]/Owner/@type: xsi:type Group does not derive from CanonicalUser`,
+		`/ListBucketResponse/ListBucketResponse/Contents[2]/StorageClass: "COLD" is not a valid StorageClass; expected one of STANDARD, REDUCED_REDUNDANCY, GLACIER, UNKNOWN`,
+		`/ListBucketResponse/ListBucketResponse/Contents[2]/Color: element is not declared in type ListEntry`,
+		`/ListBucketResponse/ListBucketResponse/NextMarker: element is out of order; it goes before Contents`,
+		`/ListBucketResponse/ListBucketResponse/NextMarker: xsi:nil on an element that is not nillable`,
+	})
+}
+
+func TestValidateCardinality(t *testing.T) {
+	err := loadS3(t).Validate([]byte(`<Envelope xmlns="http://schemas.xmlsoap.org/soap/envelope/"><Body>
+<GetBucketAccessControlPolicyResponse xmlns="http://s3.amazonaws.com/doc/2006-03-01/">
+  <GetBucketAccessControlPolicyResponse>
+    <AccessControlList>
+      <Grant><Grantee><ID>1</ID></Grantee><Permission>READ</Permission><Permission>WRITE</Permission></Grant>
+    </AccessControlList>
+  </GetBucketAccessControlPolicyResponse>
+</GetBucketAccessControlPolicyResponse>
+<Unknown xmlns="urn:other"/>
+</Body></Envelope>`))
+
+	assert.Equal(t, violations(err), []string{
+		`/GetBucketAccessControlPolicyResponse/GetBucketAccessControlPolicyResponse/AccessControlList/Grant/Grantee: type Grantee is abstract; an xsi:type is required`,
+		`/GetBucketAccessControlPolicyResponse/GetBucketAccessControlPolicyResponse/AccessControlList/Grant/Permission: element occurs 2 times; maxOccurs is 1`,
+		`/GetBucketAccessControlPolicyResponse/GetBucketAccessControlPolicyResponse/Owner: required element is missing`,
+		`/Unknown: element {urn:other}Unknown is not declared`,
+	})
+}
+
+const facetsWSDL = `<definitions xmlns="http://schemas.xmlsoap.org/wsdl/" targetNamespace="urn:test">
+  <types>
+    <xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" xmlns:t="urn:test" targetNamespace="urn:test">
+      <xs:simpleType name="Code">
+        <xs:restriction base="xs:string">
+          <xs:pattern value="[A-Z]{3}"/>
+          <xs:pattern value="[0-9]{3}"/>
+        </xs:restriction>
+      </xs:simpleType>
+      <xs:complexType name="Price">
+        <xs:simpleContent>
+          <xs:extension base="xs:decimal">
+            <xs:attribute name="currency" type="t:Code" use="required"/>
+          </xs:extension>
+        </xs:simpleContent>
+      </xs:complexType>
+      <xs:element name="Item">
+        <xs:complexType>
+          <xs:all>
+            <xs:element name="price" type="t:Price" nillable="true"/>
+            <xs:element name="code" type="t:Code"/>
+          </xs:all>
+        </xs:complexType>
+      </xs:element>
+    </xs:schema>
+  </types>
+</definitions>`
+
+func TestValidateSchemaFacets(t *testing.T) {
+	defs, err := wsdl.Parse(strings.NewReader(facetsWSDL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	v := New(defs)
+
+	err = v.ValidateElement(strings.NewReader(`<t:Item xmlns:t="urn:test"><code>978</code><price currency="EUR"> 2.50 </price></t:Item>`))
+	if err != nil {
+		t.Error(err)
+	}
+	err = v.ValidateElement(strings.NewReader(`<t:Item xmlns:t="urn:test" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance">` +
+		`<code>eur</code><price xsi:nil="true"/></t:Item>`))
+	assert.Equal(t, violations(err), []string{`/Item/code: "eur" does not match the pattern of Code`})
+
+	err = v.ValidateElement(bytes.NewReader([]byte(`<Item xmlns="urn:test"><price kind="net">free</price></Item>`)))
+	assert.Equal(t, violations(err), []string{
+		`/Item/price: element is in namespace "urn:test" instead of ""`,
+		`/Item/price/@currency: required attribute is missing`,
+		`/Item/price/@kind: attribute is not declared`,
+		`/Item/price: "free" is not a valid decimal`,
+		`/Item/code: required element is missing`,
+	})
+}
+
+// A validator is shared by the handlers of a server; run with -race.
+func TestValidateConcurrently(t *testing.T) {
+	defs, err := wsdl.Parse(strings.NewReader(facetsWSDL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	v := New(defs)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				err := v.ValidateElement(strings.NewReader(`<t:Item xmlns:t="urn:test"><code>eur</code><price currency="EUR">1</price></t:Item>`))
+				if len(violations(err)) != 1 {
+					t.Errorf("unexpected violations %v", violations(err))
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,185 @@
+package wsdl
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+)
+
+// node is an element of a parsed document. WSDL and XSD refer to components
+// by QName in attribute values, which encoding/xml leaves unresolved, so the
+// namespace declarations in scope are kept with every node.
+type node struct {
+	name     xml.Name
+	attrs    []xml.Attr
+	children []*node
+	text     string
+	ns       map[string]string
+}
+
+func parseNode(r io.Reader) (*node, error) {
+	d := xml.NewDecoder(r)
+	d.CharsetReader = charsetReader
+
+	var (
+		root  *node
+		stack []*node
+	)
+	for {
+		token, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			n := &node{name: t.Name, attrs: t.Attr}
+			parentNS := map[string]string{"xml": "http://www.w3.org/XML/1998/namespace"}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, n)
+				parentNS = parent.ns
+			} else {
+				root = n
+			}
+			n.ns = scope(parentNS, t.Attr)
+			stack = append(stack, n)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			if len(stack) > 0 {
+				stack[len(stack)-1].text += string(t)
+			}
+		}
+	}
+	if root == nil {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	return root, nil
+}
+
+// scope returns the namespace declarations of an element with attributes
+// attrs nested in a parent with declarations parent.
+func scope(parent map[string]string, attrs []xml.Attr) map[string]string {
+	ns := parent
+	copied := false
+	for _, attr := range attrs {
+		var prefix string
+		switch {
+		case attr.Name.Space == "xmlns":
+			prefix = attr.Name.Local
+		case attr.Name.Space == "" && attr.Name.Local == "xmlns":
+			prefix = ""
+		default:
+			continue
+		}
+		if !copied {
+			ns = make(map[string]string, len(parent)+1)
+			for k, v := range parent {
+				ns[k] = v
+			}
+			copied = true
+		}
+		ns[prefix] = attr.Value
+	}
+
+	return ns
+}
+
+// charsetReader accepts the encodings WSDL documents are published in. Only
+// UTF-8 is decoded; declarations of its common aliases are tolerated.
+func charsetReader(charset string, input io.Reader) (io.Reader, error) {
+	switch strings.ToLower(charset) {
+	case "utf-8", "utf8", "us-ascii", "ascii":
+		return input, nil
+	}
+
+	return nil, &UnsupportedCharsetError{Charset: charset}
+}
+
+// UnsupportedCharsetError is returned for documents not encoded in UTF-8.
+type UnsupportedCharsetError struct {
+	Charset string
+}
+
+func (e *UnsupportedCharsetError) Error() string {
+	return "wsdl: unsupported charset " + e.Charset
+}
+
+func (n *node) is(space, local string) bool {
+	return n.name.Space == space && n.name.Local == local
+}
+
+func (n *node) attr(local string) string {
+	return n.attrNS("", local)
+}
+
+// attrNS returns the value of attribute space:local, as in wsdl:arrayType.
+func (n *node) attrNS(space, local string) string {
+	for _, attr := range n.attrs {
+		if attr.Name.Space == space && attr.Name.Local == local {
+			return attr.Value
+		}
+	}
+
+	return ""
+}
+
+// all returns the children of n named space:local.
+func (n *node) all(space, local string) []*node {
+	var nodes []*node
+	for _, child := range n.children {
+		if child.is(space, local) {
+			nodes = append(nodes, child)
+		}
+	}
+
+	return nodes
+}
+
+// first returns the first child of n named space:local, or nil.
+func (n *node) first(space, local string) *node {
+	for _, child := range n.children {
+		if child.is(space, local) {
+			return child
+		}
+	}
+
+	return nil
+}
+
+// qname resolves the QName held by attribute local against the namespace
+// declarations in scope. Unprefixed names take the default namespace.
+func (n *node) qname(local string) QName {
+	return n.resolve(n.attr(local))
+}
+
+// resolve resolves a QName written in n, as in an attribute value.
+func (n *node) resolve(value string) QName {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return QName{}
+	}
+
+	prefix, name := "", value
+	if i := strings.Index(value, ":"); i >= 0 {
+		prefix, name = value[:i], value[i+1:]
+	}
+
+	return QName{Space: n.ns[prefix], Local: name}
+}
+
+// documentation returns the text of the documentation child in namespace
+// space, if any.
+func (n *node) documentation(space string) string {
+	doc := n.first(space, "documentation")
+	if doc == nil {
+		return ""
+	}
+
+	return strings.TrimSpace(doc.text)
+}
@@ -0,0 +1,300 @@
+package wsdl
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Catalog maps the locations of remote schemas to local files standing in
+// for them, so documents can be loaded without network access.
+type Catalog map[string]string
+
+// ReadCatalog reads a catalog file. Every line holds a location and the path
+// of its file, relative to the catalog. Blank lines and lines starting with
+// # are ignored.
+func ReadCatalog(path string) (Catalog, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	catalog := make(Catalog)
+	dir := filepath.Dir(path)
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := strings.Fields(text)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("wsdl: %s:%d: expected a location and a file", path, line)
+		}
+		file := filepath.FromSlash(fields[1])
+		if !filepath.IsAbs(file) {
+			file = filepath.Join(dir, file)
+		}
+		catalog[fields[0]] = file
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return catalog, nil
+}
+
+// WriteFile writes c to a catalog file at path, sorted by location. The
+// comments heading a catalog already at path, such as notes on schemas
+// maintained by hand, are kept.
+func (c Catalog) WriteFile(path string) error {
+	locations := make([]string, 0, len(c))
+	for location := range c {
+		locations = append(locations, location)
+	}
+	sort.Strings(locations)
+
+	var buffer bytes.Buffer
+	buffer.WriteString(catalogHeader(path))
+	dir := filepath.Dir(path)
+	for _, location := range locations {
+		file := c[location]
+		if rel, err := filepath.Rel(dir, file); err == nil {
+			file = rel
+		}
+		fmt.Fprintf(&buffer, "%s %s\n", location, filepath.ToSlash(file))
+	}
+
+	return ioutil.WriteFile(path, buffer.Bytes(), 0644)
+}
+
+const defaultCatalogHeader = "# Local copies of remote schemas, written by wsdl-example vendor-schemas.\n"
+
+// catalogHeader returns the leading comment lines of the catalog at path,
+// or a default header when there is none.
+func catalogHeader(path string) string {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return defaultCatalogHeader
+	}
+
+	var header strings.Builder
+	for _, line := range strings.SplitAfter(string(data), "\n") {
+		if !strings.HasPrefix(line, "#") {
+			break
+		}
+		header.WriteString(strings.TrimRight(line, "\n") + "\n")
+	}
+	if header.Len() == 0 {
+		return defaultCatalogHeader
+	}
+
+	return header.String()
+}
+
+// Loader reads WSDL documents along with the schemas they include and
+// import. Locations are resolved against the document referring to them,
+// then looked up in the catalog.
+type Loader struct {
+	Catalog Catalog
+	// Fetch retrieves remote documents missing from the catalog. When nil,
+	// such documents fail to load.
+	Fetch func(location string) ([]byte, error)
+}
+
+// Load reads the WSDL document at location, a file path or URL, and the
+// schemas it pulls in. Every schema is read once, so include and import
+// cycles end where they started.
+func (l *Loader) Load(location string) (*Definitions, error) {
+	data, err := l.read(location)
+	if err != nil {
+		return nil, err
+	}
+	defs, err := Parse(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("wsdl: %s: %v", location, err)
+	}
+
+	location = cleanLocation(location)
+	state := &loadState{Loader: l, defs: defs, loaded: map[string]bool{location: true}}
+	// Imported schemas are appended to defs.Schemas, and resolved, on the way.
+	embedded := defs.Schemas
+	for _, schema := range embedded {
+		if err := state.resolve(schema, location); err != nil {
+			return nil, err
+		}
+	}
+
+	return defs, nil
+}
+
+type loadState struct {
+	*Loader
+	defs   *Definitions
+	loaded map[string]bool
+}
+
+// resolve loads the schemas pulled in by schema, which was read from base.
+// Included components are merged into schema, imported schemas are added to
+// the definitions.
+func (s *loadState) resolve(schema *Schema, base string) error {
+	for _, include := range schema.Includes {
+		if err := s.include(schema, base, include); err != nil {
+			return err
+		}
+	}
+	for _, imp := range schema.Imports {
+		if err := s.importSchema(imp, base); err != nil {
+			return err
+		}
+	}
+	schema.Unresolved = nil
+
+	return nil
+}
+
+func (s *loadState) include(schema *Schema, base, include string) error {
+	location := resolveLocation(base, include)
+	if s.loaded[location] {
+		return nil
+	}
+	included, err := s.schema(location)
+	if err != nil {
+		return err
+	}
+	if included.TargetNamespace != "" && included.TargetNamespace != schema.TargetNamespace {
+		return fmt.Errorf("wsdl: %s: included schema has namespace %s, not %s", location, included.TargetNamespace, schema.TargetNamespace)
+	}
+	schema.Elements = append(schema.Elements, included.Elements...)
+	schema.ComplexTypes = append(schema.ComplexTypes, included.ComplexTypes...)
+	schema.SimpleTypes = append(schema.SimpleTypes, included.SimpleTypes...)
+
+	for _, nested := range included.Includes {
+		if err := s.include(schema, location, nested); err != nil {
+			return err
+		}
+	}
+	for _, imp := range included.Imports {
+		if err := s.importSchema(imp, location); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *loadState) importSchema(imp *Import, base string) error {
+	if imp.SchemaLocation == "" {
+		return nil
+	}
+	location := resolveLocation(base, imp.SchemaLocation)
+	if s.loaded[location] {
+		return nil
+	}
+	imported, err := s.schema(location)
+	if err != nil {
+		return err
+	}
+	if imported.TargetNamespace != imp.Namespace {
+		return fmt.Errorf("wsdl: %s: imported schema has namespace %s, not %s", location, imported.TargetNamespace, imp.Namespace)
+	}
+	s.defs.Schemas = append(s.defs.Schemas, imported)
+
+	return s.resolve(imported, location)
+}
+
+// schema reads the schema document at location and marks it loaded.
+func (s *loadState) schema(location string) (*Schema, error) {
+	s.loaded[location] = true
+
+	data, err := s.read(location)
+	if err != nil {
+		return nil, err
+	}
+	root, err := parseNode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("wsdl: %s: %v", location, err)
+	}
+	if !root.is(XSDNamespace, "schema") {
+		return nil, fmt.Errorf("wsdl: %s: root element is %s, not schema", location, QName(root.name))
+	}
+
+	return parseSchema(root), nil
+}
+
+func (l *Loader) read(location string) ([]byte, error) {
+	if file, ok := l.Catalog[location]; ok {
+		return ioutil.ReadFile(file)
+	}
+	if !isURL(location) {
+		return ioutil.ReadFile(location)
+	}
+	if u, err := url.Parse(location); err == nil && u.Scheme == "file" {
+		return ioutil.ReadFile(filepath.FromSlash(u.Path))
+	}
+	if l.Fetch == nil {
+		return nil, fmt.Errorf("wsdl: %s is not in the catalog; vendor it with wsdl-example vendor-schemas", location)
+	}
+
+	return l.Fetch(location)
+}
+
+// FetchHTTP retrieves the document at location over HTTP.
+func FetchHTTP(location string) ([]byte, error) {
+	resp, err := http.Get(location)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("wsdl: fetching %s: %s", location, resp.Status)
+	}
+
+	return ioutil.ReadAll(resp.Body)
+}
+
+// resolveLocation returns location relative to the document at base.
+func resolveLocation(base, location string) string {
+	if isURL(location) {
+		return location
+	}
+	if isURL(base) {
+		b, err := url.Parse(base)
+		if err != nil {
+			return location
+		}
+		ref, err := url.Parse(location)
+		if err != nil {
+			return location
+		}
+		return b.ResolveReference(ref).String()
+	}
+	if filepath.IsAbs(location) {
+		return location
+	}
+
+	return filepath.Join(filepath.Dir(base), filepath.FromSlash(location))
+}
+
+func cleanLocation(location string) string {
+	if isURL(location) {
+		return location
+	}
+
+	return filepath.Clean(location)
+}
+
+func isURL(location string) bool {
+	i := strings.Index(location, "://")
+	// Leave room for Windows drive letters such as C:\.
+	return i > 1
+}
@@ -0,0 +1,187 @@
+package wsdl
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/magiconair/properties/assert"
+)
+
+const testWSDL = `<definitions xmlns="http://schemas.xmlsoap.org/wsdl/" targetNamespace="urn:a">
+  <types>
+    <xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" targetNamespace="urn:a">
+      <xs:include schemaLocation="%s"/>
+    </xs:schema>
+  </types>
+</definitions>`
+
+// The included schema includes itself through a second one and imports a
+// third.
+var testSchemas = map[string]string{
+	"/a.xsd": `<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" targetNamespace="urn:a">
+  <xs:include schemaLocation="more/a2.xsd"/>
+  <xs:import namespace="urn:b" schemaLocation="b.xsd"/>
+  <xs:element name="A" type="xs:string"/>
+</xs:schema>`,
+	"/more/a2.xsd": `<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" targetNamespace="urn:a">
+  <xs:include schemaLocation="../a.xsd"/>
+  <xs:element name="A2" type="xs:string"/>
+</xs:schema>`,
+	"/b.xsd": `<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" targetNamespace="urn:b">
+  <xs:import namespace="urn:a" schemaLocation="a.xsd"/>
+  <xs:element name="B" type="xs:string"/>
+</xs:schema>`,
+}
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func assertLoaded(t *testing.T, defs *Definitions) {
+	assert.Equal(t, len(defs.Schemas), 2)
+	for _, name := range []QName{{"urn:a", "A"}, {"urn:a", "A2"}, {"urn:b", "B"}} {
+		if defs.Element(name) == nil {
+			t.Errorf("element %s not loaded", name)
+		}
+	}
+	assert.Equal(t, len(defs.Schemas[0].Elements), 2)
+	assert.Equal(t, len(defs.Schemas[0].Unresolved), 0)
+}
+
+func TestLoadRelativeIncludes(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wsdl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{"/service.wsdl": strings.Replace(testWSDL, "%s", "schemas/a.xsd", 1)}
+	for name, content := range testSchemas {
+		files["/schemas"+name] = content
+	}
+	writeFiles(t, dir, files)
+
+	defs, err := (&Loader{}).Load(filepath.Join(dir, "service.wsdl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertLoaded(t, defs)
+}
+
+func TestLoadCatalog(t *testing.T) {
+	dir, err := ioutil.TempDir("", "wsdl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"/service.wsdl": strings.Replace(testWSDL, "%s", "http://example.com/a.xsd", 1),
+		"/catalog.txt": `# test catalog
+http://example.com/a.xsd vendor/a.xsd
+http://example.com/more/a2.xsd vendor/a2.xsd
+
+http://example.com/b.xsd vendor/b.xsd
+`,
+		"/vendor/a.xsd":  testSchemas["/a.xsd"],
+		"/vendor/a2.xsd": testSchemas["/more/a2.xsd"],
+		"/vendor/b.xsd":  testSchemas["/b.xsd"],
+	}
+	writeFiles(t, dir, files)
+
+	catalog, err := ReadCatalog(filepath.Join(dir, "catalog.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, catalog["http://example.com/b.xsd"], filepath.Join(dir, "vendor", "b.xsd"))
+
+	defs, err := (&Loader{Catalog: catalog}).Load(filepath.Join(dir, "service.wsdl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertLoaded(t, defs)
+
+	delete(catalog, "http://example.com/b.xsd")
+	_, err = (&Loader{Catalog: catalog}).Load(filepath.Join(dir, "service.wsdl"))
+	if err == nil || !strings.Contains(err.Error(), "http://example.com/b.xsd is not in the catalog") {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
+
+func TestVendor(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		content, ok := testSchemas[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(content))
+	}))
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "wsdl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	writeFiles(t, dir, map[string]string{"/service.wsdl": strings.Replace(testWSDL, "%s", server.URL+"/a.xsd", 1)})
+
+	catalog := make(Catalog)
+	locations, err := Vendor(filepath.Join(dir, "service.wsdl"), catalog, filepath.Join(dir, "schemas"), FetchHTTP)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, locations, []string{server.URL + "/a.xsd", server.URL + "/b.xsd", server.URL + "/more/a2.xsd"})
+	assert.Equal(t, requests, 3)
+
+	catalogPath := filepath.Join(dir, "schemas", "catalog.txt")
+	if err := catalog.WriteFile(catalogPath); err != nil {
+		t.Fatal(err)
+	}
+	header := "# Local copies of remote schemas.\n# a.xsd is maintained by hand.\n"
+	data, err := ioutil.ReadFile(catalogPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data = []byte(header + strings.SplitN(string(data), "\n", 2)[1])
+	if err := ioutil.WriteFile(catalogPath, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	catalog, err = ReadCatalog(catalogPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Rewriting the catalog keeps its notes.
+	if err := catalog.WriteFile(catalogPath); err != nil {
+		t.Fatal(err)
+	}
+	data, err = ioutil.ReadFile(catalogPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(data), header+"http") {
+		t.Errorf("the header of the catalog was not kept:\n%s", data)
+	}
+	server.Close()
+
+	defs, err := (&Loader{Catalog: catalog}).Load(filepath.Join(dir, "service.wsdl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertLoaded(t, defs)
+}
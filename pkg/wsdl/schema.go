@@ -0,0 +1,330 @@
+package wsdl
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Unbounded is the MaxOccurs of an element declared maxOccurs="unbounded".
+const Unbounded = -1
+
+// Schema is an XML schema embedded in the types of a WSDL document.
+type Schema struct {
+	TargetNamespace    string
+	ElementFormDefault string
+
+	Elements     []*Element
+	ComplexTypes []*ComplexType
+	SimpleTypes  []*SimpleType
+	// Includes and Imports hold the schemaLocation of the schemas this one
+	// pulls in.
+	Includes []string
+	Imports  []*Import
+	// Unresolved lists the includes and imports whose schemas have not
+	// been loaded. Only a Loader resolves them.
+	Unresolved []string
+}
+
+// Import pulls in the components of another namespace.
+type Import struct {
+	Namespace      string
+	SchemaLocation string
+}
+
+// Element declares an element, either at the top level of a schema or
+// within a complex type.
+type Element struct {
+	Name string
+	// Ref names a top-level element this declaration stands for.
+	Ref  QName
+	Type QName
+	// ComplexType and SimpleType hold an anonymous type declared inline.
+	ComplexType *ComplexType
+	SimpleType  *SimpleType
+
+	MinOccurs     int
+	MaxOccurs     int
+	Nillable      bool
+	Documentation string
+}
+
+// ComplexType declares the content of elements holding other elements or
+// attributes.
+type ComplexType struct {
+	Name     string
+	Abstract bool
+	// Base is the type this one extends or restricts.
+	Base QName
+	// SimpleContent is set for types holding text with attributes.
+	SimpleContent bool
+	// Model is the compositor of the elements: sequence, all or choice.
+	Model      string
+	Elements   []*Element
+	Attributes []*Attribute
+	// ArrayType is the type of the items of a SOAP encoded array, a type
+	// restricting soapenc:Array.
+	ArrayType QName
+
+	Documentation string
+}
+
+// SimpleType restricts a built-in type, typically to an enumeration.
+type SimpleType struct {
+	Name        string
+	Base        QName
+	Enumeration []string
+	// Patterns are the regular expressions values must match, in the
+	// dialect of XML schema.
+	Patterns []string
+
+	Documentation string
+}
+
+// Attribute declares an attribute of a complex type.
+type Attribute struct {
+	Name string
+	Ref  QName
+	Type QName
+	Use  string
+}
+
+// Schema returns the schema declaring the components of namespace, or nil.
+func (d *Definitions) Schema(namespace string) *Schema {
+	for _, schema := range d.Schemas {
+		if schema.TargetNamespace == namespace {
+			return schema
+		}
+	}
+
+	return nil
+}
+
+// Element returns the top-level element named name, or nil.
+func (d *Definitions) Element(name QName) *Element {
+	for _, schema := range d.Schemas {
+		if schema.TargetNamespace != name.Space {
+			continue
+		}
+		for _, e := range schema.Elements {
+			if e.Name == name.Local {
+				return e
+			}
+		}
+	}
+
+	return nil
+}
+
+// ComplexType returns the complex type named name, or nil.
+func (d *Definitions) ComplexType(name QName) *ComplexType {
+	for _, schema := range d.Schemas {
+		if schema.TargetNamespace != name.Space {
+			continue
+		}
+		for _, ct := range schema.ComplexTypes {
+			if ct.Name == name.Local {
+				return ct
+			}
+		}
+	}
+
+	return nil
+}
+
+// SimpleType returns the simple type named name, or nil.
+func (d *Definitions) SimpleType(name QName) *SimpleType {
+	for _, schema := range d.Schemas {
+		if schema.TargetNamespace != name.Space {
+			continue
+		}
+		for _, st := range schema.SimpleTypes {
+			if st.Name == name.Local {
+				return st
+			}
+		}
+	}
+
+	return nil
+}
+
+// IsBuiltin reports whether name is a type built into XML schema.
+func IsBuiltin(name QName) bool {
+	return name.Space == XSDNamespace
+}
+
+func parseSchema(n *node) *Schema {
+	schema := &Schema{
+		TargetNamespace:    n.attr("targetNamespace"),
+		ElementFormDefault: n.attr("elementFormDefault"),
+	}
+	for _, child := range n.children {
+		if child.name.Space != XSDNamespace {
+			continue
+		}
+		switch child.name.Local {
+		case "element":
+			schema.Elements = append(schema.Elements, parseElement(child))
+		case "complexType":
+			schema.ComplexTypes = append(schema.ComplexTypes, parseComplexType(child))
+		case "simpleType":
+			schema.SimpleTypes = append(schema.SimpleTypes, parseSimpleType(child))
+		case "include":
+			schema.Includes = append(schema.Includes, child.attr("schemaLocation"))
+			schema.Unresolved = append(schema.Unresolved, child.attr("schemaLocation"))
+		case "import":
+			imp := &Import{
+				Namespace:      child.attr("namespace"),
+				SchemaLocation: child.attr("schemaLocation"),
+			}
+			schema.Imports = append(schema.Imports, imp)
+			if imp.SchemaLocation != "" {
+				schema.Unresolved = append(schema.Unresolved, imp.SchemaLocation)
+			}
+		}
+	}
+
+	return schema
+}
+
+func parseElement(n *node) *Element {
+	element := &Element{
+		Name:          n.attr("name"),
+		Ref:           n.qname("ref"),
+		Type:          n.qname("type"),
+		MinOccurs:     occurs(n.attr("minOccurs")),
+		MaxOccurs:     occurs(n.attr("maxOccurs")),
+		Nillable:      n.attr("nillable") == "true",
+		Documentation: schemaDocumentation(n),
+	}
+	if ct := n.first(XSDNamespace, "complexType"); ct != nil {
+		element.ComplexType = parseComplexType(ct)
+	}
+	if st := n.first(XSDNamespace, "simpleType"); st != nil {
+		element.SimpleType = parseSimpleType(st)
+	}
+
+	return element
+}
+
+func parseComplexType(n *node) *ComplexType {
+	ct := &ComplexType{
+		Name:          n.attr("name"),
+		Abstract:      n.attr("abstract") == "true",
+		Documentation: schemaDocumentation(n),
+	}
+
+	content := n
+	for _, kind := range []string{"complexContent", "simpleContent"} {
+		c := n.first(XSDNamespace, kind)
+		if c == nil {
+			continue
+		}
+		ct.SimpleContent = kind == "simpleContent"
+		for _, derivation := range []string{"extension", "restriction"} {
+			if d := c.first(XSDNamespace, derivation); d != nil {
+				ct.Base = d.qname("base")
+				content = d
+			}
+		}
+	}
+
+	parseParticles(ct, content, false, 1)
+	for _, a := range content.all(XSDNamespace, "attribute") {
+		ct.Attributes = append(ct.Attributes, &Attribute{
+			Name: a.attr("name"),
+			Ref:  a.qname("ref"),
+			Type: a.qname("type"),
+			Use:  a.attr("use"),
+		})
+		if arrayType := a.attrNS(Namespace, "arrayType"); arrayType != "" {
+			// The brackets give the rank, as in "xsd:string[]".
+			if i := strings.Index(arrayType, "["); i >= 0 {
+				arrayType = arrayType[:i]
+			}
+			ct.ArrayType = a.resolve(arrayType)
+		}
+	}
+	if ct.Base == (QName{Space: SOAPEncodingNamespace, Local: "Array"}) && ct.ArrayType.IsZero() && len(ct.Elements) == 1 {
+		ct.ArrayType = ct.Elements[0].Type
+	}
+
+	return ct
+}
+
+// parseParticles collects the elements of the model groups below n into ct,
+// flattening nested groups. Elements of a choice or of an optional group are
+// optional themselves, and those of a repeated group repeat as often.
+func parseParticles(ct *ComplexType, n *node, optional bool, repeat int) {
+	for _, child := range n.children {
+		if child.name.Space != XSDNamespace {
+			continue
+		}
+		switch child.name.Local {
+		case "sequence", "all", "choice":
+			if ct.Model == "" {
+				ct.Model = child.name.Local
+			}
+			groupOptional := optional || child.name.Local == "choice" || occurs(child.attr("minOccurs")) == 0
+			parseParticles(ct, child, groupOptional, multiplyOccurs(repeat, occurs(child.attr("maxOccurs"))))
+		case "element":
+			element := parseElement(child)
+			if optional {
+				element.MinOccurs = 0
+			}
+			element.MaxOccurs = multiplyOccurs(element.MaxOccurs, repeat)
+			ct.Elements = append(ct.Elements, element)
+		}
+	}
+}
+
+func multiplyOccurs(a, b int) int {
+	if a == Unbounded || b == Unbounded {
+		return Unbounded
+	}
+
+	return a * b
+}
+
+func parseSimpleType(n *node) *SimpleType {
+	st := &SimpleType{
+		Name:          n.attr("name"),
+		Documentation: schemaDocumentation(n),
+	}
+	if r := n.first(XSDNamespace, "restriction"); r != nil {
+		st.Base = r.qname("base")
+		for _, e := range r.all(XSDNamespace, "enumeration") {
+			st.Enumeration = append(st.Enumeration, e.attr("value"))
+		}
+		for _, p := range r.all(XSDNamespace, "pattern") {
+			st.Patterns = append(st.Patterns, p.attr("value"))
+		}
+	}
+
+	return st
+}
+
+func schemaDocumentation(n *node) string {
+	annotation := n.first(XSDNamespace, "annotation")
+	if annotation == nil {
+		return ""
+	}
+
+	return annotation.documentation(XSDNamespace)
+}
+
+// occurs parses a minOccurs or maxOccurs value, both of which default to 1.
+func occurs(value string) int {
+	value = strings.TrimSpace(value)
+	switch value {
+	case "":
+		return 1
+	case "unbounded":
+		return Unbounded
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 1
+	}
+
+	return n
+}
@@ -0,0 +1,68 @@
+package wsdl
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+)
+
+// Vendor snapshots the remote schemas pulled in by the document at location.
+// Every schema missing from catalog is retrieved with fetch, saved below dir
+// in a directory named after its host and added to catalog. It returns the
+// locations retrieved.
+func Vendor(location string, catalog Catalog, dir string, fetch func(location string) ([]byte, error)) ([]string, error) {
+	fetched := make(map[string][]byte)
+	loader := &Loader{
+		Catalog: catalog,
+		Fetch: func(location string) ([]byte, error) {
+			data, err := fetch(location)
+			if err == nil {
+				fetched[location] = data
+			}
+			return data, err
+		},
+	}
+	if _, err := loader.Load(location); err != nil {
+		return nil, err
+	}
+
+	locations := make([]string, 0, len(fetched))
+	for location, data := range fetched {
+		file, err := vendorPath(dir, location)
+		if err != nil {
+			return nil, err
+		}
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			return nil, err
+		}
+		if err := ioutil.WriteFile(file, data, 0644); err != nil {
+			return nil, err
+		}
+		catalog[location] = file
+		locations = append(locations, location)
+	}
+	sort.Strings(locations)
+
+	return locations, nil
+}
+
+// vendorPath returns the file below dir a copy of location is saved to.
+func vendorPath(dir, location string) (string, error) {
+	u, err := url.Parse(location)
+	if err != nil {
+		return "", err
+	}
+	if u.Host == "" {
+		return "", fmt.Errorf("wsdl: cannot vendor %s, which has no host", location)
+	}
+	p := path.Clean("/" + u.Path)
+	if p == "/" {
+		p = "/index.xsd"
+	}
+
+	return filepath.Join(dir, u.Host, filepath.FromSlash(p)), nil
+}
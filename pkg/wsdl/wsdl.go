@@ -0,0 +1,393 @@
+// Package wsdl reads WSDL 1.1 documents and the XML schemas embedded in them.
+package wsdl
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// Namespaces of the vocabularies a WSDL document is written in.
+const (
+	Namespace       = "http://schemas.xmlsoap.org/wsdl/"
+	SOAPNamespace   = "http://schemas.xmlsoap.org/wsdl/soap/"
+	SOAP12Namespace = "http://schemas.xmlsoap.org/wsdl/soap12/"
+	XSDNamespace    = "http://www.w3.org/2001/XMLSchema"
+	// SOAPEncodingNamespace holds the types of the SOAP 1.1 encoding, such
+	// as soapenc:Array, used by rpc/encoded services.
+	SOAPEncodingNamespace = "http://schemas.xmlsoap.org/soap/encoding/"
+)
+
+// QName is a name qualified by the namespace it was declared in.
+type QName struct {
+	Space string
+	Local string
+}
+
+func (q QName) String() string {
+	if q.Space == "" {
+		return q.Local
+	}
+
+	return "{" + q.Space + "}" + q.Local
+}
+
+// IsZero reports whether q names nothing.
+func (q QName) IsZero() bool {
+	return q.Local == ""
+}
+
+// Definitions is a parsed WSDL document.
+type Definitions struct {
+	Name            string
+	TargetNamespace string
+	Documentation   string
+
+	Schemas   []*Schema
+	Messages  []*Message
+	PortTypes []*PortType
+	Bindings  []*Binding
+	Services  []*Service
+}
+
+// Message is an abstract message exchanged by an operation.
+type Message struct {
+	Name  string
+	Parts []*Part
+}
+
+// Part is a message part, described either by a schema element (document
+// style) or by a schema type (rpc style).
+type Part struct {
+	Name    string
+	Element QName
+	Type    QName
+}
+
+// PortType is an abstract interface, a set of operations.
+type PortType struct {
+	Name       string
+	Operations []*Operation
+}
+
+// Operation is an operation of a port type.
+type Operation struct {
+	Name          string
+	Documentation string
+	Input         *OperationMessage
+	Output        *OperationMessage
+	Faults        []*OperationMessage
+}
+
+// OperationMessage refers to the message an operation sends or receives.
+type OperationMessage struct {
+	Name    string
+	Message QName
+}
+
+// Binding ties a port type to a protocol.
+type Binding struct {
+	Name string
+	Type QName
+	// SOAPVersion is "1.1" or "1.2" for SOAP bindings and empty otherwise.
+	SOAPVersion string
+	Style       string
+	Transport   string
+	Operations  []*BindingOperation
+}
+
+// BindingOperation tells how an operation is carried by its binding.
+type BindingOperation struct {
+	Name       string
+	SOAPAction string
+	Style      string
+	Input      *BindingBody
+	Output     *BindingBody
+}
+
+// BindingBody tells how a message is carried in the SOAP body.
+type BindingBody struct {
+	Use           string
+	Namespace     string
+	EncodingStyle string
+}
+
+// Service groups the ports a service is reachable at.
+type Service struct {
+	Name          string
+	Documentation string
+	Ports         []*Port
+}
+
+// Port is the address of an endpoint speaking a binding.
+type Port struct {
+	Name    string
+	Binding QName
+	Address string
+}
+
+// Message returns the message named name, or nil.
+func (d *Definitions) Message(name QName) *Message {
+	if d.owns(name) {
+		for _, m := range d.Messages {
+			if m.Name == name.Local {
+				return m
+			}
+		}
+	}
+
+	return nil
+}
+
+// PortType returns the port type named name, or nil.
+func (d *Definitions) PortType(name QName) *PortType {
+	if d.owns(name) {
+		for _, pt := range d.PortTypes {
+			if pt.Name == name.Local {
+				return pt
+			}
+		}
+	}
+
+	return nil
+}
+
+// Binding returns the binding named name, or nil.
+func (d *Definitions) Binding(name QName) *Binding {
+	if d.owns(name) {
+		for _, b := range d.Bindings {
+			if b.Name == name.Local {
+				return b
+			}
+		}
+	}
+
+	return nil
+}
+
+// Ports returns the ports of all services speaking the binding named name.
+func (d *Definitions) Ports(binding QName) []*Port {
+	var ports []*Port
+	for _, s := range d.Services {
+		for _, p := range s.Ports {
+			if p.Binding == binding {
+				ports = append(ports, p)
+			}
+		}
+	}
+
+	return ports
+}
+
+// SOAPBinding returns the SOAP binding of portType, preferring SOAP 1.1,
+// or nil if there is none.
+func (d *Definitions) SOAPBinding(portType *PortType) *Binding {
+	var found *Binding
+	for _, b := range d.Bindings {
+		if b.SOAPVersion == "" || d.PortType(b.Type) != portType {
+			continue
+		}
+		if found == nil || b.SOAPVersion == "1.1" && found.SOAPVersion != "1.1" {
+			found = b
+		}
+	}
+
+	return found
+}
+
+// Address returns the address of the first port speaking binding, or an
+// empty string.
+func (d *Definitions) Address(binding *Binding) string {
+	for _, p := range d.Ports(QName{Space: d.TargetNamespace, Local: binding.Name}) {
+		if p.Address != "" {
+			return p.Address
+		}
+	}
+
+	return ""
+}
+
+// owns reports whether name lies in the target namespace of d.
+func (d *Definitions) owns(name QName) bool {
+	return name.Space == d.TargetNamespace
+}
+
+// Operation returns the operation named name, or nil.
+func (pt *PortType) Operation(name string) *Operation {
+	for _, o := range pt.Operations {
+		if o.Name == name {
+			return o
+		}
+	}
+
+	return nil
+}
+
+// Operation returns the binding of the operation named name, or nil.
+func (b *Binding) Operation(name string) *BindingOperation {
+	for _, o := range b.Operations {
+		if o.Name == name {
+			return o
+		}
+	}
+
+	return nil
+}
+
+// ParseFile reads the WSDL document at path. The schemas it includes or
+// imports are left unresolved; a Loader reads those as well.
+func ParseFile(path string) (*Definitions, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return Parse(f)
+}
+
+// Parse reads a WSDL document from r.
+func Parse(r io.Reader) (*Definitions, error) {
+	root, err := parseNode(r)
+	if err != nil {
+		return nil, err
+	}
+	if !root.is(Namespace, "definitions") {
+		return nil, fmt.Errorf("wsdl: root element is %s, not definitions", QName(root.name))
+	}
+
+	defs := &Definitions{
+		Name:            root.attr("name"),
+		TargetNamespace: root.attr("targetNamespace"),
+		Documentation:   root.documentation(Namespace),
+	}
+	for _, types := range root.all(Namespace, "types") {
+		for _, n := range types.all(XSDNamespace, "schema") {
+			defs.Schemas = append(defs.Schemas, parseSchema(n))
+		}
+	}
+	for _, n := range root.all(Namespace, "message") {
+		defs.Messages = append(defs.Messages, parseMessage(n))
+	}
+	for _, n := range root.all(Namespace, "portType") {
+		defs.PortTypes = append(defs.PortTypes, parsePortType(n))
+	}
+	for _, n := range root.all(Namespace, "binding") {
+		defs.Bindings = append(defs.Bindings, parseBinding(n))
+	}
+	for _, n := range root.all(Namespace, "service") {
+		defs.Services = append(defs.Services, parseService(n))
+	}
+
+	return defs, nil
+}
+
+func parseMessage(n *node) *Message {
+	message := &Message{Name: n.attr("name")}
+	for _, p := range n.all(Namespace, "part") {
+		message.Parts = append(message.Parts, &Part{
+			Name:    p.attr("name"),
+			Element: p.qname("element"),
+			Type:    p.qname("type"),
+		})
+	}
+
+	return message
+}
+
+func parsePortType(n *node) *PortType {
+	portType := &PortType{Name: n.attr("name")}
+	for _, o := range n.all(Namespace, "operation") {
+		operation := &Operation{
+			Name:          o.attr("name"),
+			Documentation: o.documentation(Namespace),
+			Input:         parseOperationMessage(o.first(Namespace, "input")),
+			Output:        parseOperationMessage(o.first(Namespace, "output")),
+		}
+		for _, f := range o.all(Namespace, "fault") {
+			operation.Faults = append(operation.Faults, parseOperationMessage(f))
+		}
+		portType.Operations = append(portType.Operations, operation)
+	}
+
+	return portType
+}
+
+func parseOperationMessage(n *node) *OperationMessage {
+	if n == nil {
+		return nil
+	}
+
+	return &OperationMessage{Name: n.attr("name"), Message: n.qname("message")}
+}
+
+func parseBinding(n *node) *Binding {
+	binding := &Binding{Name: n.attr("name"), Type: n.qname("type")}
+
+	soapNS := ""
+	for _, ns := range []string{SOAPNamespace, SOAP12Namespace} {
+		if b := n.first(ns, "binding"); b != nil {
+			soapNS = ns
+			binding.Style = b.attr("style")
+			binding.Transport = b.attr("transport")
+		}
+	}
+	switch soapNS {
+	case SOAPNamespace:
+		binding.SOAPVersion = "1.1"
+	case SOAP12Namespace:
+		binding.SOAPVersion = "1.2"
+	}
+
+	for _, o := range n.all(Namespace, "operation") {
+		operation := &BindingOperation{Name: o.attr("name")}
+		if soapNS != "" {
+			if so := o.first(soapNS, "operation"); so != nil {
+				operation.SOAPAction = so.attr("soapAction")
+				operation.Style = so.attr("style")
+			}
+			operation.Input = parseBindingBody(o.first(Namespace, "input"), soapNS)
+			operation.Output = parseBindingBody(o.first(Namespace, "output"), soapNS)
+		}
+		if operation.Style == "" {
+			operation.Style = binding.Style
+		}
+		if operation.Style == "" {
+			operation.Style = "document"
+		}
+		binding.Operations = append(binding.Operations, operation)
+	}
+
+	return binding
+}
+
+func parseBindingBody(n *node, soapNS string) *BindingBody {
+	if n == nil {
+		return nil
+	}
+	body := n.first(soapNS, "body")
+	if body == nil {
+		return nil
+	}
+
+	return &BindingBody{
+		Use:           body.attr("use"),
+		Namespace:     body.attr("namespace"),
+		EncodingStyle: body.attr("encodingStyle"),
+	}
+}
+
+func parseService(n *node) *Service {
+	service := &Service{Name: n.attr("name"), Documentation: n.documentation(Namespace)}
+	for _, p := range n.all(Namespace, "port") {
+		port := &Port{Name: p.attr("name"), Binding: p.qname("binding")}
+		for _, ns := range []string{SOAPNamespace, SOAP12Namespace} {
+			if address := p.first(ns, "address"); address != nil {
+				port.Address = address.attr("location")
+			}
+		}
+		service.Ports = append(service.Ports, port)
+	}
+
+	return service
+}
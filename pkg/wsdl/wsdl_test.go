@@ -0,0 +1,190 @@
+package wsdl
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/magiconair/properties/assert"
+)
+
+const tempuri = "http://tempuri.org/"
+
+func TestParseCalculator(t *testing.T) {
+	defs, err := ParseFile("../calculator.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, defs.TargetNamespace, tempuri)
+	assert.Equal(t, len(defs.Schemas), 1)
+	assert.Equal(t, len(defs.Messages), 8)
+	assert.Equal(t, len(defs.Bindings), 2)
+
+	portType := defs.PortType(QName{Space: tempuri, Local: "CalculatorSoap"})
+	if portType == nil {
+		t.Fatal("port type CalculatorSoap not found")
+	}
+	add := portType.Operation("Add")
+	assert.Equal(t, add.Documentation, "Adds two integers. This is a test WebService. ©DNE Online")
+	assert.Equal(t, add.Input.Message, QName{Space: tempuri, Local: "AddSoapIn"})
+
+	message := defs.Message(add.Input.Message)
+	assert.Equal(t, message.Parts[0].Element, QName{Space: tempuri, Local: "Add"})
+	element := defs.Element(message.Parts[0].Element)
+	assert.Equal(t, element.ComplexType.Model, "sequence")
+	assert.Equal(t, len(element.ComplexType.Elements), 2)
+	intA := element.ComplexType.Elements[0]
+	assert.Equal(t, intA.Name, "intA")
+	assert.Equal(t, intA.Type, QName{Space: XSDNamespace, Local: "int"})
+	assert.Equal(t, IsBuiltin(intA.Type), true)
+	assert.Equal(t, intA.MinOccurs, 1)
+	assert.Equal(t, intA.MaxOccurs, 1)
+
+	for _, version := range []string{"1.1", "1.2"} {
+		var binding *Binding
+		for _, b := range defs.Bindings {
+			if b.SOAPVersion == version {
+				binding = b
+			}
+		}
+		if binding == nil {
+			t.Fatalf("no SOAP %s binding", version)
+		}
+		assert.Equal(t, defs.PortType(binding.Type), portType)
+		op := binding.Operation("Divide")
+		assert.Equal(t, op.SOAPAction, "http://tempuri.org/Divide")
+		assert.Equal(t, op.Style, "document")
+		assert.Equal(t, op.Input.Use, "literal")
+
+		ports := defs.Ports(QName{Space: tempuri, Local: binding.Name})
+		assert.Equal(t, len(ports), 1)
+		assert.Equal(t, ports[0].Address, "http://www.dneonline.com/calculator.asmx")
+	}
+}
+
+func TestParseIncludes(t *testing.T) {
+	defs, err := ParseFile("../AmazonS3.wsdl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, defs.Schemas[0].Includes, []string{"http://doc.s3.amazonaws.com/2006-03-01/AmazonS3.xsd"})
+}
+
+func TestParseSchema(t *testing.T) {
+	defs, err := Parse(strings.NewReader(`<definitions xmlns="http://schemas.xmlsoap.org/wsdl/" targetNamespace="urn:test">
+  <types>
+    <xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" xmlns:t="urn:test" targetNamespace="urn:test">
+      <xs:simpleType name="Color">
+        <xs:restriction base="xs:string">
+          <xs:enumeration value="red"/>
+          <xs:enumeration value="green"/>
+        </xs:restriction>
+      </xs:simpleType>
+      <xs:simpleType name="Code">
+        <xs:restriction base="xs:string">
+          <xs:pattern value="[A-Z]{3}"/>
+        </xs:restriction>
+      </xs:simpleType>
+      <xs:complexType name="Shape" abstract="true">
+        <xs:sequence>
+          <xs:element name="color" type="t:Color" minOccurs="0"/>
+        </xs:sequence>
+        <xs:attribute name="id" type="xs:ID" use="required"/>
+      </xs:complexType>
+      <xs:complexType name="Circle">
+        <xs:complexContent>
+          <xs:extension base="t:Shape">
+            <xs:choice>
+              <xs:element name="radius" type="xs:double"/>
+              <xs:element name="diameter" type="xs:double"/>
+            </xs:choice>
+          </xs:extension>
+        </xs:complexContent>
+      </xs:complexType>
+      <xs:complexType name="Label">
+        <xs:simpleContent>
+          <xs:extension base="xs:string">
+            <xs:attribute name="lang" type="xs:language"/>
+          </xs:extension>
+        </xs:simpleContent>
+      </xs:complexType>
+      <xs:element name="Drawing">
+        <xs:complexType>
+          <xs:sequence>
+            <xs:element name="shape" type="t:Shape" maxOccurs="unbounded"/>
+          </xs:sequence>
+        </xs:complexType>
+      </xs:element>
+    </xs:schema>
+  </types>
+</definitions>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	color := defs.SimpleType(QName{Space: "urn:test", Local: "Color"})
+	assert.Equal(t, color.Base, QName{Space: XSDNamespace, Local: "string"})
+	assert.Equal(t, color.Enumeration, []string{"red", "green"})
+	assert.Equal(t, defs.SimpleType(QName{Space: "urn:test", Local: "Code"}).Patterns, []string{"[A-Z]{3}"})
+
+	shape := defs.ComplexType(QName{Space: "urn:test", Local: "Shape"})
+	assert.Equal(t, shape.Abstract, true)
+	assert.Equal(t, shape.Elements[0].MinOccurs, 0)
+	assert.Equal(t, shape.Attributes[0].Use, "required")
+
+	circle := defs.ComplexType(QName{Space: "urn:test", Local: "Circle"})
+	assert.Equal(t, circle.Base, QName{Space: "urn:test", Local: "Shape"})
+	assert.Equal(t, circle.Model, "choice")
+	assert.Equal(t, circle.Elements[0].MinOccurs, 0)
+
+	label := defs.ComplexType(QName{Space: "urn:test", Local: "Label"})
+	assert.Equal(t, label.SimpleContent, true)
+	assert.Equal(t, label.Attributes[0].Name, "lang")
+
+	drawing := defs.Element(QName{Space: "urn:test", Local: "Drawing"})
+	assert.Equal(t, drawing.ComplexType.Elements[0].MaxOccurs, Unbounded)
+
+	if defs.ComplexType(QName{Space: "urn:other", Local: "Shape"}) != nil {
+		t.Error("lookup ignored the namespace")
+	}
+}
+
+func TestParseRejectsOtherDocuments(t *testing.T) {
+	_, err := Parse(strings.NewReader(`<schema xmlns="http://www.w3.org/2001/XMLSchema"/>`))
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+}
+
+func TestParseRepeatedGroups(t *testing.T) {
+	defs, err := Parse(strings.NewReader(`<definitions xmlns="http://schemas.xmlsoap.org/wsdl/">
+  <types>
+    <xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema">
+      <xs:complexType name="Listing">
+        <xs:sequence>
+          <xs:element name="Name" type="xs:string"/>
+          <xs:choice minOccurs="0" maxOccurs="unbounded">
+            <xs:element name="Version" type="xs:string"/>
+            <xs:element name="DeleteMarker" type="xs:string"/>
+          </xs:choice>
+          <xs:sequence maxOccurs="2">
+            <xs:element name="Pair" type="xs:int" maxOccurs="2"/>
+          </xs:sequence>
+        </xs:sequence>
+      </xs:complexType>
+    </xs:schema>
+  </types>
+</definitions>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	listing := defs.ComplexType(QName{Local: "Listing"})
+	occurs := make(map[string][2]int)
+	for _, e := range listing.Elements {
+		occurs[e.Name] = [2]int{e.MinOccurs, e.MaxOccurs}
+	}
+	assert.Equal(t, occurs["Name"], [2]int{1, 1})
+	assert.Equal(t, occurs["Version"], [2]int{0, Unbounded})
+	assert.Equal(t, occurs["DeleteMarker"], [2]int{0, Unbounded})
+	assert.Equal(t, occurs["Pair"], [2]int{1, 4})
+}
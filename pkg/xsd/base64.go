@@ -0,0 +1,38 @@
+package xsd
+
+import (
+	"encoding/base64"
+	"strings"
+)
+
+// Base64Binary is an xsd:base64Binary value. encoding/xml writes a plain
+// []byte as raw text, which corrupts binary data and is not what the schema
+// type asks for.
+type Base64Binary []byte
+
+func (b Base64Binary) MarshalText() ([]byte, error) {
+	text := make([]byte, base64.StdEncoding.EncodedLen(len(b)))
+	base64.StdEncoding.Encode(text, b)
+
+	return text, nil
+}
+
+func (b *Base64Binary) UnmarshalText(text []byte) error {
+	// The lexical space allows whitespace anywhere, line wrapped output
+	// included.
+	clean := strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '\t', '\r', '\n':
+			return -1
+		}
+		return r
+	}, string(text))
+
+	data, err := base64.StdEncoding.DecodeString(clean)
+	if err != nil {
+		return err
+	}
+	*b = data
+
+	return nil
+}
@@ -0,0 +1,232 @@
+package xsd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Layouts of the date and time types, with the time zone left out. The
+// fraction of seconds is written only when there is one.
+const (
+	dateTimeLayout = "2006-01-02T15:04:05.999999999"
+	dateLayout     = "2006-01-02"
+	timeLayout     = "15:04:05.999999999"
+	zoneLayout     = "Z07:00"
+)
+
+// DateTime is an xsd:dateTime value. The zero DateTime is written as
+// 0001-01-01T00:00:00Z like any other; optional elements and attributes hold
+// a *DateTime, nil when absent.
+type DateTime struct {
+	time.Time
+	// NoZone is set for values without a time zone, which XML Schema leaves
+	// unanchored. They are read as UTC and written back without a zone.
+	NoZone bool
+}
+
+// ParseDateTime parses the lexical form of xsd:dateTime, as in
+// 2002-10-10T12:00:00-05:00, with or without fraction of seconds and zone.
+func ParseDateTime(s string) (DateTime, error) {
+	t, noZone, err := parseZoned(dateTimeLayout, s)
+	if err != nil {
+		return DateTime{}, fmt.Errorf("xsd: %q is not a valid dateTime", s)
+	}
+
+	return DateTime{Time: t, NoZone: noZone}, nil
+}
+
+func (t DateTime) String() string {
+	return formatZoned(t.Time, dateTimeLayout, t.NoZone)
+}
+
+func (t DateTime) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+func (t *DateTime) UnmarshalText(text []byte) error {
+	if len(strings.TrimSpace(string(text))) == 0 {
+		*t = DateTime{}
+		return nil
+	}
+	parsed, err := ParseDateTime(string(text))
+	if err != nil {
+		return err
+	}
+	*t = parsed
+
+	return nil
+}
+
+func (t DateTime) MarshalJSON() ([]byte, error) {
+	return jsonUnlessZero(t.IsZero(), t.String())
+}
+
+func (t *DateTime) UnmarshalJSON(data []byte) error {
+	return unmarshalJSONText(data, t)
+}
+
+// Date is an xsd:date value: a day, with an optional time zone. The time of
+// day is ignored.
+type Date struct {
+	time.Time
+	// NoZone is set for dates without a time zone, the common case. Dates
+	// are written with the zone of Time otherwise.
+	NoZone bool
+}
+
+// NewDate returns the date of year, month and day, without a time zone.
+func NewDate(year int, month time.Month, day int) Date {
+	return Date{Time: time.Date(year, month, day, 0, 0, 0, 0, time.UTC), NoZone: true}
+}
+
+// ParseDate parses the lexical form of xsd:date, as in 2002-10-10 or
+// 2002-10-10+13:00.
+func ParseDate(s string) (Date, error) {
+	t, noZone, err := parseZoned(dateLayout, s)
+	if err != nil {
+		return Date{}, fmt.Errorf("xsd: %q is not a valid date", s)
+	}
+
+	return Date{Time: t, NoZone: noZone}, nil
+}
+
+func (d Date) String() string {
+	return formatZoned(d.Time, dateLayout, d.NoZone)
+}
+
+func (d Date) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+func (d *Date) UnmarshalText(text []byte) error {
+	if len(strings.TrimSpace(string(text))) == 0 {
+		*d = Date{}
+		return nil
+	}
+	parsed, err := ParseDate(string(text))
+	if err != nil {
+		return err
+	}
+	*d = parsed
+
+	return nil
+}
+
+func (d Date) MarshalJSON() ([]byte, error) {
+	return jsonUnlessZero(d.IsZero(), d.String())
+}
+
+func (d *Date) UnmarshalJSON(data []byte) error {
+	return unmarshalJSONText(data, d)
+}
+
+// Time is an xsd:time value: a time of day, with an optional time zone. The
+// date is ignored. Parsed values fall on January 1st of year 0, as with
+// time.Parse, so midnight is not mistaken for the zero Time, which is null
+// in JSON.
+type Time struct {
+	time.Time
+	// NoZone is set for times without a time zone. Times are written with
+	// the zone of Time otherwise.
+	NoZone bool
+}
+
+// NewTime returns the time of day hour:min:sec.nsec in loc, or without a
+// time zone for a nil loc.
+func NewTime(hour, min, sec, nsec int, loc *time.Location) Time {
+	if loc == nil {
+		return Time{Time: time.Date(0, 1, 1, hour, min, sec, nsec, time.UTC), NoZone: true}
+	}
+
+	return Time{Time: time.Date(0, 1, 1, hour, min, sec, nsec, loc)}
+}
+
+// ParseTime parses the lexical form of xsd:time, as in 13:20:00 or
+// 13:20:00.5-05:00.
+func ParseTime(s string) (Time, error) {
+	t, noZone, err := parseZoned(timeLayout, s)
+	if err != nil {
+		return Time{}, fmt.Errorf("xsd: %q is not a valid time", s)
+	}
+
+	return Time{Time: t, NoZone: noZone}, nil
+}
+
+func (t Time) String() string {
+	return formatZoned(t.Time, timeLayout, t.NoZone)
+}
+
+func (t Time) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+func (t *Time) UnmarshalText(text []byte) error {
+	if len(strings.TrimSpace(string(text))) == 0 {
+		*t = Time{}
+		return nil
+	}
+	parsed, err := ParseTime(string(text))
+	if err != nil {
+		return err
+	}
+	*t = parsed
+
+	return nil
+}
+
+func (t Time) MarshalJSON() ([]byte, error) {
+	return jsonUnlessZero(t.IsZero(), t.String())
+}
+
+func (t *Time) UnmarshalJSON(data []byte) error {
+	return unmarshalJSONText(data, t)
+}
+
+// parseZoned parses s in layout followed by an optional time zone, and
+// reports whether the zone was missing. Values without one are read as UTC.
+func parseZoned(layout, s string) (time.Time, bool, error) {
+	s = strings.TrimSpace(s)
+	if t, err := time.Parse(layout+zoneLayout, s); err == nil {
+		return t, false, nil
+	}
+	t, err := time.Parse(layout, s)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+
+	return t, true, nil
+}
+
+func formatZoned(t time.Time, layout string, noZone bool) string {
+	if noZone {
+		return t.Format(layout)
+	}
+
+	return t.Format(layout + zoneLayout)
+}
+
+// The types embed time.Time, whose JSON methods would write RFC 3339
+// timestamps. They are written in their lexical form instead, and as null
+// when zero.
+
+func jsonUnlessZero(zero bool, text string) ([]byte, error) {
+	if zero {
+		return []byte("null"), nil
+	}
+
+	return json.Marshal(text)
+}
+
+func unmarshalJSONText(data []byte, value interface{ UnmarshalText([]byte) error }) error {
+	if string(data) == "null" {
+		return value.UnmarshalText(nil)
+	}
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return err
+	}
+
+	return value.UnmarshalText([]byte(text))
+}
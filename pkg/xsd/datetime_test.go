@@ -0,0 +1,179 @@
+package xsd
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"testing"
+	"time"
+
+	"github.com/magiconair/properties/assert"
+)
+
+type event struct {
+	XMLName xml.Name  `xml:"event"`
+	At      *DateTime `xml:"At,omitempty"`
+	On      Date      `xml:"On"`
+	Starts  Time      `xml:"Starts"`
+	Lasts   *Duration `xml:"Lasts,omitempty"`
+	Stamp   *DateTime `xml:"stamp,attr,omitempty"`
+}
+
+func TestDateTime(t *testing.T) {
+	for _, test := range []struct {
+		in, out string
+	}{
+		{"2002-10-10T12:00:00-05:00", "2002-10-10T12:00:00-05:00"},
+		{"2002-10-10T17:00:00Z", "2002-10-10T17:00:00Z"},
+		{" 2002-10-10T12:00:00.250+07:00 ", "2002-10-10T12:00:00.25+07:00"},
+		{"2002-10-10T12:00:00", "2002-10-10T12:00:00"},
+	} {
+		dt, err := ParseDateTime(test.in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, dt.String(), test.out)
+	}
+
+	dt, _ := ParseDateTime("2002-10-10T12:00:00-05:00")
+	assert.Equal(t, dt.UTC(), time.Date(2002, 10, 10, 17, 0, 0, 0, time.UTC))
+	dt, _ = ParseDateTime("2002-10-10T12:00:00")
+	assert.Equal(t, dt.NoZone, true)
+	assert.Equal(t, dt.Location(), time.UTC)
+
+	if _, err := ParseDateTime("2002-10-10"); err == nil {
+		t.Error("expected an error for a date")
+	}
+}
+
+func TestDate(t *testing.T) {
+	d, err := ParseDate("2002-10-10")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, d, NewDate(2002, time.October, 10))
+	assert.Equal(t, d.String(), "2002-10-10")
+
+	d, err = ParseDate("2002-10-10+13:00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, d.String(), "2002-10-10+13:00")
+	assert.Equal(t, Date{Time: time.Date(2002, 10, 10, 23, 0, 0, 0, time.UTC)}.String(), "2002-10-10Z")
+
+	if _, err := ParseDate("2002-10-10T12:00:00"); err == nil {
+		t.Error("expected an error for a dateTime")
+	}
+}
+
+func TestTime(t *testing.T) {
+	tm, err := ParseTime("00:00:00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, tm.IsZero(), false)
+	assert.Equal(t, tm, NewTime(0, 0, 0, 0, nil))
+	assert.Equal(t, tm.String(), "00:00:00")
+
+	tm, err = ParseTime("13:20:00.5-05:00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, tm.String(), "13:20:00.5-05:00")
+	assert.Equal(t, NewTime(8, 30, 0, 0, time.UTC).String(), "08:30:00Z")
+}
+
+func TestDuration(t *testing.T) {
+	for _, test := range []struct {
+		in, out string
+		d       Duration
+	}{
+		{"P1Y2M3DT10H30M", "P1Y2M3DT10H30M", Duration{Years: 1, Months: 2, Days: 3, Hours: 10, Minutes: 30}},
+		{"-P120D", "-P120D", Duration{Negative: true, Days: 120}},
+		{"PT1.5S", "PT1.5S", Duration{Seconds: 1.5}},
+		{"PT0S", "PT0S", Duration{}},
+		{"P0D", "PT0S", Duration{}},
+	} {
+		d, err := ParseDuration(test.in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, d, test.d)
+		assert.Equal(t, d.String(), test.out)
+	}
+
+	for _, bad := range []string{"P", "PT", "P1H", "1D", "P1DT"} {
+		if _, err := ParseDuration(bad); err == nil {
+			t.Errorf("expected an error for %q", bad)
+		}
+	}
+
+	assert.Equal(t, NewDuration(90*time.Minute+500*time.Millisecond).String(), "PT1H30M0.5S")
+	assert.Equal(t, Duration{Days: 1, Hours: 2}.Duration(), 26*time.Hour)
+}
+
+func TestDateTimeXML(t *testing.T) {
+	out, err := xml.Marshal(&event{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, string(out), "<event><On>0001-01-01Z</On><Starts>00:00:00Z</Starts></event>")
+
+	at, _ := ParseDateTime("2002-10-10T12:00:00Z")
+	lasts := Duration{Hours: 2}
+	in := &event{
+		At:     &at,
+		On:     NewDate(2002, time.October, 10),
+		Starts: NewTime(9, 0, 0, 0, nil),
+		Lasts:  &lasts,
+		Stamp:  &at,
+	}
+	out, err = xml.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, string(out), `<event stamp="2002-10-10T12:00:00Z"><At>2002-10-10T12:00:00Z</At>`+
+		`<On>2002-10-10</On><Starts>09:00:00</Starts><Lasts>PT2H</Lasts></event>`)
+
+	var back event
+	if err := xml.Unmarshal(out, &back); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, back.At.Equal(at.Time), true)
+	assert.Equal(t, back.On, in.On)
+	assert.Equal(t, back.Starts, in.Starts)
+	assert.Equal(t, *back.Lasts, lasts)
+
+	if err := xml.Unmarshal([]byte("<event><On></On><On>2002-10-10</On></event>"), &back); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDateTimeJSON(t *testing.T) {
+	at, _ := ParseDateTime("2002-10-10T12:00:00-05:00")
+	in := struct {
+		At     DateTime
+		On     Date
+		Starts Time
+		Lasts  Duration
+		Never  Date
+	}{at, NewDate(2002, time.October, 10), NewTime(9, 0, 0, 0, nil), Duration{Hours: 2}, Date{}}
+	out, err := json.Marshal(&in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, string(out), `{"At":"2002-10-10T12:00:00-05:00","On":"2002-10-10","Starts":"09:00:00","Lasts":"PT2H","Never":null}`)
+
+	back := in
+	back.Never = NewDate(2000, time.January, 1)
+	if err := json.Unmarshal(out, &back); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, back.At.String(), in.At.String())
+	assert.Equal(t, back.On, in.On)
+	assert.Equal(t, back.Starts, in.Starts)
+	assert.Equal(t, back.Never.IsZero(), true)
+
+	if err := json.Unmarshal([]byte(`{"On":"2002-10-10T00:00:00Z"}`), &back); err == nil {
+		t.Error("expected an error for a timestamp given as a date")
+	}
+}
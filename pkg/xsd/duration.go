@@ -0,0 +1,133 @@
+package xsd
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Duration is an xsd:duration value such as P1Y2M3DT10H30M. Years and
+// months have no fixed length, so the components are kept as written
+// rather than as a time.Duration. Unlike the date and time types, a zero
+// Duration is a value, written PT0S.
+type Duration struct {
+	Negative bool
+	Years    int
+	Months   int
+	Days     int
+	Hours    int
+	Minutes  int
+	Seconds  float64
+}
+
+var durationPattern = regexp.MustCompile(`^(-)?P(?:(\d+)Y)?(?:(\d+)M)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+(?:\.\d*)?|\.\d+)S)?)?$`)
+
+// NewDuration returns d as a duration of hours, minutes and seconds.
+func NewDuration(d time.Duration) Duration {
+	var duration Duration
+	if d < 0 {
+		duration.Negative = true
+		d = -d
+	}
+	duration.Hours = int(d / time.Hour)
+	d -= time.Duration(duration.Hours) * time.Hour
+	duration.Minutes = int(d / time.Minute)
+	d -= time.Duration(duration.Minutes) * time.Minute
+	duration.Seconds = d.Seconds()
+
+	return duration
+}
+
+// ParseDuration parses the lexical form of xsd:duration.
+func ParseDuration(s string) (Duration, error) {
+	s = strings.TrimSpace(s)
+	m := durationPattern.FindStringSubmatch(s)
+	// P alone, or a T without a time component, is not allowed.
+	if m == nil || s == "P" || s == "-P" || strings.HasSuffix(s, "T") {
+		return Duration{}, fmt.Errorf("xsd: %q is not a valid duration", s)
+	}
+
+	d := Duration{Negative: m[1] == "-"}
+	for i, component := range []*int{&d.Years, &d.Months, &d.Days, &d.Hours, &d.Minutes} {
+		if m[i+2] == "" {
+			continue
+		}
+		n, err := strconv.Atoi(m[i+2])
+		if err != nil {
+			return Duration{}, fmt.Errorf("xsd: %q is not a valid duration", s)
+		}
+		*component = n
+	}
+	if m[7] != "" {
+		seconds, err := strconv.ParseFloat(m[7], 64)
+		if err != nil {
+			return Duration{}, fmt.Errorf("xsd: %q is not a valid duration", s)
+		}
+		d.Seconds = seconds
+	}
+
+	return d, nil
+}
+
+// Duration returns d as a time.Duration, taking years as 365 days and months
+// as 30.
+func (d Duration) Duration() time.Duration {
+	days := d.Years*365 + d.Months*30 + d.Days
+	total := time.Duration(days)*24*time.Hour +
+		time.Duration(d.Hours)*time.Hour +
+		time.Duration(d.Minutes)*time.Minute +
+		time.Duration(d.Seconds*float64(time.Second))
+	if d.Negative {
+		return -total
+	}
+
+	return total
+}
+
+func (d Duration) String() string {
+	var b strings.Builder
+	if d.Negative {
+		b.WriteByte('-')
+	}
+	b.WriteByte('P')
+	for _, c := range []struct {
+		n    int
+		unit byte
+	}{{d.Years, 'Y'}, {d.Months, 'M'}, {d.Days, 'D'}} {
+		if c.n != 0 {
+			fmt.Fprintf(&b, "%d%c", c.n, c.unit)
+		}
+	}
+
+	if d.Hours != 0 || d.Minutes != 0 || d.Seconds != 0 || b.Len() == 1 || d.Negative && b.Len() == 2 {
+		b.WriteByte('T')
+		if d.Hours != 0 {
+			fmt.Fprintf(&b, "%dH", d.Hours)
+		}
+		if d.Minutes != 0 {
+			fmt.Fprintf(&b, "%dM", d.Minutes)
+		}
+		if d.Seconds != 0 || d.Hours == 0 && d.Minutes == 0 {
+			b.WriteString(strconv.FormatFloat(d.Seconds, 'f', -1, 64))
+			b.WriteByte('S')
+		}
+	}
+
+	return b.String()
+}
+
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+func (d *Duration) UnmarshalText(text []byte) error {
+	parsed, err := ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = parsed
+
+	return nil
+}
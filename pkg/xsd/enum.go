@@ -0,0 +1,47 @@
+package xsd
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+)
+
+var lenientEnums atomic.Bool
+
+// SetLenientEnums lets generated enumerations marshal and unmarshal values
+// outside their enumeration, for services adding values ahead of their
+// contracts, or makes them strict again. Validate still reports such
+// values. The setting applies to the whole process and is safe to change
+// while calls are in flight.
+func SetLenientEnums(lenient bool) {
+	lenientEnums.Store(lenient)
+}
+
+// LenientEnums reports whether generated enumerations accept values outside
+// their enumeration, as set with SetLenientEnums.
+func LenientEnums() bool {
+	return lenientEnums.Load()
+}
+
+// EnumError reports a value outside an enumeration.
+type EnumError struct {
+	// Type is the name of the enumerated type.
+	Type   string
+	Value  string
+	Values []string
+}
+
+func (e *EnumError) Error() string {
+	return fmt.Sprintf("xsd: %q is not a valid %s; expected one of %s", e.Value, e.Type, strings.Join(e.Values, ", "))
+}
+
+// ValidateEnum returns an *EnumError unless value is one of values.
+func ValidateEnum(typeName, value string, values ...string) error {
+	for _, v := range values {
+		if v == value {
+			return nil
+		}
+	}
+
+	return &EnumError{Type: typeName, Value: value, Values: values}
+}
@@ -0,0 +1,27 @@
+package xsd
+
+import (
+	"encoding/hex"
+	"strings"
+)
+
+// HexBinary is an xsd:hexBinary value, written as two hex digits per byte
+// rather than as the raw text encoding/xml makes of a plain []byte.
+type HexBinary []byte
+
+func (b HexBinary) MarshalText() ([]byte, error) {
+	text := make([]byte, hex.EncodedLen(len(b)))
+	hex.Encode(text, b)
+
+	return []byte(strings.ToUpper(string(text))), nil
+}
+
+func (b *HexBinary) UnmarshalText(text []byte) error {
+	data, err := hex.DecodeString(strings.TrimSpace(string(text)))
+	if err != nil {
+		return err
+	}
+	*b = data
+
+	return nil
+}
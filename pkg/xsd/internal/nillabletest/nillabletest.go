@@ -0,0 +1,98 @@
+// Code generated by wsdl-example generate; DO NOT EDIT.
+
+//go:generate go run github.com/luhonghai/wsdl-example generate --wsdl nillabletest.wsdl --package nillabletest --out . --import github.com/luhonghai/wsdl-example/pkg/xsd/internal/nillabletest
+
+package nillabletest
+
+import (
+	"encoding/xml"
+	"time"
+
+	"github.com/luhonghai/wsdl-example/pkg/xsd"
+)
+
+// against "unused imports"
+var _ time.Time
+var _ xml.Name
+
+type Person struct {
+	XMLName xml.Name `xml:"urn:people person"`
+
+	Name     NillableString  `xml:"Name"`
+	Age      NillableInt32   `xml:"Age"`
+	Born     NillableDate    `xml:"Born"`
+	Address  NillableAddress `xml:"Address"`
+	Nickname *NillableString `xml:"Nickname,omitempty"`
+}
+
+type Address struct {
+	City string `xml:"City"`
+}
+
+// NillableString is the value of a nillable element; Nil stands for xsi:nil.
+type NillableString struct {
+	Value string
+	Nil   bool
+}
+
+func (n NillableString) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return xsd.MarshalNillable(e, start, n.Nil, n.Value)
+}
+
+func (n *NillableString) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var err error
+	n.Nil, err = xsd.UnmarshalNillable(d, start, &n.Value)
+
+	return err
+}
+
+// NillableInt32 is the value of a nillable element; Nil stands for xsi:nil.
+type NillableInt32 struct {
+	Value int32
+	Nil   bool
+}
+
+func (n NillableInt32) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return xsd.MarshalNillable(e, start, n.Nil, n.Value)
+}
+
+func (n *NillableInt32) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var err error
+	n.Nil, err = xsd.UnmarshalNillable(d, start, &n.Value)
+
+	return err
+}
+
+// NillableDate is the value of a nillable element; Nil stands for xsi:nil.
+type NillableDate struct {
+	Value xsd.Date
+	Nil   bool
+}
+
+func (n NillableDate) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return xsd.MarshalNillable(e, start, n.Nil, n.Value)
+}
+
+func (n *NillableDate) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var err error
+	n.Nil, err = xsd.UnmarshalNillable(d, start, &n.Value)
+
+	return err
+}
+
+// NillableAddress is the value of a nillable element; Nil stands for xsi:nil.
+type NillableAddress struct {
+	Value *Address
+	Nil   bool
+}
+
+func (n NillableAddress) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return xsd.MarshalNillable(e, start, n.Nil || n.Value == nil, n.Value)
+}
+
+func (n *NillableAddress) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var err error
+	n.Nil, err = xsd.UnmarshalNillable(d, start, &n.Value)
+
+	return err
+}
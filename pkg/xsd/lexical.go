@@ -0,0 +1,61 @@
+package xsd
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+)
+
+// CheckValue returns an error unless value is in the lexical space of the
+// built-in type named typeName, as in "int" or "dateTime". Types it does not
+// know, xsd:string among them, accept any value.
+func CheckValue(typeName, value string) error {
+	var err error
+	switch typeName {
+	case "boolean":
+		switch value {
+		case "true", "false", "1", "0":
+		default:
+			err = fmt.Errorf("%q is not a boolean", value)
+		}
+	case "byte":
+		_, err = strconv.ParseInt(value, 10, 8)
+	case "short":
+		_, err = strconv.ParseInt(value, 10, 16)
+	case "int":
+		_, err = strconv.ParseInt(value, 10, 32)
+	case "long", "integer":
+		_, err = strconv.ParseInt(value, 10, 64)
+	case "unsignedByte":
+		_, err = strconv.ParseUint(value, 10, 8)
+	case "unsignedShort":
+		_, err = strconv.ParseUint(value, 10, 16)
+	case "unsignedInt":
+		_, err = strconv.ParseUint(value, 10, 32)
+	case "unsignedLong":
+		_, err = strconv.ParseUint(value, 10, 64)
+	case "float", "double", "decimal":
+		_, err = strconv.ParseFloat(value, 64)
+	case "dateTime":
+		_, err = ParseDateTime(value)
+	case "date":
+		_, err = ParseDate(value)
+	case "time":
+		_, err = ParseTime(value)
+	case "duration":
+		_, err = ParseDuration(value)
+	case "base64Binary":
+		if new(Base64Binary).UnmarshalText([]byte(value)) != nil {
+			err = fmt.Errorf("%q is not valid base64", value)
+		}
+	case "hexBinary":
+		if _, hexErr := hex.DecodeString(value); hexErr != nil {
+			err = fmt.Errorf("%q is not valid hex", value)
+		}
+	}
+	if numErr, ok := err.(*strconv.NumError); ok {
+		err = fmt.Errorf("%q is not a valid %s", numErr.Num, typeName)
+	}
+
+	return err
+}
@@ -0,0 +1,52 @@
+package xsd
+
+import "encoding/xml"
+
+// InstanceNamespace is the namespace of the xsi: attributes instance
+// documents carry, such as xsi:type and xsi:nil.
+const InstanceNamespace = "http://www.w3.org/2001/XMLSchema-instance"
+
+// The generated types of nillable elements hold the value of the element
+// along with whether it is nil, and encode themselves with these functions.
+
+// MarshalNillable writes the element start holding value, or empty with
+// xsi:nil="true" when isNil is set.
+func MarshalNillable(e *xml.Encoder, start xml.StartElement, isNil bool, value interface{}) error {
+	if !isNil {
+		return e.EncodeElement(value, start)
+	}
+
+	// Spelled out with the usual xsi prefix, which encoding/xml would make
+	// up from the namespace otherwise.
+	start.Attr = append(start.Attr,
+		xml.Attr{Name: xml.Name{Local: "xmlns:xsi"}, Value: InstanceNamespace},
+		xml.Attr{Name: xml.Name{Local: "xsi:nil"}, Value: "true"})
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+
+	return e.EncodeToken(start.End())
+}
+
+// UnmarshalNillable reads the element start into value, a pointer, and
+// reports whether it is nil instead. The content of nil elements is
+// skipped: there is none to decode, and an empty date or number would not
+// parse.
+func UnmarshalNillable(d *xml.Decoder, start xml.StartElement, value interface{}) (bool, error) {
+	if IsNil(start) {
+		return true, d.Skip()
+	}
+
+	return false, d.DecodeElement(value, &start)
+}
+
+// IsNil reports whether start carries xsi:nil="true".
+func IsNil(start xml.StartElement) bool {
+	for _, attr := range start.Attr {
+		if attr.Name.Space == InstanceNamespace && attr.Name.Local == "nil" {
+			return attr.Value == "true" || attr.Value == "1"
+		}
+	}
+
+	return false
+}
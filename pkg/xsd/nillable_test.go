@@ -0,0 +1,84 @@
+package xsd_test
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/luhonghai/wsdl-example/pkg/xsd"
+	"github.com/luhonghai/wsdl-example/pkg/xsd/internal/nillabletest"
+	"github.com/magiconair/properties/assert"
+)
+
+// The wrappers tested are those the generator declares for nillable
+// elements, in the nillabletest package.
+
+const nilAttr = ` xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:nil="true"`
+
+func TestMarshalNillable(t *testing.T) {
+	out, err := xml.Marshal(&nillabletest.Person{
+		Name:    nillabletest.NillableString{Value: ""},
+		Age:     nillabletest.NillableInt32{Value: 0},
+		Born:    nillabletest.NillableDate{Value: xsd.NewDate(1990, 5, 17)},
+		Address: nillabletest.NillableAddress{Value: &nillabletest.Address{City: "Hanoi"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, string(out), `<person xmlns="urn:people"><Name></Name><Age>0</Age><Born>1990-05-17</Born>`+
+		"<Address><City>Hanoi</City></Address></person>")
+
+	out, err = xml.Marshal(&nillabletest.Person{
+		Name:     nillabletest.NillableString{Nil: true},
+		Age:      nillabletest.NillableInt32{Nil: true},
+		Born:     nillabletest.NillableDate{Nil: true},
+		Nickname: &nillabletest.NillableString{Nil: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, string(out), `<person xmlns="urn:people"><Name`+nilAttr+"></Name><Age"+nilAttr+"></Age><Born"+nilAttr+"></Born>"+
+		"<Address"+nilAttr+"></Address><Nickname"+nilAttr+"></Nickname></person>")
+
+	var back nillabletest.Person
+	if err := xml.Unmarshal(out, &back); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, back.Age.Nil && back.Born.Nil && back.Address.Nil && back.Nickname.Nil, true)
+}
+
+func TestUnmarshalNillable(t *testing.T) {
+	var p nillabletest.Person
+	err := xml.Unmarshal([]byte(`<person xmlns="urn:people" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance">
+  <Name xsi:nil="true"/>
+  <Age xsi:nil="1"></Age>
+  <Born xsi:nil="true"/>
+  <Address xsi:nil="true"/>
+  <Nickname>Bo</Nickname>
+</person>`), &p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, p.Name.Nil, true)
+	assert.Equal(t, p.Age.Nil, true)
+	assert.Equal(t, p.Born.Nil, true)
+	assert.Equal(t, p.Address.Nil, true)
+	assert.Equal(t, p.Nickname.Nil, false)
+	assert.Equal(t, p.Nickname.Value, "Bo")
+
+	p = nillabletest.Person{}
+	err = xml.Unmarshal([]byte(`<person xmlns="urn:people" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance">
+  <Name></Name>
+  <Age xsi:nil="false">42</Age>
+  <Born>1990-05-17</Born>
+  <Address><City>Hanoi</City></Address>
+</person>`), &p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, p.Name, nillabletest.NillableString{})
+	assert.Equal(t, p.Age, nillabletest.NillableInt32{Value: 42})
+	assert.Equal(t, p.Born.Nil, false)
+	assert.Equal(t, p.Born.Value.String(), "1990-05-17")
+	assert.Equal(t, p.Address.Value.City, "Hanoi")
+	assert.Equal(t, p.Nickname == nil, true)
+}
@@ -0,0 +1,21 @@
+package xsd
+
+// Optional elements are generated as pointers, nil when absent. These
+// helpers take the address of literal values and read values back.
+
+func String(v string) *string { return &v }
+
+func Bool(v bool) *bool { return &v }
+
+func Int32(v int32) *int32 { return &v }
+
+func Int64(v int64) *int64 { return &v }
+
+// StringValue returns the string p points to, or "" for nil.
+func StringValue(p *string) string {
+	if p == nil {
+		return ""
+	}
+
+	return *p
+}
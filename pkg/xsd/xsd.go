@@ -0,0 +1,3 @@
+// Package xsd holds Go types for XML Schema built-in datatypes whose lexical
+// form encoding/xml does not produce on its own.
+package xsd
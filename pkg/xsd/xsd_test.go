@@ -0,0 +1,49 @@
+package xsd
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/magiconair/properties/assert"
+)
+
+type blob struct {
+	XMLName xml.Name     `xml:"blob"`
+	Data    Base64Binary `xml:"Data,omitempty"`
+	Digest  HexBinary    `xml:"Digest,omitempty"`
+}
+
+func TestBase64Binary(t *testing.T) {
+	out, err := xml.Marshal(&blob{Data: []byte{0, 1, 2, 0xff}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, string(out), "<blob><Data>AAEC/w==</Data></blob>")
+
+	var in blob
+	if err := xml.Unmarshal([]byte("<blob><Data>\n  AAEC\n  /w==\n</Data></blob>"), &in); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []byte(in.Data), []byte{0, 1, 2, 0xff})
+
+	out, _ = xml.Marshal(&blob{})
+	assert.Equal(t, string(out), "<blob></blob>")
+}
+
+func TestHexBinary(t *testing.T) {
+	out, err := xml.Marshal(&blob{Digest: []byte{0, 1, 0xab, 0xff}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, string(out), "<blob><Digest>0001ABFF</Digest></blob>")
+
+	var in blob
+	if err := xml.Unmarshal([]byte("<blob><Digest> 0001abFF\n</Digest></blob>"), &in); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []byte(in.Digest), []byte{0, 1, 0xab, 0xff})
+
+	if err := xml.Unmarshal([]byte("<blob><Digest>0g</Digest></blob>"), &in); err == nil {
+		t.Error("expected an error for invalid hex")
+	}
+}